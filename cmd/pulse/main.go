@@ -14,7 +14,7 @@ func main() {
 	fmt.Println("Press Ctrl+C to stop")
 	fmt.Println()
 
-	config, err := loadEnv()
+	config, err := loadEnv("")
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
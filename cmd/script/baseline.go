@@ -0,0 +1,85 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ============================================================================
+// Baseline Comparison
+// BASELINE_PROVIDER: each provider's p50 and p95 head lag over the window as a
+// delta from the baseline's on the same chain, positive meaning slower
+// ============================================================================
+
+// BaselineDelta is one provider against the baseline on one chain, from one region
+type BaselineDelta struct {
+	Provider string  `json:"provider"`
+	Chain    string  `json:"chain"`
+	Region   string  `json:"region"`
+	P50Delta float64 `json:"p50_delta_ms"` // Provider p50 minus baseline p50
+	P95Delta float64 `json:"p95_delta_ms"`
+	Samples  int     `json:"samples"` // Provider samples behind p50 and p95, outliers excluded
+}
+
+// baselineDeltas compares every other ranked provider with the baseline, on the chains and regions
+// where both have at least STATS_MIN_SAMPLES samples in the window, sorted by chain, region then provider
+func baselineDeltas(stats []WindowStats, baseline string) []BaselineDelta {
+	reference := make(map[matrixRow]WindowStats)
+	for _, s := range stats {
+		if s.Provider == baseline && !s.Insufficient {
+			reference[matrixRow{s.Chain, s.Region}] = s
+		}
+	}
+
+	var deltas []BaselineDelta
+	for _, s := range stats {
+		if s.Provider == baseline || !isComparedProvider(s.Provider) || s.Insufficient {
+			continue
+		}
+		base, ok := reference[matrixRow{s.Chain, s.Region}]
+		if !ok {
+			continue
+		}
+		deltas = append(deltas, BaselineDelta{
+			Provider: s.Provider,
+			Chain:    s.Chain,
+			Region:   s.Region,
+			P50Delta: s.P50 - base.P50,
+			P95Delta: s.P95 - base.P95,
+			Samples:  s.Samples - s.Outliers,
+		})
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Chain != deltas[j].Chain {
+			return deltas[i].Chain < deltas[j].Chain
+		}
+		if deltas[i].Region != deltas[j].Region {
+			return deltas[i].Region < deltas[j].Region
+		}
+		return deltas[i].Provider < deltas[j].Provider
+	})
+	return deltas
+}
+
+// registerBaselineDeltas exports baseline_lag_delta_milliseconds from the /stats window
+func registerBaselineDeltas(window *LatencyWindow) {
+	prometheus.MustRegister(baselineDeltaCollector{window: window})
+}
+
+// baselineDeltaCollector exports baseline_lag_delta_milliseconds from the window, evaluated on scrape
+type baselineDeltaCollector struct {
+	window *LatencyWindow
+}
+
+func (baselineDeltaCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- baselineLagDelta
+}
+
+func (c baselineDeltaCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, d := range baselineDeltas(c.window.SnapshotAt(time.Now()), c.window.baseline) {
+		ch <- prometheus.MustNewConstMetric(baselineLagDelta, prometheus.GaugeValue, d.P50Delta, d.Provider, d.Chain, "p50", c.window.baseline, d.Region)
+		ch <- prometheus.MustNewConstMetric(baselineLagDelta, prometheus.GaugeValue, d.P95Delta, d.Provider, d.Chain, "p95", c.window.baseline, d.Region)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Block Timestamp Check
+// Compares the on-chain time providers report for sampled events against the
+// block timestamp from a node, since every lag number rests on that time
+// ============================================================================
+
+// blockTimestampQueueSize bounds the sampled events awaiting an RPC lookup
+const blockTimestampQueueSize = 64
+
+// blockTimestampTolerance is the skew beyond which a reported time counts as a mismatch;
+// EVM block timestamps only have second precision
+const blockTimestampTolerance = time.Second
+
+// BlockTimestampCheck is a Sink that looks up the block of one in every sampleRate events per
+// provider and chain over JSON-RPC, recording how far the provider's on-chain time is from it.
+// Only events carrying a block number, on chains with an RPC URL, are sampled.
+type BlockTimestampCheck struct {
+	rpcURLs    map[string]string // Chain -> JSON-RPC URL
+	sampleRate int
+	client     *http.Client
+
+	mu     sync.Mutex
+	events map[string]int // provider:chain -> events since the last sample
+
+	queue chan NormalizedTrade
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewBlockTimestampCheck starts the background RPC worker
+func NewBlockTimestampCheck(rpcURLs map[string]string, sampleRate int) *BlockTimestampCheck {
+	c := &BlockTimestampCheck{
+		rpcURLs:    rpcURLs,
+		sampleRate: sampleRate,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		events:     make(map[string]int),
+		queue:      make(chan NormalizedTrade, blockTimestampQueueSize),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go c.run()
+	return c
+}
+
+// Publish queues every sampleRate-th event of a provider on a chain, dropping it if the queue is full
+func (c *BlockTimestampCheck) Publish(trade NormalizedTrade) {
+	if trade.BlockNumber <= 0 || c.rpcURLs[trade.Chain] == "" {
+		return
+	}
+
+	c.mu.Lock()
+	key := trade.Provider + ":" + trade.Chain
+	c.events[key]++
+	sampled := c.events[key] >= c.sampleRate
+	if sampled {
+		c.events[key] = 0
+	}
+	c.mu.Unlock()
+	if !sampled {
+		return
+	}
+
+	select {
+	case c.queue <- trade:
+	default:
+		RecordSinkDropped("block_timestamp")
+	}
+}
+
+// Close stops the worker, leaving queued lookups undone so shutdown never waits on a node
+func (c *BlockTimestampCheck) Close() error {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+	<-c.done
+	return nil
+}
+
+func (c *BlockTimestampCheck) run() {
+	defer close(c.done)
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case trade := <-c.queue:
+			c.check(trade)
+		}
+	}
+}
+
+// check records the skew of one sampled event's reported time from its block's timestamp
+func (c *BlockTimestampCheck) check(trade NormalizedTrade) {
+	blockTime, err := c.fetchBlockTime(c.rpcURLs[trade.Chain], trade.BlockNumber)
+	if err != nil {
+		RecordProviderTimestampCheckError(trade.Provider, trade.Chain, trade.Region)
+		fmt.Printf("[BLOCK-TIMESTAMP][%s] Block %d lookup failed: %v\n", trade.Chain, trade.BlockNumber, err)
+		return
+	}
+
+	skew := trade.OnChainTime.Sub(blockTime)
+	mismatch := skew >= blockTimestampTolerance || skew <= -blockTimestampTolerance
+	RecordProviderTimestampSkew(trade.Provider, trade.Chain, float64(skew.Milliseconds()), mismatch, trade.Region)
+	if mismatch {
+		fmt.Printf("[BLOCK-TIMESTAMP][%s] %s reported block %d at %s, node says %s (skew %v)\n",
+			trade.Chain, trade.Provider, trade.BlockNumber,
+			trade.OnChainTime.UTC().Format(time.RFC3339), blockTime.UTC().Format(time.RFC3339), skew)
+	}
+}
+
+// fetchBlockTime returns the timestamp of an EVM block via eth_getBlockByNumber
+func (c *BlockTimestampCheck) fetchBlockTime(rpcURL string, blockNumber int64) (time.Time, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_getBlockByNumber",
+		"params":  []interface{}{"0x" + strconv.FormatInt(blockNumber, 16), false},
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.client.Post(rpcURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	var rpcResp struct {
+		Result *struct {
+			Timestamp string `json:"timestamp"`
+		} `json:"result"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return time.Time{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return time.Time{}, fmt.Errorf("rpc error: %s", rpcResp.Error.Message)
+	}
+	// A node behind the provider does not know the block yet
+	if rpcResp.Result == nil {
+		return time.Time{}, fmt.Errorf("block not found")
+	}
+
+	seconds, err := strconv.ParseInt(strings.TrimPrefix(rpcResp.Result.Timestamp, "0x"), 16, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid block timestamp %q", rpcResp.Result.Timestamp)
+	}
+	return time.Unix(seconds, 0), nil
+}
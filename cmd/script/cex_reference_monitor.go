@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ============================================================================
+// CEX Reference Monitor
+// Measures how fast Binance streams its own trades, as a baseline for what a
+// centralized feed achieves. Binance has no indexation step, so this is a
+// reference point, not a competitor in the head lag comparison.
+// ============================================================================
+
+// BinanceAggTradeMessage is an aggTrade event on Binance's combined stream endpoint
+type BinanceAggTradeMessage struct {
+	Stream string `json:"stream"`
+	Data   struct {
+		EventType string `json:"e"`
+		Symbol    string `json:"s"`
+		TradeTime int64  `json:"T"` // Matching engine time (ms)
+	} `json:"data"`
+}
+
+// binanceStreamPath builds the combined stream query for the configured symbols
+func binanceStreamPath(symbols []string) string {
+	streams := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		streams = append(streams, strings.ToLower(symbol)+"@aggTrade")
+	}
+	return providerURLs.BinanceStream + "?streams=" + strings.Join(streams, "/")
+}
+
+func runCEXReferenceMonitor(config *Config, rec Recorder, stopChan <-chan struct{}) {
+	if len(config.CEXReferenceSymbols) == 0 {
+		return
+	}
+	rec.RecordProviderStatus("binance", "cex-reference", "", config.MonitorRegion)
+
+	fmt.Println("Starting CEX reference monitor (Binance aggTrade)...")
+	fmt.Printf("   Symbols: %s\n", strings.Join(config.CEXReferenceSymbols, ", "))
+	fmt.Println("   Reference only: trade-to-receipt latency of a centralized feed, not indexation lag")
+	fmt.Println()
+
+	ctx, cancel := stopContext(stopChan)
+	defer cancel()
+
+	reconnectDelay := 5 * time.Second
+	maxReconnectDelay := 60 * time.Second
+
+	for {
+		select {
+		case <-stopChan:
+			fmt.Println("CEX reference monitor stopped")
+			return
+		default:
+			err := connectAndMonitorBinance(ctx, config, rec)
+			if err != nil {
+				log.Printf("[CEX-REF] Connection error: %v. Reconnecting in %v...", err, reconnectDelay)
+				if !backoffOrStop(rec, "cex-reference", stopChan, reconnectDelay, config.MonitorRegion) {
+					continue
+				}
+				reconnectDelay = min(reconnectDelay*2, maxReconnectDelay)
+			} else {
+				reconnectDelay = 5 * time.Second
+			}
+		}
+	}
+}
+
+func connectAndMonitorBinance(ctx context.Context, config *Config, rec Recorder) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, binanceStreamPath(config.CEXReferenceSymbols), nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("dial failed: %w", err)
+	}
+	defer conn.Close()
+
+	// Unblock the read below on shutdown instead of waiting for the next message
+	defer context.AfterFunc(ctx, func() { conn.Close() })()
+
+	// Binance pings every few minutes; the default handler answers with a pong
+	readDeadline := config.ReadDeadlines["binance"]
+	for {
+		setReadDeadline(conn, readDeadline)
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			errorType := classifyWebSocketError(err)
+			rec.RecordWebSocketError("cex-reference", errorType, config.MonitorRegion)
+			return fmt.Errorf("read failed (%s): %w", errorType, err)
+		}
+		receiveTime := time.Now().UTC()
+		rec.RecordMessageReceived("cex-reference", config.MonitorRegion)
+		rec.RecordMonitorSuccess("binance", "cex-reference", config.MonitorRegion)
+
+		var msg BinanceAggTradeMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			recordParseFailure(rec, "cex-reference", message, err, config.MonitorRegion)
+			continue
+		}
+		if msg.Data.EventType != "aggTrade" {
+			continue
+		}
+		if msg.Data.TradeTime == 0 {
+			rec.RecordMissingTimestamp("cex-reference", config.MonitorRegion)
+			continue
+		}
+
+		latencyMs := receiveTime.Sub(time.UnixMilli(msg.Data.TradeTime)).Milliseconds()
+		rec.RecordCEXReferenceLatency("binance", msg.Data.Symbol, float64(latencyMs), config.MonitorRegion)
+
+		if sampleTradeLog("cex-reference") {
+			fmt.Printf("[CEX-REF][BINANCE][%s][%s] Latency: %dms\n", receiveTime.Format("15:04:05"), msg.Data.Symbol, latencyMs)
+		}
+	}
+}
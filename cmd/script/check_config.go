@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// ============================================================================
+// check-config
+// Dry run: load the config, report what would run and probe each endpoint once
+// ============================================================================
+
+// configCheck collects the outcome of a check-config run
+type configCheck struct {
+	critical int
+	warnings int
+}
+
+func (c *configCheck) ok(format string, args ...interface{}) {
+	fmt.Printf("  ✓ %s\n", fmt.Sprintf(format, args...))
+}
+
+func (c *configCheck) warn(format string, args ...interface{}) {
+	c.warnings++
+	fmt.Printf("  ⚠ %s\n", fmt.Sprintf(format, args...))
+}
+
+func (c *configCheck) fail(format string, args ...interface{}) {
+	c.critical++
+	fmt.Printf("  ✗ %s\n", fmt.Sprintf(format, args...))
+}
+
+// runCheckConfig validates the config the way startup does and probes every enabled endpoint.
+// It returns the process exit code: non-zero if anything critical is misconfigured.
+func runCheckConfig(envFile string) int {
+	fmt.Println("=== Config Check ===")
+	fmt.Println()
+
+	config, err := loadEnv(envFile)
+	if err != nil {
+		fmt.Printf("  ✗ %v\n", err)
+		return 1
+	}
+	initProviderURLs(config.ProviderURLs)
+	logConfigSources(config)
+	fmt.Println()
+
+	check := &configCheck{}
+
+	fmt.Println("Providers:")
+	checkMobula(check, config)
+	checkCodex(check, config)
+	checkGeckoTerminal(check)
+	checkGoldRush(check, config)
+	checkQuoteAPIs(check, config)
+	fmt.Println()
+
+	fmt.Println("Sinks:")
+	checkSinks(check, config)
+	fmt.Println()
+
+	if check.critical > 0 {
+		fmt.Printf("Config check failed: %d critical issue(s), %d warning(s)\n", check.critical, check.warnings)
+		return 1
+	}
+	fmt.Printf("Config check passed with %d warning(s)\n", check.warnings)
+	return 0
+}
+
+// chainsSupportedBy lists the registry chains a provider covers
+func chainsSupportedBy(supported func(ChainInfo) bool) string {
+	return strings.Join(supportedChainNames(supported), ", ")
+}
+
+func checkMobula(check *configCheck, config *Config) {
+	if config.MobulaAPIKey == "" {
+		check.warn("Mobula: disabled (MOBULA_API_KEY not set)")
+		if len(config.Tokens) > 0 {
+			check.fail("TOKENS: set but cannot be resolved without MOBULA_API_KEY")
+		}
+		return
+	}
+
+	pools := monitoredPools()
+	latencyMs, statusCode, err := callMobulaMarketDataAPI(config.MobulaAPIKey, pools[0].Address, pools[0].BlockchainID, pools[0].ChainName, config.MobulaHistoryWindow, config.MobulaHistoryPeriod)
+	switch {
+	case err != nil:
+		check.fail("Mobula: probe failed: %v", err)
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		check.fail("Mobula: MOBULA_API_KEY rejected (HTTP %d)", statusCode)
+	case statusCode >= 400:
+		check.warn("Mobula: probe returned HTTP %d", statusCode)
+	default:
+		check.ok("Mobula: reachable in %.0fms | chains: %s", latencyMs, chainsSupportedBy(ChainInfo.SupportsMobula))
+	}
+}
+
+func checkCodex(check *configCheck, config *Config) {
+	if config.DefinedSessionCookie == "" {
+		check.warn("Codex: disabled (DEFINED_SESSION_COOKIE not set)")
+		return
+	}
+
+	if _, err := GetDefinedJWTToken(context.Background(), config.DefinedSessionCookie); err != nil {
+		check.fail("Codex: could not get a JWT from DEFINED_SESSION_COOKIE: %v", err)
+		return
+	}
+	check.ok("Codex: session valid | chains: %s", chainsSupportedBy(ChainInfo.SupportsCodex))
+}
+
+func checkGeckoTerminal(check *configCheck) {
+	headers := map[string][]string{
+		"Origin":     {geckoOrigin},
+		"User-Agent": {geckoUserAgent},
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(providerURLs.GeckoTerminalWS, headers)
+	if err != nil {
+		check.warn("GeckoTerminal: WebSocket unreachable: %v", err)
+		return
+	}
+	conn.Close()
+
+	var chains []string
+	for _, pool := range geckoTerminalPools {
+		chains = append(chains, pool.Chain)
+	}
+	check.ok("GeckoTerminal: reachable | chains: %s", strings.Join(chains, ", "))
+}
+
+func checkGoldRush(check *configCheck, config *Config) {
+	if config.GoldRushAPIKey == "" {
+		check.warn("GoldRush: disabled (GOLDRUSH_API_KEY not set)")
+		return
+	}
+
+	latencyMs, statusCode, err := callGoldRushAPI(config.GoldRushAPIKey, "balances", monitoredPools()[0].Address, ethereumChain.GoldRushChain, ethereumChain.ChainName)
+	switch {
+	case err != nil:
+		check.fail("GoldRush: probe failed: %v", err)
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		check.fail("GoldRush: GOLDRUSH_API_KEY rejected (HTTP %d)", statusCode)
+	case statusCode >= 400:
+		check.warn("GoldRush: probe returned HTTP %d", statusCode)
+	default:
+		check.ok("GoldRush: reachable in %.0fms | chains: %s", latencyMs, chainsSupportedBy(ChainInfo.SupportsGoldRush))
+	}
+}
+
+// checkQuoteAPIs probes each free quote API once on the first EVM chain
+func checkQuoteAPIs(check *configCheck, config *Config) {
+	chain := evmQuoteChains[0]
+	probes := []struct {
+		name string
+		call func(QuoteChainConfig) (float64, int, error)
+	}{
+		{"openocean", func(chain QuoteChainConfig) (float64, int, error) {
+			return callOpenOceanQuoteAPI(chain, config.QuoteSlippageBps)
+		}},
+		{"paraswap", callParaSwapQuoteAPI},
+		{"lifi", func(chain QuoteChainConfig) (float64, int, error) {
+			return callLifiQuoteAPI(chain, config.QuoteSlippageBps)
+		}},
+		{"kyberswap", callKyberSwapQuoteAPI},
+	}
+
+	for _, probe := range probes {
+		latencyMs, statusCode, err := probe.call(chain)
+		if err != nil || statusCode >= 400 {
+			check.warn("Quote API %s: probe failed on %s (status %d, err: %v)", probe.name, chain.Name, statusCode, err)
+			continue
+		}
+		check.ok("Quote API %s: reachable in %.0fms", probe.name, latencyMs)
+	}
+}
+
+// checkSinks builds the sinks exactly as startup does, then probes the ones that support it
+func checkSinks(check *configCheck, config *Config) {
+	sinks, err := newSinks(config)
+	if err != nil {
+		check.fail("%v", err)
+		return
+	}
+	if len(sinks) == 0 {
+		check.ok("none configured")
+		return
+	}
+
+	for _, sink := range sinks {
+		name := sinkName(sink)
+		if prober, ok := sink.(Prober); ok {
+			if err := prober.Probe(); err != nil {
+				check.fail("%s: %v", name, err)
+			} else {
+				check.ok("%s: reachable", name)
+			}
+		} else {
+			check.ok("%s: started", name)
+		}
+		sink.Close()
+	}
+}
+
+func sinkName(sink Sink) string {
+	switch sink.(type) {
+	case *RedisSink:
+		return "Redis"
+	case *KafkaSink:
+		return "Kafka"
+	case *GRPCServer:
+		return "gRPC"
+	default:
+		return fmt.Sprintf("%T", sink)
+	}
+}
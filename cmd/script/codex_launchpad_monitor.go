@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Codex Launchpad Feed
+// Streams newly deployed launchpad tokens from Codex so they race Mobula Pulse's,
+// see RecordLaunchpadDiscovery
+// ============================================================================
+
+const (
+	codexLaunchpadSubscriptionID = "launchpad"
+	codexLaunchpadDeployed       = "Deployed" // Event type of a token's launch, later events update it
+)
+
+var codexLaunchpadSubscription = map[string]interface{}{
+	"query": `subscription OnLaunchpadTokenEventBatch {
+		onLaunchpadTokenEventBatch(input: { eventType: Deployed }) {
+			address
+			networkId
+			eventType
+			launchpadName
+		}
+	}`,
+}
+
+type CodexLaunchpadEvent struct {
+	Address       string `json:"address"`
+	NetworkID     int    `json:"networkId"`
+	EventType     string `json:"eventType"`
+	LaunchpadName string `json:"launchpadName"`
+}
+
+type CodexLaunchpadData struct {
+	Data struct {
+		OnLaunchpadTokenEventBatch []CodexLaunchpadEvent `json:"onLaunchpadTokenEventBatch"`
+	} `json:"data"`
+}
+
+func runCodexLaunchpadMonitor(config *Config, rec Recorder, stopChan <-chan struct{}) {
+	if config.DefinedSessionCookie == "" {
+		rec.RecordProviderStatus("codex", "launchpad", skipReasonMissingDefinedCookie, config.MonitorRegion)
+		fmt.Println("[CODEX-LAUNCHPAD] DEFINED_SESSION_COOKIE not set, skipping")
+		return
+	}
+	rec.RecordProviderStatus("codex", "launchpad", "", config.MonitorRegion)
+	fmt.Println("[CODEX-LAUNCHPAD] Starting WebSocket monitor (recorded as codex-launchpad)...")
+
+	ctx, cancel := stopContext(stopChan)
+	defer cancel()
+
+	reconnectDelay := 5 * time.Second
+	maxReconnectDelay := 5 * time.Minute
+
+	for {
+		select {
+		case <-stopChan:
+			fmt.Println("[CODEX-LAUNCHPAD] Monitor stopped")
+			return
+		default:
+			err := connectAndMonitorCodexLaunchpad(ctx, config, rec, stopChan)
+			if err != nil {
+				log.Printf("[CODEX-LAUNCHPAD] Connection error: %v", err)
+				if strings.Contains(err.Error(), "authentication") || strings.Contains(err.Error(), "401") {
+					log.Printf("[CODEX-LAUNCHPAD] Authentication error - invalidating token cache")
+					RecordAuthFailure("codex", "launchpad")
+					InvalidateTokenCache()
+				}
+
+				log.Printf("[CODEX-LAUNCHPAD] Reconnecting in %v...", reconnectDelay)
+				if !backoffOrStop(rec, "codex-launchpad", stopChan, reconnectDelay, config.MonitorRegion) {
+					return
+				}
+				reconnectDelay = reconnectDelay * 2
+				if reconnectDelay > maxReconnectDelay {
+					reconnectDelay = maxReconnectDelay
+				}
+			} else {
+				reconnectDelay = 5 * time.Second
+			}
+		}
+	}
+}
+
+func connectAndMonitorCodexLaunchpad(ctx context.Context, config *Config, rec Recorder, stopChan <-chan struct{}) error {
+	conn, err := dialCodexWS(ctx, config.DefinedSessionCookie)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		recordWebSocketWriteError(rec, "codex-launchpad", err, config.MonitorRegion)
+		return err
+	}
+	defer conn.Close()
+
+	// Unblock the read below on shutdown instead of waiting for the next message
+	defer context.AfterFunc(ctx, func() { conn.Close() })()
+
+	if err := writeWebSocket(conn, wsWriteSubscribe, map[string]interface{}{
+		"type":    "subscribe",
+		"id":      codexLaunchpadSubscriptionID,
+		"payload": codexLaunchpadSubscription,
+	}); err != nil {
+		recordWebSocketWriteError(rec, "codex-launchpad", err, config.MonitorRegion)
+		return fmt.Errorf("launchpad subscribe failed: %w", err)
+	}
+	fmt.Println("[CODEX-LAUNCHPAD] Subscribed to launchpad token deployments")
+
+	// graphql-transport-ws pings are answered with a pong, which keeps a quiet feed within the read deadline
+	readDeadline := config.ReadDeadlines["codex-launchpad"]
+	pingDone := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(keepaliveInterval(readDeadline))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pingDone:
+				return
+			case <-ticker.C:
+				if err := writeWebSocket(conn, wsWritePing, map[string]string{"type": "ping"}); err != nil {
+					recordWebSocketWriteError(rec, "codex-launchpad", err, config.MonitorRegion)
+					return
+				}
+			}
+		}
+	}()
+	defer close(pingDone)
+
+	for {
+		select {
+		case <-stopChan:
+			return nil
+		default:
+			setReadDeadline(conn.Conn, readDeadline)
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				errorType := classifyWebSocketError(err)
+				rec.RecordWebSocketError("codex-launchpad", errorType, config.MonitorRegion)
+				return fmt.Errorf("read failed (%s): %w", errorType, err)
+			}
+			receiveTime := time.Now().UTC()
+			rec.RecordMessageReceived("codex-launchpad", config.MonitorRegion)
+			rec.RecordMonitorSuccess("codex", "launchpad", config.MonitorRegion)
+
+			if err := handleCodexLaunchpadMessage(config, rec, message, receiveTime); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handleCodexLaunchpadMessage reports each token deployment in a Codex frame to the launchpad race.
+// A rejected subscription leaves the connection with nothing to stream, so it is returned as an error.
+func handleCodexLaunchpadMessage(config *Config, rec Recorder, message []byte, receiveTime time.Time) error {
+	var wsMsg CodexWSMessage
+	if err := json.Unmarshal(message, &wsMsg); err != nil {
+		recordParseFailure(rec, "codex-launchpad", message, err, config.MonitorRegion)
+		return nil
+	}
+
+	if wsMsg.Type == "error" && wsMsg.ID == codexLaunchpadSubscriptionID {
+		return fmt.Errorf("launchpad subscription rejected: %s", string(wsMsg.Payload))
+	}
+	if wsMsg.Type != "next" || len(wsMsg.Payload) == 0 {
+		return nil
+	}
+
+	var data CodexLaunchpadData
+	if err := json.Unmarshal(wsMsg.Payload, &data); err != nil {
+		recordParseFailure(rec, "codex-launchpad", message, err, config.MonitorRegion)
+		return nil
+	}
+
+	for _, event := range data.Data.OnLaunchpadTokenEventBatch {
+		if event.EventType != codexLaunchpadDeployed || event.Address == "" {
+			continue
+		}
+		// Tokens on networks outside the registry could never be matched against Mobula Pulse
+		chain, ok := lookupNetworkID(event.NetworkID)
+		if !ok || !chainEnabled("codex", chain.ChainName) {
+			continue
+		}
+		rec.RecordLaunchpadDiscovery("codex-launchpad", chain.ChainName, event.Address, receiveTime, config.MonitorRegion)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Only deployments on registry networks reach the launchpad race, under the chain name Pulse uses
+func TestCodexLaunchpadRecordsDeployments(t *testing.T) {
+	frame := `{"type":"next","id":"launchpad","payload":{"data":{"onLaunchpadTokenEventBatch":[
+		{"address":"PumpToken111","networkId":1399811149,"eventType":"Deployed","launchpadName":"Pump.fun"},
+		{"address":"PumpToken222","networkId":1399811149,"eventType":"Updated","launchpadName":"Pump.fun"},
+		{"address":"0xabc","networkId":424242,"eventType":"Deployed","launchpadName":"Unknown"},
+		{"address":"0x0000000000000000000000000000000000000b01","networkId":8453,"eventType":"Deployed","launchpadName":"Zora"}
+	]}}}`
+
+	rec := &fakeRecorder{}
+	if err := handleCodexLaunchpadMessage(&Config{MonitorRegion: "eu"}, rec, []byte(frame), time.Now()); err != nil {
+		t.Fatalf("handleCodexLaunchpadMessage: %v", err)
+	}
+
+	want := []string{
+		"codex-launchpad solana PumpToken111 eu",
+		"codex-launchpad base 0x0000000000000000000000000000000000000b01 eu",
+	}
+	if got := rec.calls("RecordLaunchpadDiscovery"); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("RecordLaunchpadDiscovery calls = %q, want %q", got, want)
+	}
+}
+
+// A rejected subscription ends the connection so the feed resubscribes instead of staying silent
+func TestCodexLaunchpadRejectedSubscription(t *testing.T) {
+	frame := `{"type":"error","id":"launchpad","payload":[{"message":"Cannot query field"}]}`
+	err := handleCodexLaunchpadMessage(&Config{MonitorRegion: "eu"}, &fakeRecorder{}, []byte(frame), time.Now())
+	if err == nil || !strings.Contains(err.Error(), "Cannot query field") {
+		t.Errorf("handleCodexLaunchpadMessage error = %v, want the rejection", err)
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -12,41 +14,61 @@ import (
 )
 
 const (
-	codexRESTBaseURL = "https://graph.codex.io/graphql"
+	// getBars data-quality probe, over CODEX_BARS_WINDOW at CODEX_BARS_RESOLUTION
+	codexBarsEndpoint = "getBars"
 )
 
-// Chains for REST monitoring - aligned with all monitors
-var codexRESTChains = []struct {
-	networkID   int
-	chainName   string
-	poolAddress string
-}{
-	{1399811149, "solana", "7qbRF6YsyGuLUVs6Y1q64bdVrfe4ZcUUz1JRdoVNUJnm"}, // SOL/USDC Raydium
-	{1, "ethereum", "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"},           // WETH/USDC Uniswap V3
-	{8453, "base", "0x4c36388be6f416a29c8d8eee81c771ce6be14b18"},            // WETH/USDC Base
-	{56, "bnb", "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16"},               // WBNB/BUSD PancakeSwap
-	{42161, "arbitrum", "0xc6962004f452be9203591991d15f6b388e09e8d0"},       // WETH/USDC Uniswap V3 Arbitrum
+// codexBarResolutions are the bar resolutions CODEX_BARS_RESOLUTION accepts, by Codex's name
+var codexBarResolutions = map[string]time.Duration{
+	"1S":  time.Second,
+	"5S":  5 * time.Second,
+	"15S": 15 * time.Second,
+	"30S": 30 * time.Second,
+	"1":   time.Minute,
+	"5":   5 * time.Minute,
+	"15":  15 * time.Minute,
+	"30":  30 * time.Minute,
+	"60":  time.Hour,
+	"240": 4 * time.Hour,
+	"720": 12 * time.Hour,
+	"1D":  24 * time.Hour,
 }
 
+var (
+	// errCodexGraphQL is a query answered with GraphQL errors instead of data
+	errCodexGraphQL = errors.New("graphql error")
+	// errCodexAuth is a query rejected because the JWT token is no longer valid
+	errCodexAuth = errors.New("authentication error")
+)
+
+// codexUnauthenticatedMessage is the GraphQL error Codex answers an expired or revoked JWT with
+const codexUnauthenticatedMessage = "User is not authenticated"
+
 type CodexGraphQLRequest struct {
 	Query     string                 `json:"query"`
 	Variables map[string]interface{} `json:"variables"`
 }
 
 type CodexGraphQLResponse struct {
-	Data   map[string]interface{} `json:"data"`
+	Data   json.RawMessage `json:"data"`
 	Errors []struct {
 		Message string `json:"message"`
 	} `json:"errors"`
 }
 
+// codexRESTEndpoints are the latency probes CODEX_REST_ENDPOINTS can select, by endpoint label.
+// codexBarsEndpoint is also accepted and additionally checks data quality, see checkCodexBars.
+var codexRESTEndpoints = map[string]func(apiKey string, pool MonitoredPool) (float64, int, error){
+	"graphql": func(apiKey string, pool MonitoredPool) (float64, int, error) {
+		return callCodexGraphQLAPI(apiKey, pool.AddressFor("codex"), pool.NetworkID, pool.ChainName)
+	},
+	"pairMetadata": func(apiKey string, pool MonitoredPool) (float64, int, error) {
+		return callCodexPairMetadataAPI(apiKey, pool.AddressFor("codex"), pool.NetworkID, pool.ChainName)
+	},
+}
+
 // callCodexGraphQLAPI makes a GraphQL query to Codex API
 func callCodexGraphQLAPI(apiKey string, poolAddress string, networkID int, chainName string) (float64, int, error) {
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
 	// Build GraphQL query - filterPairs is reliable and works for all chains
 	// This query filters pairs by network and returns one result to measure latency
 	query := `
@@ -63,12 +85,41 @@ func callCodexGraphQLAPI(apiKey string, poolAddress string, networkID int, chain
 		}
 	`
 
+	return callCodexGraphQLQuery(apiKey, query, map[string]interface{}{
+		"networkId": []int{networkID},
+	}, chainName, nil)
+}
+
+// callCodexPairMetadataAPI queries Codex pairMetadata for a single pool
+func callCodexPairMetadataAPI(apiKey string, poolAddress string, networkID int, chainName string) (float64, int, error) {
+	query := `
+		query PairMetadata($pairId: String!) {
+			pairMetadata(pairId: $pairId) {
+				id
+				price
+			}
+		}
+	`
+
+	return callCodexGraphQLQuery(apiKey, query, map[string]interface{}{
+		"pairId": fmt.Sprintf("%s:%d", poolAddress, networkID),
+	}, chainName, nil)
+}
+
+// callCodexGraphQLQuery posts a GraphQL query to Codex and measures its latency.
+// A response carrying GraphQL errors fails with errCodexAuth or errCodexGraphQL, so it is
+// never measured as a successful call. When data is not nil, the response's data is decoded
+// into it and an unparseable response fails the call.
+func callCodexGraphQLQuery(apiKey string, query string, variables map[string]interface{}, chainName string, data interface{}) (float64, int, error) {
+	// Create HTTP client with timeout
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
 	// Build request body with variables
 	reqBody := CodexGraphQLRequest{
-		Query: query,
-		Variables: map[string]interface{}{
-			"networkId": []int{networkID},
-		},
+		Query:     query,
+		Variables: variables,
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
@@ -77,7 +128,7 @@ func callCodexGraphQLAPI(apiKey string, poolAddress string, networkID int, chain
 	}
 
 	// Build request
-	req, err := http.NewRequest("POST", codexRESTBaseURL, bytes.NewBuffer(bodyBytes))
+	req, err := http.NewRequest("POST", providerURLs.CodexGraphQL, bytes.NewBuffer(bodyBytes))
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -102,6 +153,9 @@ func callCodexGraphQLAPI(apiKey string, poolAddress string, networkID int, chain
 	// Try to parse response
 	var graphqlResp CodexGraphQLResponse
 	if err := json.Unmarshal(body, &graphqlResp); err != nil {
+		if data != nil {
+			return latencyMs, resp.StatusCode, fmt.Errorf("response parse failed: %w", err)
+		}
 		log.Printf("[CODEX-REST][%s] Response parse warning: %v (status: %d)", chainName, err, resp.StatusCode)
 	}
 
@@ -110,55 +164,185 @@ func callCodexGraphQLAPI(apiKey string, poolAddress string, networkID int, chain
 		log.Printf("[CODEX-REST][%s] GraphQL errors: %v", chainName, graphqlResp.Errors[0].Message)
 
 		// Check if it's an authentication error
-		if graphqlResp.Errors[0].Message == "User is not authenticated" {
-			return latencyMs, resp.StatusCode, fmt.Errorf("authentication error: %s", graphqlResp.Errors[0].Message)
+		if graphqlResp.Errors[0].Message == codexUnauthenticatedMessage {
+			return latencyMs, resp.StatusCode, fmt.Errorf("%w: %s", errCodexAuth, graphqlResp.Errors[0].Message)
 		}
+		return latencyMs, resp.StatusCode, fmt.Errorf("%w: %s", errCodexGraphQL, graphqlResp.Errors[0].Message)
 	}
 
+	if data != nil && len(graphqlResp.Data) > 0 {
+		if err := json.Unmarshal(graphqlResp.Data, data); err != nil {
+			return latencyMs, resp.StatusCode, fmt.Errorf("response parse failed: %w", err)
+		}
+	}
 	return latencyMs, resp.StatusCode, nil
 }
 
+// callCodexGetBars fetches the last window of bars for a pool and returns their
+// timestamps (Unix seconds) alongside the latency and status code
+func callCodexGetBars(apiKey string, poolAddress string, networkID int, chainName string, window time.Duration, resolution string) (float64, int, []int64, error) {
+	query := `
+		query GetBars($symbol: String!, $from: Int!, $to: Int!, $resolution: String!) {
+			getBars(symbol: $symbol, from: $from, to: $to, resolution: $resolution) {
+				t
+			}
+		}
+	`
+
+	to := time.Now()
+	var data struct {
+		GetBars *struct {
+			T []int64 `json:"t"`
+		} `json:"getBars"`
+	}
+	latencyMs, statusCode, err := callCodexGraphQLQuery(apiKey, query, map[string]interface{}{
+		"symbol":     fmt.Sprintf("%s:%d", poolAddress, networkID),
+		"from":       to.Add(-window).Unix(),
+		"to":         to.Unix(),
+		"resolution": resolution,
+	}, chainName, &data)
+	if err != nil || data.GetBars == nil {
+		return latencyMs, statusCode, nil, err
+	}
+	return latencyMs, statusCode, data.GetBars.T, nil
+}
+
+// checkCodexBars probes getBars for a pool, records how current and complete the bars are and
+// returns the call's error. An empty bar set counts as an error and as maximum staleness.
+func checkCodexBars(config *Config, rec Recorder, stopChan <-chan struct{}, jwtToken string, pool MonitoredPool, timestamp string) error {
+	window := config.CodexBarsWindow
+	expected := int(window / codexBarResolutions[config.CodexBarsResolution])
+
+	var bars []int64
+	latencyMs, statusCode, err := withRESTRetry(stopChan, config.RESTRetries, func() (float64, int, error) {
+		latencyMs, statusCode, t, err := callCodexGetBars(jwtToken, pool.AddressFor("codex"), pool.NetworkID, pool.ChainName, window, config.CodexBarsResolution)
+		bars = t
+		return latencyMs, statusCode, err
+	})
+
+	if err == nil && statusCode >= 400 {
+		err = fmt.Errorf("status %d", statusCode)
+	}
+	if err != nil {
+		rec.RecordRESTError("codex", codexBarsEndpoint, pool.ChainName, codexRESTErrorType(statusCode, err), config.MonitorRegion)
+		fmt.Printf("[CODEX-REST][%s][%s] getBars ERROR | Latency: %.0fms | Status: %d | Error: %v\n",
+			timestamp, pool.ChainName, latencyMs, statusCode, err)
+		return err
+	}
+	rec.RecordRESTLatency("codex", codexBarsEndpoint, pool.ChainName, latencyMs, statusCode, config.MonitorRegion)
+	rec.RecordMonitorSuccess("codex", "rest", config.MonitorRegion)
+
+	if len(bars) == 0 {
+		rec.RecordRESTError("codex", codexBarsEndpoint, pool.ChainName, "empty_bars", config.MonitorRegion)
+		rec.RecordRESTDataQuality("codex", codexBarsEndpoint, pool.ChainName, window.Seconds(), 0, config.MonitorRegion)
+		fmt.Printf("[CODEX-REST][%s][%s] getBars returned no bars for the last %v\n", timestamp, pool.ChainName, window)
+		return nil
+	}
+
+	latest := bars[0]
+	for _, t := range bars {
+		latest = max(latest, t)
+	}
+	// A bar opening after now means Codex's clock or ours is off; such a freshness would be nonsense
+	freshness := time.Since(time.Unix(latest, 0)).Seconds()
+	if freshness < 0 {
+		rec.RecordClockAnomaly("codex", codexBarsEndpoint, pool.ChainName, config.MonitorRegion)
+		if currentDebug() {
+			fmt.Printf("[DEBUG][CODEX-REST][%s] getBars latest bar %d is %.0fs ahead of the local clock, freshness not recorded\n", pool.ChainName, latest, -freshness)
+		}
+		return nil
+	}
+	completeness := min(float64(len(bars))/float64(expected), 1)
+
+	rec.RecordRESTDataQuality("codex", codexBarsEndpoint, pool.ChainName, freshness, completeness, config.MonitorRegion)
+	fmt.Printf("[CODEX-REST][%s][%s] getBars | Latency: %.0fms | Latest bar: %.0fs ago | Bars: %d/%d\n",
+		timestamp, pool.ChainName, latencyMs, freshness, len(bars), expected)
+	return nil
+}
+
 // monitorCodexREST continuously monitors Codex GraphQL API latency
-func monitorCodexREST(config *Config, stopChan <-chan struct{}) {
+func monitorCodexREST(config *Config, rec Recorder, stopChan <-chan struct{}) {
 	fmt.Println("Starting Codex REST API monitor...")
-	fmt.Printf("   Monitoring %d chains with 20s interval\n", len(codexRESTChains))
-	fmt.Printf("   Endpoint: POST /graphql (GraphQL)\n")
+	fmt.Printf("   Monitoring %d pools with %v interval\n", len(monitoredPools()), currentRESTInterval())
+	fmt.Printf("   Endpoints: %s (POST /graphql)\n", strings.Join(config.CodexRESTEndpoints, ", "))
+	logSkippedChains("[CODEX-REST]", "codex", ChainInfo.SupportsCodex)
 	fmt.Println()
 
 	if config.DefinedSessionCookie == "" {
+		rec.RecordProviderStatus("codex", "rest", skipReasonMissingDefinedCookie, config.MonitorRegion)
 		fmt.Println("DEFINED_SESSION_COOKIE not set in .env file. Skipping Codex REST monitor.")
 		return
 	}
+	rec.RecordProviderStatus("codex", "rest", "", config.MonitorRegion)
+
+	ctx, cancel := stopContext(stopChan)
+	defer cancel()
 
-	// Create ticker for 20 second intervals
-	ticker := time.NewTicker(20 * time.Second)
+	ticker := time.NewTicker(currentRESTInterval())
 	defer ticker.Stop()
 
+	warmup := newRESTWarmup(config)
+
 	// Run once immediately
-	performCodexRESTChecks(config)
+	if warmup.due(time.Now()) {
+		warmUpCodexREST(ctx, config)
+	}
+	performCodexRESTChecks(ctx, config, rec)
 
-	// Then run every 20 seconds
+	// Then run on every tick, picking up interval changes from SIGHUP reloads
 	for {
 		select {
 		case <-stopChan:
 			fmt.Println("Codex REST monitor stopped")
 			return
 		case <-ticker.C:
-			performCodexRESTChecks(config)
+			if warmup.due(time.Now()) {
+				warmUpCodexREST(ctx, config)
+			}
+			performCodexRESTChecks(ctx, config, rec)
+			ticker.Reset(currentRESTInterval())
 		}
 	}
 }
 
+// warmUpCodexREST sends one discarded query per endpoint, for the first pool Codex covers.
+// Without a JWT there is nothing to send; the check cycle reports that failure itself.
+func warmUpCodexREST(ctx context.Context, config *Config) {
+	jwtToken, err := GetDefinedJWTToken(ctx, config.DefinedSessionCookie)
+	if err != nil {
+		return
+	}
+
+	warmUpEndpoints("[CODEX-REST]", config.CodexRESTEndpoints, func(endpoint string) bool {
+		for _, pool := range monitoredPools() {
+			if !pool.SupportsCodex() || !chainEnabled("codex", pool.ChainName) {
+				continue
+			}
+			if endpoint == codexBarsEndpoint {
+				callCodexGetBars(jwtToken, pool.AddressFor("codex"), pool.NetworkID, pool.ChainName, config.CodexBarsWindow, config.CodexBarsResolution)
+			} else {
+				codexRESTEndpoints[endpoint](jwtToken, pool)
+			}
+			return true
+		}
+		return false
+	})
+}
+
 // performCodexRESTChecks performs GraphQL API calls to all chains
-func performCodexRESTChecks(config *Config) {
+func performCodexRESTChecks(ctx context.Context, config *Config, rec Recorder) {
 	timestamp := time.Now().UTC().Format("2006-01-02 15:04:05")
 
 	// Get JWT token from Defined.fi
-	jwtToken, err := GetDefinedJWTToken(config.DefinedSessionCookie)
+	jwtToken, err := GetDefinedJWTToken(ctx, config.DefinedSessionCookie)
 	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+
 		// Check if it's a rate limit error
 		if strings.Contains(err.Error(), "rate limited (429)") {
-			fmt.Printf("[CODEX-REST][%s] ⚠ Rate limited - skipping this check cycle (will retry in 20s)\n", timestamp)
+			fmt.Printf("[CODEX-REST][%s] ⚠ Rate limited - skipping this check cycle (will retry in %v)\n", timestamp, currentRESTInterval())
 			return
 		}
 
@@ -167,67 +351,95 @@ func performCodexRESTChecks(config *Config) {
 	}
 
 	authErrorCount := 0
-	for _, chain := range codexRESTChains {
-		latencyMs, statusCode, err := callCodexGraphQLAPI(
-			jwtToken,
-			chain.poolAddress,
-			chain.networkID,
-			chain.chainName,
-		)
+	for _, pool := range monitoredPools() {
+		if !pool.SupportsCodex() || !chainEnabled("codex", pool.ChainName) {
+			continue
+		}
+
+		for _, endpoint := range config.CodexRESTEndpoints {
+			var err error
+			if endpoint == codexBarsEndpoint {
+				err = checkCodexBars(config, rec, ctx.Done(), jwtToken, pool, timestamp)
+			} else {
+				err = checkCodexEndpoint(config, rec, ctx.Done(), endpoint, jwtToken, pool, timestamp)
+			}
 
-		if err != nil {
 			// Check if it's an auth error
-			if err.Error() == "authentication error: User is not authenticated" && authErrorCount == 0 {
+			if errors.Is(err, errCodexAuth) && authErrorCount == 0 {
 				authErrorCount++
 				fmt.Println("[CODEX-REST] Authentication error - JWT token may be expired")
+				RecordAuthFailure("codex", "rest")
 				InvalidateTokenCache()
 				fmt.Println("[CODEX-REST] Token cache invalidated, will get new token on next cycle")
 			}
-
-			// Record error
-			errorType := "request_error"
-			if statusCode >= 500 {
-				errorType = "server_error"
-			} else if statusCode >= 400 {
-				errorType = "client_error"
-			} else if statusCode == 0 {
-				errorType = "timeout_error"
-			}
-
-			RecordRESTError("codex", "graphql", chain.chainName, errorType, config.MonitorRegion)
-
-			fmt.Printf("[CODEX-REST][%s][%s] ERROR | Latency: %.0fms | Status: %d | Error: %v\n",
-				timestamp,
-				chain.chainName,
-				latencyMs,
-				statusCode,
-				err,
-			)
-			continue
 		}
+	}
+}
 
-		// Record successful latency measurement
-		RecordRESTLatency("codex", "graphql", chain.chainName, latencyMs, statusCode, config.MonitorRegion)
+// checkCodexEndpoint probes one Codex GraphQL endpoint for a pool, records the outcome and returns the call's error
+func checkCodexEndpoint(config *Config, rec Recorder, stopChan <-chan struct{}, endpoint string, jwtToken string, pool MonitoredPool, timestamp string) error {
+	latencyMs, statusCode, err := withRESTRetry(stopChan, config.RESTRetries, func() (float64, int, error) {
+		return codexRESTEndpoints[endpoint](jwtToken, pool)
+	})
 
-		// Log the result
-		statusEmoji := "✓"
-		if statusCode >= 400 {
-			statusEmoji = "✗"
-		} else if statusCode >= 300 {
-			statusEmoji = "⚠"
-		}
+	if err != nil {
+		// Record error
+		rec.RecordRESTError("codex", endpoint, pool.ChainName, codexRESTErrorType(statusCode, err), config.MonitorRegion)
 
-		fmt.Printf("[CODEX-REST][%s][%s] %s | Latency: %.0fms | Status: %d\n",
+		fmt.Printf("[CODEX-REST][%s][%s][%s] ERROR | Latency: %.0fms | Status: %d | Error: %v\n",
 			timestamp,
-			chain.chainName,
-			statusEmoji,
+			pool.ChainName,
+			endpoint,
 			latencyMs,
 			statusCode,
+			err,
 		)
+		return err
+	}
+
+	// Record successful latency measurement
+	rec.RecordRESTLatency("codex", endpoint, pool.ChainName, latencyMs, statusCode, config.MonitorRegion)
+	if statusCode < 400 {
+		rec.RecordMonitorSuccess("codex", "rest", config.MonitorRegion)
+	}
+
+	// Log the result
+	statusEmoji := "✓"
+	if statusCode >= 400 {
+		statusEmoji = "✗"
+	} else if statusCode >= 300 {
+		statusEmoji = "⚠"
+	}
+
+	fmt.Printf("[CODEX-REST][%s][%s][%s] %s | Latency: %.0fms | Status: %d\n",
+		timestamp,
+		pool.ChainName,
+		endpoint,
+		statusEmoji,
+		latencyMs,
+		statusCode,
+	)
+	return nil
+}
+
+// codexRESTErrorType classifies a failed Codex call for rest_api_errors_total.
+// GraphQL errors usually arrive with a 200, so they are told apart from request errors.
+func codexRESTErrorType(statusCode int, err error) string {
+	switch {
+	case errors.Is(err, errCodexGraphQL) || errors.Is(err, errCodexAuth):
+		return "graphql_error"
+	case statusCode >= 500:
+		return "server_error"
+	case statusCode >= 400:
+		return "client_error"
+	case statusCode == 0:
+		return "timeout_error"
+	default:
+		return "request_error"
 	}
 }
 
 // runCodexRESTMonitor is the entry point for the Codex REST monitor
-func runCodexRESTMonitor(config *Config, stopChan <-chan struct{}) {
-	monitorCodexREST(config, stopChan)
+func runCodexRESTMonitor(config *Config, rec Recorder, stopChan <-chan struct{}) {
+	monitorCodexREST(config, rec, stopChan)
 }
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+// GraphQL errors come back with a 200, so they must still count as errors and never as a measured call
+func TestCodexEndpointRecordsGraphQLErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantErr     error
+		wantLatency []string
+		wantErrors  []string
+	}{
+		{
+			name:        "data",
+			status:      http.StatusOK,
+			body:        `{"data":{"filterPairs":{"results":[]}}}`,
+			wantLatency: []string{"codex graphql ethereum 200 eu"},
+		},
+		{
+			name:       "GraphQL error",
+			status:     http.StatusOK,
+			body:       `{"data":null,"errors":[{"message":"Cannot query field"}]}`,
+			wantErr:    errCodexGraphQL,
+			wantErrors: []string{"codex graphql ethereum graphql_error eu"},
+		},
+		{
+			name:       "expired token",
+			status:     http.StatusOK,
+			body:       fmt.Sprintf(`{"errors":[{"message":%q}]}`, codexUnauthenticatedMessage),
+			wantErr:    errCodexAuth,
+			wantErrors: []string{"codex graphql ethereum graphql_error eu"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+			withProviderURL(t, &providerURLs.CodexGraphQL, server.URL)
+
+			rec := &fakeRecorder{}
+			pool := MonitoredPool{Name: "ETH/USDC", Address: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", ChainInfo: ethereumChain}
+			err := checkCodexEndpoint(&Config{MonitorRegion: "eu"}, rec, nil, "graphql", "jwt", pool, "00:00:00")
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkCodexEndpoint error = %v, want %v", err, tt.wantErr)
+			}
+			if got := rec.calls("RecordRESTLatency"); fmt.Sprint(got) != fmt.Sprint(tt.wantLatency) {
+				t.Errorf("RecordRESTLatency calls = %q, want %q", got, tt.wantLatency)
+			}
+			if got := rec.calls("RecordRESTError"); fmt.Sprint(got) != fmt.Sprint(tt.wantErrors) {
+				t.Errorf("RecordRESTError calls = %q, want %q", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+// getBars goes through callCodexGraphQLQuery, so an expired JWT is an auth error there too and
+// gets the token refreshed like the other endpoints
+func TestCodexBarsAuthErrorInvalidatesToken(t *testing.T) {
+	withFreshTokenCache(t)
+	withProviderURL(t, &providerURLs.DefinedAPI, definedTokenServer(t).URL)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":null,"errors":[{"message":%q}]}`, codexUnauthenticatedMessage)
+	}))
+	defer server.Close()
+	withProviderURL(t, &providerURLs.CodexGraphQL, server.URL)
+
+	config := &Config{
+		DefinedSessionCookie: "cookie",
+		CodexRESTEndpoints:   []string{codexBarsEndpoint},
+		CodexBarsWindow:      time.Hour,
+		CodexBarsResolution:  "60",
+		MonitorRegion:        "eu",
+	}
+	pool := MonitoredPool{Name: "ETH/USDC", Address: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", ChainInfo: ethereumChain}
+	if err := checkCodexBars(config, &fakeRecorder{}, nil, "jwt", pool, "00:00:00"); !errors.Is(err, errCodexAuth) {
+		t.Errorf("checkCodexBars error = %v, want %v", err, errCodexAuth)
+	}
+
+	rec := &fakeRecorder{}
+	performCodexRESTChecks(context.Background(), config, rec)
+	if got := rec.calls("RecordRESTError"); len(got) == 0 || got[0] != "codex getBars ethereum graphql_error eu" {
+		t.Errorf("RecordRESTError calls = %q, want graphql_error for getBars", got)
+	}
+	if !globalTokenCache.invalidated {
+		t.Error("JWT cache not invalidated after a getBars auth error")
+	}
+}
+
+func TestCodexBarsDecodesBars(t *testing.T) {
+	latest := time.Now().Add(-time.Minute).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":{"getBars":{"t":[%d,%d]}}}`, latest-60, latest)
+	}))
+	defer server.Close()
+	withProviderURL(t, &providerURLs.CodexGraphQL, server.URL)
+
+	_, status, bars, err := callCodexGetBars("jwt", "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", 1, "ethereum", time.Hour, "1")
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("callCodexGetBars = status %d, error %v", status, err)
+	}
+	if !slices.Equal(bars, []int64{latest - 60, latest}) {
+		t.Errorf("callCodexGetBars bars = %v, want [%d %d]", bars, latest-60, latest)
+	}
+}
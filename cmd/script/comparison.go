@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Comparison Fairness
+// Cross-provider summaries only count pools that every provider covering the
+// chain subscribes to, so a provider is never ranked on pools the others lack
+// ============================================================================
+
+// comparedProviders are the head lag feeds compared in the stats summary and leaderboard,
+// with whether each one subscribes to a pool
+var comparedProviders = []struct {
+	name   string
+	covers func(pool MonitoredPool) bool
+}{
+	{"mobula", func(pool MonitoredPool) bool {
+		return pool.SupportsMobula() && chainEnabled("mobula", pool.ChainName)
+	}},
+	{"codex", func(pool MonitoredPool) bool {
+		return pool.SupportsCodex() && chainEnabled("codex", pool.ChainName)
+	}},
+	{"geckoterminal", func(pool MonitoredPool) bool {
+		return geckoPoolForAddress(pool.Address) != "" && chainEnabled("geckoterminal", pool.ChainName)
+	}},
+}
+
+// isComparedProvider reports whether a provider is a trade feed ranked against the others.
+// Derived series such as codex-metadata are summarized but never ranked.
+func isComparedProvider(name string) bool {
+	for _, provider := range comparedProviders {
+		if provider.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// comparisonEligible reports whether pool is subscribed by every compared provider that covers
+// its chain, i.e. subscribes to at least one pool on it. pools is the registry snapshot.
+// It also returns the providers on the chain that do not subscribe to the pool.
+func comparisonEligible(pool MonitoredPool, pools []MonitoredPool) (bool, []string) {
+	var missing []string
+	for _, provider := range comparedProviders {
+		if provider.covers(pool) {
+			continue
+		}
+		for _, other := range pools {
+			if other.ChainName == pool.ChainName && provider.covers(other) {
+				missing = append(missing, provider.name)
+				break
+			}
+		}
+	}
+	return len(missing) == 0, missing
+}
+
+// tradePoolComparable looks up a traded pool by address. Pools outside the registry
+// cannot be judged and count as eligible.
+func tradePoolComparable(address string) bool {
+	if address == "" {
+		return true
+	}
+	pool, ok := lookupPool(address)
+	if !ok {
+		return true
+	}
+	eligible, _ := comparisonEligible(pool, monitoredPools())
+	return eligible
+}
+
+// reportComparisonEligibility publishes which registry pools are compared across providers
+// and logs the ones that are not. It runs whenever the registry may have grown.
+func reportComparisonEligibility(region string) {
+	pools := monitoredPools()
+	for _, pool := range pools {
+		eligible, missing := comparisonEligible(pool, pools)
+		RecordPoolComparisonEligible(pool.ChainName, pool.Address, eligible, region)
+		if !eligible {
+			fmt.Printf("[COMPARISON][%s] %s (%s) left out of cross-provider stats: not subscribed by %s\n",
+				pool.ChainName, pool.Name, pool.Address, strings.Join(missing, ", "))
+		}
+	}
+}
@@ -2,45 +2,385 @@ package main
 
 import (
 	"bufio"
+	"cmp"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
 	CoinGeckoAPIKey       string
 	MobulaAPIKey          string
 	DefinedSessionCookie  string
+	GoldRushAPIKey        string
+	SyveAPIKey            string
 	MonitorRegion         string // Deployment region: us-west, us-east, singapore, etc.
+	MetricsAddr           string // Listen address of /metrics, /stats, /healthz and /readyz
+
+	// /readyz passes once ReadyQuorum of the running monitors received data within ReadyMaxAge
+	ReadyMaxAge time.Duration
+	ReadyQuorum float64 // Share of running monitors, in (0, 1]
+	readyMaxAge string
+	readyQuorum string
+
+	// Extra tokens to benchmark, resolved to their top pool at startup
+	Tokens []TokenTarget
+	tokens string
+
+	// Extra pools to monitor as a JSON list, from POOLS_FILE or else POOLS_JSON (see pool_list.go)
+	Pools     []MonitoredPool
+	PoolsFile string
+	poolsJSON string
+
+	// Look up every pool with each provider at startup, warning about stale addresses
+	ValidatePools bool
+	validatePools string
+
+	// Chains each provider is benchmarked on, every chain by default
+	ProviderChains ProviderChains
+	providerChains string
+
+	// Endpoint each provider is reached on, production by default (see provider_urls.go)
+	ProviderURLs ProviderURLs
+	providerURLs string
+
+	// Provider-specific addresses subscribed in place of registry pools
+	PoolOverrides []PoolOverride
+	poolOverrides string
+
+	// Polling intervals, hot-reloadable on SIGHUP
+	RESTInterval  time.Duration // Mobula and Codex REST monitors
+	QuoteInterval time.Duration // Quote API monitor
+	restInterval  string
+	quoteInterval string
+
+	// How often the Syve monitor polls each pool for new swaps
+	SyvePollInterval time.Duration
+	syvePollInterval string
+
+	// Slippage in basis points sent with each quote request, per provider
+	QuoteSlippageBps map[string]int
+	quoteSlippageBps string
+
+	// Notional sizes in USD quoted for price impact, ascending; empty disables it.
+	// Sizes above QuoteImpactMaxUSD are refused, since some providers reject them.
+	QuoteImpactSizes  []float64
+	QuoteImpactMaxUSD float64
+	quoteImpactSizes  string
+	quoteImpactMaxUSD string
+
+	// WebSocket read deadline per feed, 0 for none: a feed silent for longer is reconnected
+	ReadDeadlines map[string]time.Duration
+	readDeadlines string
+
+	// Binance symbols streamed as a CEX latency reference (disabled when empty)
+	CEXReferenceSymbols []string
+	cexReferenceSymbols string
+
+	// Discarded warm-up request per REST endpoint at startup, repeated every RESTWarmupInterval if set
+	RESTWarmup         bool
+	RESTWarmupInterval time.Duration
+	restWarmup         string
+	restWarmupInterval string
+
+	// Extra attempts for a Mobula or Codex REST call that failed transiently
+	RESTRetries int
+	restRetries string
+
+	// REST endpoints probed for every pool, by endpoint label
+	MobulaRESTEndpoints []string
+	CodexRESTEndpoints  []string
+	mobulaRESTEndpoints string
+	codexRESTEndpoints  string
+
+	// GoldRush address endpoints probed for every EVM pool, by endpoint label
+	GoldRushRESTEndpoints []string
+	goldRushRESTEndpoints string
+
+	// History window and candle size of the Mobula market_data probe and the Codex getBars probe
+	MobulaHistoryWindow time.Duration
+	MobulaHistoryPeriod string
+	CodexBarsWindow     time.Duration
+	CodexBarsResolution string
+	mobulaHistoryWindow string
+	codexBarsWindow     string
+
+	// Log one in every LogSampleRate trades per provider (0: only high-lag trades), hot-reloadable on SIGHUP
+	LogSampleRate int
+	logSampleRate string
+
+	// Debug logging (unparseable frames), hot-reloadable on SIGHUP
+	Debug bool
+	debug string
+
+	// ASCII-only startup banners and printed tables
+	PlainOutput bool
+	plainOutput string
+
+	// Sliding-window summaries printed to stdout
+	StatsWindow         time.Duration
+	StatsPrintInterval  time.Duration
+	LeaderboardInterval time.Duration
+	OutlierPolicy       OutlierPolicy
+	StatsMinSamples     int // Below this, a series reports insufficient data instead of percentiles
+	statsWindow         string
+	statsPrintInterval  string
+	leaderboardInterval string
+	outlierPolicy       string
+	outlierTrimPercent  string
+	outlierMADK         string
+	statsMinSamples     string
+
+	// Provider the /stats summary expresses the others' p50 and p95 against, empty for none
+	BaselineProvider string
+
+	// JSON-RPC URL per EVM chain for checking reported block timestamps, one in BlockTimestampSampleRate events
+	BlockTimestampRPC        map[string]string
+	blockTimestampRPC        string
+	BlockTimestampSampleRate int
+	blockTimestampSampleRate string
+
+	// How long a transaction waits for every provider before the first-to-index race is settled
+	FirstToIndexWindow time.Duration
+	firstToIndexWindow string
+
+	// Trades below this USD volume are left out of the head lag metrics (0: no filter)
+	MinTradeVolumeUSD float64
+	minTradeVolumeUSD string
+
+	// Record head_lag_by_trade_type_milliseconds (buy vs sell)
+	TradeTypeMetrics bool
+	tradeTypeMetrics string
+
+	// Also record head lag as a full-precision seconds histogram
+	PreciseLagMetrics bool
+	preciseLagMetrics string
+
+	// Optional Prometheus labels kept; the others are exported as "all" to bound cardinality
+	MetricLabels []string
+	metricLabels string
+
+	// Pulse sources counted as launchpads and kept in the launchpad metric label
+	Launchpads []string
+	launchpads string
+
+	// Weight of each new trade in head_lag_ewma_milliseconds
+	EWMAAlpha float64
+	ewmaAlpha string
+
+	// Codex head lag events: confirmed, or unconfirmed where Codex streams them
+	CodexConfirmation string
+
+	// Codex event types recorded in event_lag_milliseconds; only swaps are head lag trades
+	CodexEventTypes []string
+	codexEventTypes string
+
+	// Also subscribe to Codex pair metadata updates, recorded as codex-metadata
+	CodexMetadataUpdates bool
+	codexMetadataUpdates string
+
+	// Capacity of the metadata check and Moralis check queues
+	MetadataQueueSize int
+	MoralisQueueSize  int
+	metadataQueueSize string
+	moralisQueueSize  string
+
+	// Concurrent metadata requests allowed per provider
+	MetadataConcurrency map[string]int
+	metadataConcurrency string
+
+	// Redis Stream sink (disabled when RedisURL is empty)
+	RedisURL          string
+	RedisStreamKey    string
+	RedisStreamMaxLen int
+	redisStreamMaxLen string
+
+	// Kafka sink (disabled when KafkaBrokers is empty)
+	KafkaBrokers string
+	KafkaTopic   string
+
+	// CSV file sink (disabled when CSVPath is empty), rotated or truncated at CSVMaxSizeMB
+	CSVPath      string
+	CSVMaxSizeMB int
+	CSVRotation  string
+	csvMaxSizeMB string
+
+	// gRPC streaming API (disabled when GRPCPort is empty)
+	GRPCPort string
+
+	// OTLP/gRPC metrics export (disabled when OTLPEndpoint is empty)
+	OTLPEndpoint       string
+	OTelExportInterval time.Duration
+	otelExportInterval string
+
+	// Raw WebSocket frame capture for the replay subcommand (disabled when empty)
+	CaptureDir string
+
+	EnvFile string            // Env file that was read, empty if none
+	Sources map[string]string // Key -> env, file, default or unset
 }
 
-func loadEnv() (*Config, error) {
-	config := &Config{}
+// Where a config key's value came from
+const (
+	configSourceEnv     = "env"
+	configSourceFile    = "file"
+	configSourceDefault = "default"
+	configSourceUnset   = "unset"
+)
+
+// configFields lists every supported key and the Config field it fills, in log order
+func (config *Config) configFields() []struct {
+	key    string
+	target *string
+} {
+	return []struct {
+		key    string
+		target *string
+	}{
+		{"COINGECKO_API_KEY", &config.CoinGeckoAPIKey},
+		{"MOBULA_API_KEY", &config.MobulaAPIKey},
+		{"DEFINED_SESSION_COOKIE", &config.DefinedSessionCookie},
+		{"GOLDRUSH_API_KEY", &config.GoldRushAPIKey},
+		{"SYVE_API_KEY", &config.SyveAPIKey},
+		{"MONITOR_REGION", &config.MonitorRegion},
+		{"METRICS_ADDR", &config.MetricsAddr},
+		{"READY_MAX_AGE", &config.readyMaxAge},
+		{"READY_QUORUM", &config.readyQuorum},
+		{"TOKENS", &config.tokens},
+		{"POOLS_FILE", &config.PoolsFile},
+		{"POOLS_JSON", &config.poolsJSON},
+		{"VALIDATE_POOLS", &config.validatePools},
+		{"PROVIDER_CHAINS", &config.providerChains},
+		{"PROVIDER_URLS", &config.providerURLs},
+		{"POOL_OVERRIDES", &config.poolOverrides},
+		{"REST_INTERVAL", &config.restInterval},
+		{"QUOTE_INTERVAL", &config.quoteInterval},
+		{"SYVE_POLL_INTERVAL", &config.syvePollInterval},
+		{"QUOTE_SLIPPAGE_BPS", &config.quoteSlippageBps},
+		{"QUOTE_IMPACT_SIZES", &config.quoteImpactSizes},
+		{"QUOTE_IMPACT_MAX_USD", &config.quoteImpactMaxUSD},
+		{"READ_DEADLINES", &config.readDeadlines},
+		{"REST_RETRIES", &config.restRetries},
+		{"REST_WARMUP", &config.restWarmup},
+		{"REST_WARMUP_INTERVAL", &config.restWarmupInterval},
+		{"MOBULA_REST_ENDPOINTS", &config.mobulaRESTEndpoints},
+		{"CODEX_REST_ENDPOINTS", &config.codexRESTEndpoints},
+		{"GOLDRUSH_REST_ENDPOINTS", &config.goldRushRESTEndpoints},
+		{"MOBULA_HISTORY_WINDOW", &config.mobulaHistoryWindow},
+		{"MOBULA_HISTORY_PERIOD", &config.MobulaHistoryPeriod},
+		{"CODEX_BARS_WINDOW", &config.codexBarsWindow},
+		{"CODEX_BARS_RESOLUTION", &config.CodexBarsResolution},
+		{"LOG_SAMPLE_RATE", &config.logSampleRate},
+		{"DEBUG", &config.debug},
+		{"PLAIN_OUTPUT", &config.plainOutput},
+		{"STATS_WINDOW", &config.statsWindow},
+		{"STATS_MIN_SAMPLES", &config.statsMinSamples},
+		{"STATS_PRINT_INTERVAL", &config.statsPrintInterval},
+		{"LEADERBOARD_INTERVAL", &config.leaderboardInterval},
+		{"OUTLIER_POLICY", &config.outlierPolicy},
+		{"OUTLIER_TRIM_PERCENT", &config.outlierTrimPercent},
+		{"OUTLIER_MAD_K", &config.outlierMADK},
+		{"BASELINE_PROVIDER", &config.BaselineProvider},
+		{"FIRST_TO_INDEX_WINDOW", &config.firstToIndexWindow},
+		{"BLOCK_TIMESTAMP_RPC", &config.blockTimestampRPC},
+		{"BLOCK_TIMESTAMP_SAMPLE_RATE", &config.blockTimestampSampleRate},
+		{"EWMA_ALPHA", &config.ewmaAlpha},
+		{"MIN_TRADE_VOLUME_USD", &config.minTradeVolumeUSD},
+		{"TRADE_TYPE_METRICS", &config.tradeTypeMetrics},
+		{"PRECISE_LAG_METRICS", &config.preciseLagMetrics},
+		{"METRIC_LABELS", &config.metricLabels},
+		{"LAUNCHPADS", &config.launchpads},
+		{"CODEX_CONFIRMATION", &config.CodexConfirmation},
+		{"CODEX_EVENT_TYPES", &config.codexEventTypes},
+		{"CODEX_METADATA_UPDATES", &config.codexMetadataUpdates},
+		{"METADATA_QUEUE_SIZE", &config.metadataQueueSize},
+		{"MORALIS_QUEUE_SIZE", &config.moralisQueueSize},
+		{"METADATA_CONCURRENCY", &config.metadataConcurrency},
+		{"CEX_REFERENCE_SYMBOLS", &config.cexReferenceSymbols},
+		{"REDIS_URL", &config.RedisURL},
+		{"REDIS_STREAM_KEY", &config.RedisStreamKey},
+		{"REDIS_STREAM_MAXLEN", &config.redisStreamMaxLen},
+		{"KAFKA_BROKERS", &config.KafkaBrokers},
+		{"KAFKA_TOPIC", &config.KafkaTopic},
+		{"CSV_PATH", &config.CSVPath},
+		{"CSV_MAX_SIZE_MB", &config.csvMaxSizeMB},
+		{"CSV_ROTATION", &config.CSVRotation},
+		{"GRPC_PORT", &config.GRPCPort},
+		{"OTEL_EXPORTER_OTLP_ENDPOINT", &config.OTLPEndpoint},
+		{"OTEL_EXPORT_INTERVAL", &config.otelExportInterval},
+		{"CAPTURE_DIR", &config.CaptureDir},
+	}
+}
 
-	// First, try to load from environment variables (for production/Railway)
-	config.CoinGeckoAPIKey = strings.TrimSpace(os.Getenv("COINGECKO_API_KEY"))
-	config.MobulaAPIKey = strings.TrimSpace(os.Getenv("MOBULA_API_KEY"))
-	config.DefinedSessionCookie = strings.TrimSpace(os.Getenv("DEFINED_SESSION_COOKIE"))
-	config.MonitorRegion = strings.TrimSpace(os.Getenv("MONITOR_REGION"))
+// loadEnv layers environment variables over the env file.
+// The file is envFile if set, else $ENV_FILE, else ".env" in the working directory;
+// only the implicit ".env" may be missing.
+func loadEnv(envFile string) (*Config, error) {
+	config := &Config{Sources: make(map[string]string)}
 
-	// Default to "unknown" if not set
-	if config.MonitorRegion == "" {
-		config.MonitorRegion = "unknown"
+	if envFile == "" {
+		envFile = strings.TrimSpace(os.Getenv("ENV_FILE"))
+	}
+	explicit := envFile != ""
+	if !explicit {
+		envFile = ".env"
+	}
+
+	fileValues, err := readEnvFile(envFile)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			// No .env file is fine - keys come from the environment or services are skipped
+			fileValues = map[string]string{}
+		} else {
+			return nil, err
+		}
+	} else {
+		config.EnvFile = envFile
 	}
 
-	// If all env vars are set, return early (production mode)
-	if config.CoinGeckoAPIKey != "" || config.MobulaAPIKey != "" || config.DefinedSessionCookie != "" {
-		return config, nil
+	for _, field := range config.configFields() {
+		if value := strings.TrimSpace(os.Getenv(field.key)); value != "" {
+			*field.target = value
+			config.Sources[field.key] = configSourceEnv
+		} else if value := fileValues[field.key]; value != "" {
+			*field.target = value
+			config.Sources[field.key] = configSourceFile
+		}
+	}
+
+	if err := applyConfigDefaults(config); err != nil {
+		return nil, err
 	}
 
-	// Otherwise, try to load from .env file (for local development)
-	file, err := os.Open(".env")
+	for _, field := range config.configFields() {
+		if _, ok := config.Sources[field.key]; ok {
+			continue
+		}
+		if *field.target != "" {
+			config.Sources[field.key] = configSourceDefault
+		} else {
+			config.Sources[field.key] = configSourceUnset
+		}
+	}
+
+	return config, nil
+}
+
+// readEnvFile parses KEY=VALUE lines, ignoring blanks and # comments
+func readEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		// If no .env file and no env vars, that's OK - services will just be skipped
-		return config, nil
+		return nil, fmt.Errorf("error opening env file %s: %w", path, err)
 	}
 	defer file.Close()
 
+	values := make(map[string]string)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -53,26 +393,710 @@ func loadEnv() (*Config, error) {
 			continue
 		}
 
-		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
-		switch key {
-		case "COINGECKO_API_KEY":
-			if config.CoinGeckoAPIKey == "" {
-				config.CoinGeckoAPIKey = value
-			}
-		case "MOBULA_API_KEY":
-			if config.MobulaAPIKey == "" {
-				config.MobulaAPIKey = value
-			}
-		case "DEFINED_SESSION_COOKIE":
-			if config.DefinedSessionCookie == "" {
-				config.DefinedSessionCookie = value
-			}
-		}
+		values[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading .env file: %w", err)
+		return nil, fmt.Errorf("error reading env file %s: %w", path, err)
 	}
 
-	return config, nil
+	return values, nil
+}
+
+// logConfigSources prints where each key came from, never the values themselves
+func logConfigSources(config *Config) {
+	if config.EnvFile != "" {
+		fmt.Printf("Config file: %s\n", config.EnvFile)
+	} else {
+		fmt.Println("Config file: none (environment only)")
+	}
+
+	var parts []string
+	for _, field := range config.configFields() {
+		parts = append(parts, fmt.Sprintf("%s=%s", field.key, config.Sources[field.key]))
+	}
+	fmt.Printf("Config sources: %s\n", strings.Join(parts, " "))
+}
+
+// applyConfigDefaults parses numeric settings and fills in defaults for optional ones
+func applyConfigDefaults(config *Config) error {
+	if config.MonitorRegion == "" {
+		config.MonitorRegion = "unknown"
+	}
+
+	if config.MetricsAddr == "" {
+		config.MetricsAddr = ":2112"
+	}
+	if _, _, err := net.SplitHostPort(config.MetricsAddr); err != nil {
+		return fmt.Errorf("invalid METRICS_ADDR: %q", config.MetricsAddr)
+	}
+
+	tokens, err := parseTokenTargets(config.tokens)
+	if err != nil {
+		return err
+	}
+	config.Tokens = tokens
+
+	config.Pools, err = loadPoolList(config.PoolsFile, config.poolsJSON)
+	if err != nil {
+		return err
+	}
+
+	if config.readyMaxAge == "" {
+		config.readyMaxAge = "5m"
+	}
+	config.ReadyMaxAge, err = time.ParseDuration(config.readyMaxAge)
+	if err != nil || config.ReadyMaxAge <= 0 {
+		return fmt.Errorf("invalid READY_MAX_AGE: %q", config.readyMaxAge)
+	}
+
+	if config.readyQuorum == "" {
+		config.readyQuorum = "1"
+	}
+	config.ReadyQuorum, err = strconv.ParseFloat(config.readyQuorum, 64)
+	if err != nil || config.ReadyQuorum <= 0 || config.ReadyQuorum > 1 {
+		return fmt.Errorf("invalid READY_QUORUM: %q", config.readyQuorum)
+	}
+
+	if config.validatePools == "" {
+		config.validatePools = "true"
+	}
+	config.ValidatePools, err = strconv.ParseBool(config.validatePools)
+	if err != nil {
+		return fmt.Errorf("invalid VALIDATE_POOLS: %q", config.validatePools)
+	}
+
+	config.ProviderChains, err = parseProviderChains(config.providerChains)
+	if err != nil {
+		return err
+	}
+
+	config.ProviderURLs, err = parseProviderURLs(config.providerURLs)
+	if err != nil {
+		return err
+	}
+
+	config.PoolOverrides, err = parsePoolOverrides(config.poolOverrides)
+	if err != nil {
+		return err
+	}
+
+	if config.restInterval == "" {
+		config.restInterval = "20s"
+	}
+	config.RESTInterval, err = time.ParseDuration(config.restInterval)
+	if err != nil || config.RESTInterval <= 0 {
+		return fmt.Errorf("invalid REST_INTERVAL: %q", config.restInterval)
+	}
+
+	if config.quoteInterval == "" {
+		config.quoteInterval = "30s"
+	}
+	config.QuoteInterval, err = time.ParseDuration(config.quoteInterval)
+	if err != nil || config.QuoteInterval <= 0 {
+		return fmt.Errorf("invalid QUOTE_INTERVAL: %q", config.quoteInterval)
+	}
+
+	if config.syvePollInterval == "" {
+		config.syvePollInterval = "5s"
+	}
+	config.SyvePollInterval, err = time.ParseDuration(config.syvePollInterval)
+	if err != nil || config.SyvePollInterval <= 0 {
+		return fmt.Errorf("invalid SYVE_POLL_INTERVAL: %q", config.syvePollInterval)
+	}
+
+	if config.quoteSlippageBps == "" {
+		config.quoteSlippageBps = "mobula=100,jupiter=50"
+	}
+	config.QuoteSlippageBps, err = parseQuoteSlippage(config.quoteSlippageBps)
+	if err != nil {
+		return err
+	}
+
+	if config.quoteImpactMaxUSD == "" {
+		config.quoteImpactMaxUSD = "10000"
+	}
+	config.QuoteImpactMaxUSD, err = strconv.ParseFloat(config.quoteImpactMaxUSD, 64)
+	if err != nil || config.QuoteImpactMaxUSD <= 0 {
+		return fmt.Errorf("invalid QUOTE_IMPACT_MAX_USD: %q", config.quoteImpactMaxUSD)
+	}
+	config.QuoteImpactSizes, err = parseQuoteImpactSizes(config.quoteImpactSizes, config.QuoteImpactMaxUSD)
+	if err != nil {
+		return err
+	}
+
+	if config.readDeadlines == "" {
+		config.readDeadlines = "mobula=60s,codex=60s"
+	}
+	config.ReadDeadlines, err = parseReadDeadlines(config.readDeadlines)
+	if err != nil {
+		return err
+	}
+
+	if config.restRetries == "" {
+		config.restRetries = "2"
+	}
+	config.RESTRetries, err = strconv.Atoi(config.restRetries)
+	if err != nil || config.RESTRetries < 0 {
+		return fmt.Errorf("invalid REST_RETRIES: %q", config.restRetries)
+	}
+
+	if config.restWarmup == "" {
+		config.restWarmup = "false"
+	}
+	config.RESTWarmup, err = strconv.ParseBool(config.restWarmup)
+	if err != nil {
+		return fmt.Errorf("invalid REST_WARMUP: %q", config.restWarmup)
+	}
+
+	if config.restWarmupInterval == "" {
+		config.restWarmupInterval = "0"
+	}
+	config.RESTWarmupInterval, err = time.ParseDuration(config.restWarmupInterval)
+	if err != nil || config.RESTWarmupInterval < 0 {
+		return fmt.Errorf("invalid REST_WARMUP_INTERVAL: %q", config.restWarmupInterval)
+	}
+
+	if config.mobulaRESTEndpoints == "" {
+		config.mobulaRESTEndpoints = "market_data,market_v2"
+	}
+	config.MobulaRESTEndpoints, err = parseEndpointList("MOBULA_REST_ENDPOINTS", config.mobulaRESTEndpoints, func(name string) bool {
+		_, ok := mobulaRESTEndpoints[name]
+		return ok
+	})
+	if err != nil {
+		return err
+	}
+
+	if config.codexRESTEndpoints == "" {
+		config.codexRESTEndpoints = "graphql," + codexBarsEndpoint
+	}
+	config.CodexRESTEndpoints, err = parseEndpointList("CODEX_REST_ENDPOINTS", config.codexRESTEndpoints, func(name string) bool {
+		_, ok := codexRESTEndpoints[name]
+		return ok || name == codexBarsEndpoint
+	})
+	if err != nil {
+		return err
+	}
+
+	if config.goldRushRESTEndpoints == "" {
+		config.goldRushRESTEndpoints = "balances"
+	}
+	config.GoldRushRESTEndpoints, err = parseEndpointList("GOLDRUSH_REST_ENDPOINTS", config.goldRushRESTEndpoints, func(name string) bool {
+		_, ok := goldRushRESTEndpoints[name]
+		return ok
+	})
+	if err != nil {
+		return err
+	}
+
+	if config.mobulaHistoryWindow == "" {
+		config.mobulaHistoryWindow = "1h"
+	}
+	if config.MobulaHistoryPeriod == "" {
+		config.MobulaHistoryPeriod = "1min"
+	}
+	config.MobulaHistoryWindow, err = parseHistoryWindow("MOBULA_HISTORY_WINDOW", config.mobulaHistoryWindow, "MOBULA_HISTORY_PERIOD", config.MobulaHistoryPeriod, mobulaHistoryPeriods)
+	if err != nil {
+		return err
+	}
+
+	if config.codexBarsWindow == "" {
+		config.codexBarsWindow = "1h"
+	}
+	if config.CodexBarsResolution == "" {
+		config.CodexBarsResolution = "1"
+	}
+	config.CodexBarsWindow, err = parseHistoryWindow("CODEX_BARS_WINDOW", config.codexBarsWindow, "CODEX_BARS_RESOLUTION", config.CodexBarsResolution, codexBarResolutions)
+	if err != nil {
+		return err
+	}
+
+	if config.logSampleRate == "" {
+		config.logSampleRate = "50"
+	}
+	config.LogSampleRate, err = strconv.Atoi(config.logSampleRate)
+	if err != nil || config.LogSampleRate < 0 {
+		return fmt.Errorf("invalid LOG_SAMPLE_RATE: %q", config.logSampleRate)
+	}
+
+	if config.debug == "" {
+		config.debug = "false"
+	}
+	config.Debug, err = strconv.ParseBool(config.debug)
+	if err != nil {
+		return fmt.Errorf("invalid DEBUG: %q", config.debug)
+	}
+
+	if config.plainOutput == "" {
+		config.plainOutput = "false"
+	}
+	config.PlainOutput, err = strconv.ParseBool(config.plainOutput)
+	if err != nil {
+		return fmt.Errorf("invalid PLAIN_OUTPUT: %q", config.plainOutput)
+	}
+
+	if config.statsWindow == "" {
+		config.statsWindow = "5m"
+	}
+	config.StatsWindow, err = time.ParseDuration(config.statsWindow)
+	if err != nil || config.StatsWindow <= 0 {
+		return fmt.Errorf("invalid STATS_WINDOW: %q", config.statsWindow)
+	}
+
+	if config.statsMinSamples == "" {
+		config.statsMinSamples = "20"
+	}
+	config.StatsMinSamples, err = strconv.Atoi(config.statsMinSamples)
+	if err != nil || config.StatsMinSamples < 1 {
+		return fmt.Errorf("invalid STATS_MIN_SAMPLES: %q", config.statsMinSamples)
+	}
+
+	if config.statsPrintInterval == "" {
+		config.statsPrintInterval = "1m"
+	}
+	config.StatsPrintInterval, err = time.ParseDuration(config.statsPrintInterval)
+	if err != nil || config.StatsPrintInterval <= 0 {
+		return fmt.Errorf("invalid STATS_PRINT_INTERVAL: %q", config.statsPrintInterval)
+	}
+
+	if config.leaderboardInterval == "" {
+		config.leaderboardInterval = "5m"
+	}
+	config.LeaderboardInterval, err = time.ParseDuration(config.leaderboardInterval)
+	if err != nil || config.LeaderboardInterval <= 0 {
+		return fmt.Errorf("invalid LEADERBOARD_INTERVAL: %q", config.leaderboardInterval)
+	}
+
+	if config.outlierPolicy == "" {
+		config.outlierPolicy = outlierPolicyMAD
+	}
+	config.OutlierPolicy.Mode = strings.ToLower(config.outlierPolicy)
+	switch config.OutlierPolicy.Mode {
+	case outlierPolicyNone, outlierPolicyTrim, outlierPolicyMAD:
+	default:
+		return fmt.Errorf("invalid OUTLIER_POLICY: %q (expected none, trim or mad)", config.outlierPolicy)
+	}
+
+	if config.outlierTrimPercent == "" {
+		config.outlierTrimPercent = "1"
+	}
+	config.OutlierPolicy.TrimPercent, err = strconv.ParseFloat(config.outlierTrimPercent, 64)
+	if err != nil || config.OutlierPolicy.TrimPercent < 0 || config.OutlierPolicy.TrimPercent >= 50 {
+		return fmt.Errorf("invalid OUTLIER_TRIM_PERCENT: %q", config.outlierTrimPercent)
+	}
+
+	if config.outlierMADK == "" {
+		config.outlierMADK = "5"
+	}
+	config.OutlierPolicy.MADK, err = strconv.ParseFloat(config.outlierMADK, 64)
+	if err != nil || config.OutlierPolicy.MADK <= 0 {
+		return fmt.Errorf("invalid OUTLIER_MAD_K: %q", config.outlierMADK)
+	}
+
+	config.BaselineProvider = strings.ToLower(strings.TrimSpace(config.BaselineProvider))
+	if config.BaselineProvider != "" && !isComparedProvider(config.BaselineProvider) {
+		var names []string
+		for _, provider := range comparedProviders {
+			names = append(names, provider.name)
+		}
+		return fmt.Errorf("invalid BASELINE_PROVIDER: %q (expected %s)", config.BaselineProvider, strings.Join(names, ", "))
+	}
+
+	if config.firstToIndexWindow == "" {
+		config.firstToIndexWindow = "5s"
+	}
+	config.FirstToIndexWindow, err = time.ParseDuration(config.firstToIndexWindow)
+	if err != nil || config.FirstToIndexWindow <= 0 {
+		return fmt.Errorf("invalid FIRST_TO_INDEX_WINDOW: %q", config.firstToIndexWindow)
+	}
+
+	config.BlockTimestampRPC, err = parseBlockTimestampRPC(config.blockTimestampRPC)
+	if err != nil {
+		return err
+	}
+	if config.blockTimestampSampleRate == "" {
+		config.blockTimestampSampleRate = "100"
+	}
+	config.BlockTimestampSampleRate, err = strconv.Atoi(config.blockTimestampSampleRate)
+	if err != nil || config.BlockTimestampSampleRate <= 0 {
+		return fmt.Errorf("invalid BLOCK_TIMESTAMP_SAMPLE_RATE: %q", config.blockTimestampSampleRate)
+	}
+
+	if config.ewmaAlpha == "" {
+		config.ewmaAlpha = "0.1"
+	}
+	config.EWMAAlpha, err = strconv.ParseFloat(config.ewmaAlpha, 64)
+	if err != nil || config.EWMAAlpha <= 0 || config.EWMAAlpha > 1 {
+		return fmt.Errorf("invalid EWMA_ALPHA: %q (expected 0 < alpha <= 1)", config.ewmaAlpha)
+	}
+
+	if config.minTradeVolumeUSD == "" {
+		config.minTradeVolumeUSD = "0"
+	}
+	config.MinTradeVolumeUSD, err = strconv.ParseFloat(config.minTradeVolumeUSD, 64)
+	if err != nil || config.MinTradeVolumeUSD < 0 {
+		return fmt.Errorf("invalid MIN_TRADE_VOLUME_USD: %q", config.minTradeVolumeUSD)
+	}
+
+	if config.tradeTypeMetrics == "" {
+		config.tradeTypeMetrics = "false"
+	}
+	config.TradeTypeMetrics, err = strconv.ParseBool(config.tradeTypeMetrics)
+	if err != nil {
+		return fmt.Errorf("invalid TRADE_TYPE_METRICS: %q", config.tradeTypeMetrics)
+	}
+
+	if config.preciseLagMetrics == "" {
+		config.preciseLagMetrics = "false"
+	}
+	config.PreciseLagMetrics, err = strconv.ParseBool(config.preciseLagMetrics)
+	if err != nil {
+		return fmt.Errorf("invalid PRECISE_LAG_METRICS: %q", config.preciseLagMetrics)
+	}
+
+	if config.metricLabels == "" {
+		config.metricLabels = "confirmation,trade_type"
+	}
+	config.MetricLabels, err = parseMetricLabels(config.metricLabels)
+	if err != nil {
+		return err
+	}
+
+	if config.launchpads == "" {
+		config.launchpads = strings.Join(defaultLaunchpads, ",")
+	}
+	config.Launchpads, err = parseLaunchpads(config.launchpads)
+	if err != nil {
+		return err
+	}
+
+	if config.CodexConfirmation == "" {
+		config.CodexConfirmation = codexConfirmed
+	}
+	config.CodexConfirmation = strings.ToLower(config.CodexConfirmation)
+	switch config.CodexConfirmation {
+	case codexConfirmed, codexUnconfirmed:
+	default:
+		return fmt.Errorf("invalid CODEX_CONFIRMATION: %q (expected confirmed or unconfirmed)", config.CodexConfirmation)
+	}
+
+	if config.codexEventTypes == "" {
+		config.codexEventTypes = codexSwapEvent
+	}
+	config.CodexEventTypes, err = parseCodexEventTypes(config.codexEventTypes)
+	if err != nil {
+		return err
+	}
+
+	if config.codexMetadataUpdates == "" {
+		config.codexMetadataUpdates = "false"
+	}
+	config.CodexMetadataUpdates, err = strconv.ParseBool(config.codexMetadataUpdates)
+	if err != nil {
+		return fmt.Errorf("invalid CODEX_METADATA_UPDATES: %q", config.codexMetadataUpdates)
+	}
+
+	if config.metadataQueueSize == "" {
+		config.metadataQueueSize = "500"
+	}
+	config.MetadataQueueSize, err = strconv.Atoi(config.metadataQueueSize)
+	if err != nil || config.MetadataQueueSize <= 0 {
+		return fmt.Errorf("invalid METADATA_QUEUE_SIZE: %q", config.metadataQueueSize)
+	}
+
+	if config.moralisQueueSize == "" {
+		config.moralisQueueSize = "1000"
+	}
+	config.MoralisQueueSize, err = strconv.Atoi(config.moralisQueueSize)
+	if err != nil || config.MoralisQueueSize <= 0 {
+		return fmt.Errorf("invalid MORALIS_QUEUE_SIZE: %q", config.moralisQueueSize)
+	}
+
+	if config.metadataConcurrency == "" {
+		config.metadataConcurrency = "mobula=4,codex=2,jupiter=2"
+	}
+	config.MetadataConcurrency, err = parseMetadataConcurrency(config.metadataConcurrency)
+	if err != nil {
+		return err
+	}
+
+	for _, symbol := range strings.Split(config.cexReferenceSymbols, ",") {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" {
+			continue
+		}
+		if strings.IndexFunc(symbol, func(r rune) bool { return (r < 'A' || r > 'Z') && (r < '0' || r > '9') }) >= 0 {
+			return fmt.Errorf("invalid CEX_REFERENCE_SYMBOLS: %q", config.cexReferenceSymbols)
+		}
+		config.CEXReferenceSymbols = append(config.CEXReferenceSymbols, symbol)
+	}
+
+	if config.RedisStreamKey == "" {
+		config.RedisStreamKey = "latency:measurements"
+	}
+
+	if config.redisStreamMaxLen == "" {
+		config.redisStreamMaxLen = "100000"
+	}
+	maxLen, err := strconv.Atoi(config.redisStreamMaxLen)
+	if err != nil || maxLen <= 0 {
+		return fmt.Errorf("invalid REDIS_STREAM_MAXLEN: %q", config.redisStreamMaxLen)
+	}
+	config.RedisStreamMaxLen = maxLen
+
+	if config.KafkaTopic == "" {
+		config.KafkaTopic = "latency-measurements"
+	}
+
+	if config.csvMaxSizeMB == "" {
+		config.csvMaxSizeMB = "100"
+	}
+	config.CSVMaxSizeMB, err = strconv.Atoi(config.csvMaxSizeMB)
+	if err != nil || config.CSVMaxSizeMB < 0 {
+		return fmt.Errorf("invalid CSV_MAX_SIZE_MB: %q", config.csvMaxSizeMB)
+	}
+
+	if config.CSVRotation == "" {
+		config.CSVRotation = csvRotationRotate
+	}
+	config.CSVRotation = strings.ToLower(config.CSVRotation)
+	switch config.CSVRotation {
+	case csvRotationRotate, csvRotationTruncate:
+	default:
+		return fmt.Errorf("invalid CSV_ROTATION: %q (expected rotate or truncate)", config.CSVRotation)
+	}
+
+	if config.GRPCPort != "" {
+		if port, err := strconv.Atoi(config.GRPCPort); err != nil || port <= 0 || port > 65535 {
+			return fmt.Errorf("invalid GRPC_PORT: %q", config.GRPCPort)
+		}
+	}
+
+	if config.OTLPEndpoint != "" {
+		if endpoint, err := url.Parse(config.OTLPEndpoint); err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return fmt.Errorf("invalid OTEL_EXPORTER_OTLP_ENDPOINT: %q (expected http(s)://host:port)", config.OTLPEndpoint)
+		}
+	}
+
+	if config.otelExportInterval == "" {
+		config.otelExportInterval = "15s"
+	}
+	config.OTelExportInterval, err = time.ParseDuration(config.otelExportInterval)
+	if err != nil || config.OTelExportInterval <= 0 {
+		return fmt.Errorf("invalid OTEL_EXPORT_INTERVAL: %q", config.otelExportInterval)
+	}
+
+	return nil
+}
+
+// parseQuoteSlippage parses QUOTE_SLIPPAGE_BPS ("mobula=100,jupiter=50,lifi=50"). Providers left out
+// get no slippage parameter and use their own default.
+func parseQuoteSlippage(raw string) (map[string]int, error) {
+	slippage := make(map[string]int)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		provider, value, ok := strings.Cut(entry, "=")
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		bps, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || bps < 0 || bps > 10000 {
+			return nil, fmt.Errorf("invalid QUOTE_SLIPPAGE_BPS entry %q: expected <provider>=<bps>", entry)
+		}
+		if _, known := quoteSlippageParams[provider]; !known {
+			return nil, fmt.Errorf("invalid QUOTE_SLIPPAGE_BPS entry %q: provider %q takes no slippage on quotes", entry, provider)
+		}
+		slippage[provider] = bps
+	}
+	return slippage, nil
+}
+
+// parseQuoteImpactSizes parses QUOTE_IMPACT_SIZES ("100,10000,1000000") into ascending sizes.
+// Impact is measured against the smallest size, so it takes at least two; none disables it.
+func parseQuoteImpactSizes(raw string, maxUSD float64) ([]float64, error) {
+	var sizes []float64
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		size, err := strconv.ParseFloat(entry, 64)
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("invalid QUOTE_IMPACT_SIZES entry %q: expected a USD amount", entry)
+		}
+		if size > maxUSD {
+			return nil, fmt.Errorf("invalid QUOTE_IMPACT_SIZES entry %q: above QUOTE_IMPACT_MAX_USD (%g), raise it to quote larger sizes", entry, maxUSD)
+		}
+		if slices.Contains(sizes, size) {
+			return nil, fmt.Errorf("invalid QUOTE_IMPACT_SIZES entry %q: listed twice", entry)
+		}
+		sizes = append(sizes, size)
+	}
+	if len(sizes) == 1 {
+		return nil, fmt.Errorf("invalid QUOTE_IMPACT_SIZES: %q, at least two sizes are needed", raw)
+	}
+	slices.Sort(sizes)
+	return sizes, nil
+}
+
+// parseReadDeadlines parses READ_DEADLINES ("mobula=60s,geckoterminal=5m"). Feeds left out,
+// or set to 0, have no read deadline.
+func parseReadDeadlines(raw string) (map[string]time.Duration, error) {
+	deadlines := make(map[string]time.Duration)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		feed, value, ok := strings.Cut(entry, "=")
+		feed = strings.ToLower(strings.TrimSpace(feed))
+		deadline, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || err != nil || deadline < 0 {
+			return nil, fmt.Errorf("invalid READ_DEADLINES entry %q: expected <feed>=<duration>", entry)
+		}
+		if !slices.Contains(websocketFeeds, feed) {
+			return nil, fmt.Errorf("invalid READ_DEADLINES entry %q: unknown feed %q (expected %s)", entry, feed, strings.Join(websocketFeeds, ", "))
+		}
+		deadlines[feed] = deadline
+	}
+	return deadlines, nil
+}
+
+// parseBlockTimestampRPC parses BLOCK_TIMESTAMP_RPC ("ethereum=https://eth.example,base=https://base.example").
+// Only EVM chains are accepted, as the check relies on eth_getBlockByNumber.
+func parseBlockTimestampRPC(raw string) (map[string]string, error) {
+	urls := make(map[string]string)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		chain, rpcURL, ok := strings.Cut(entry, "=")
+		chain = strings.ToLower(strings.TrimSpace(chain))
+		rpcURL = strings.TrimSpace(rpcURL)
+		u, err := url.Parse(rpcURL)
+		if !ok || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid BLOCK_TIMESTAMP_RPC entry %q: expected <chain>=<http(s) url>", entry)
+		}
+		info, known := lookupChain(chain)
+		if !known || info.Kind != ChainKindEVM {
+			return nil, fmt.Errorf("invalid BLOCK_TIMESTAMP_RPC entry %q: %q is not a known EVM chain", entry, chain)
+		}
+		urls[info.ChainName] = rpcURL
+	}
+	return urls, nil
+}
+
+// parseMetadataConcurrency parses METADATA_CONCURRENCY ("mobula=4,codex=2"). Providers left out keep the
+// default of one request at a time.
+func parseMetadataConcurrency(raw string) (map[string]int, error) {
+	limits := make(map[string]int)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		provider, value, ok := strings.Cut(entry, "=")
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid METADATA_CONCURRENCY entry %q: expected <provider>=<limit>", entry)
+		}
+		if !isMetadataProvider(provider) {
+			return nil, fmt.Errorf("invalid METADATA_CONCURRENCY entry %q: unknown provider %q", entry, provider)
+		}
+		limits[provider] = limit
+	}
+	return limits, nil
+}
+
+// parseHistoryWindow parses a REST probe's history window, which must hold at least one candle
+// of the size named by candle
+func parseHistoryWindow(windowKey string, raw string, candleKey string, candle string, candles map[string]time.Duration) (time.Duration, error) {
+	step, ok := candles[candle]
+	if !ok {
+		known := make([]string, 0, len(candles))
+		for name := range candles {
+			known = append(known, name)
+		}
+		slices.SortFunc(known, func(a, b string) int { return cmp.Compare(candles[a], candles[b]) })
+		return 0, fmt.Errorf("invalid %s: %q (expected one of %s)", candleKey, candle, strings.Join(known, ", "))
+	}
+	window, err := time.ParseDuration(raw)
+	if err != nil || window < step {
+		return 0, fmt.Errorf("invalid %s: %q (must hold at least one %s candle)", windowKey, raw, candle)
+	}
+	return window, nil
+}
+
+// parseMetricLabels parses METRIC_LABELS, a comma-separated list of optional labels or "none"
+func parseMetricLabels(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "none" {
+		return nil, nil
+	}
+
+	var labels []string
+	for _, label := range strings.Split(raw, ",") {
+		label = strings.TrimSpace(label)
+		if label == "" || slices.Contains(labels, label) {
+			continue
+		}
+		if !slices.Contains(optionalMetricLabels, label) {
+			return nil, fmt.Errorf("invalid METRIC_LABELS: unknown label %q (expected %s, or none)", label, strings.Join(optionalMetricLabels, ", "))
+		}
+		labels = append(labels, label)
+	}
+	return labels, nil
+}
+
+// parseLaunchpads parses LAUNCHPADS, a comma-separated list of Pulse source names. Aliases such as
+// pump.fun resolve to their listed name; launchpadOther is reserved for sources outside the list.
+func parseLaunchpads(raw string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(raw, ",") {
+		name = canonicalLaunchpad(name)
+		if name == "" || slices.Contains(names, name) {
+			continue
+		}
+		if name == launchpadOther || strings.ContainsFunc(name, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || strings.ContainsRune("._-", r))
+		}) {
+			return nil, fmt.Errorf("invalid LAUNCHPADS entry %q", name)
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("invalid LAUNCHPADS: %q", raw)
+	}
+	return names, nil
+}
+
+// parseEndpointList splits a comma-separated list of endpoint labels, rejecting unknown ones and dropping repeats
+func parseEndpointList(key string, raw string, known func(string) bool) ([]string, error) {
+	var endpoints []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known(name) {
+			return nil, fmt.Errorf("invalid %s: unknown endpoint %q", key, name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			endpoints = append(endpoints, name)
+		}
+	}
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("invalid %s: %q", key, raw)
+	}
+	return endpoints, nil
 }
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ============================================================================
+// Effective Config
+// One startup summary of what the process will run: providers, chains, intervals
+// and thresholds. Credentials and URLs are reported as present or absent only.
+// ============================================================================
+
+// secretConfigKeys are the keys whose values may carry credentials: API keys, the session
+// cookie, and URLs that can embed a password or an API key
+var secretConfigKeys = []string{
+	"COINGECKO_API_KEY",
+	"MOBULA_API_KEY",
+	"DEFINED_SESSION_COOKIE",
+	"GOLDRUSH_API_KEY",
+	"SYVE_API_KEY",
+	"BLOCK_TIMESTAMP_RPC",
+	"REDIS_URL",
+	"KAFKA_BROKERS",
+	"OTEL_EXPORTER_OTLP_ENDPOINT",
+	"PROVIDER_URLS",
+}
+
+// presence reports whether a config value is set, for values that must not be printed
+func presence(value string) string {
+	if value == "" {
+		return "absent"
+	}
+	return "present"
+}
+
+// enabledChains lists the registry chains a provider covers and PROVIDER_CHAINS leaves enabled
+func enabledChains(provider string, supported func(ChainInfo) bool) string {
+	var names []string
+	for _, chain := range chainRegistry {
+		if supported(chain) && chainEnabled(provider, chain.ChainName) {
+			names = append(names, chain.ChainName)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// geckoTerminalCovers reports whether a GeckoTerminal pool is monitored on the chain
+func geckoTerminalCovers(chain ChainInfo) bool {
+	for _, pool := range geckoTerminalPools {
+		if pool.Chain == chain.ChainName {
+			return true
+		}
+	}
+	return false
+}
+
+// logEffectiveConfig prints the effective configuration once the config is loaded and
+// PROVIDER_CHAINS is applied. It never prints a secret, see secretConfigKeys.
+func logEffectiveConfig(config *Config) {
+	fmt.Println("Effective config:")
+	fmt.Printf("  Region: %s | metrics on %s\n", config.MonitorRegion, config.MetricsAddr)
+
+	var credentials []string
+	for _, field := range config.configFields() {
+		if slices.Contains(secretConfigKeys, field.key) {
+			credentials = append(credentials, fmt.Sprintf("%s=%s", field.key, presence(*field.target)))
+		}
+	}
+	fmt.Printf("  Credentials: %s\n", strings.Join(credentials, " "))
+
+	if config.MobulaAPIKey != "" {
+		fmt.Printf("  mobula: enabled | chains: %s | REST endpoints: %s\n", enabledChains("mobula", ChainInfo.SupportsMobula), strings.Join(config.MobulaRESTEndpoints, ", "))
+	} else {
+		fmt.Println("  mobula: disabled (MOBULA_API_KEY absent)")
+	}
+	if config.DefinedSessionCookie != "" {
+		fmt.Printf("  codex: enabled | chains: %s | REST endpoints: %s | %s, events: %s\n", enabledChains("codex", ChainInfo.SupportsCodex), strings.Join(config.CodexRESTEndpoints, ", "), config.CodexConfirmation, strings.Join(config.CodexEventTypes, ", "))
+	} else {
+		fmt.Println("  codex: disabled (DEFINED_SESSION_COOKIE absent)")
+	}
+	if config.GoldRushAPIKey != "" {
+		fmt.Printf("  goldrush: enabled | chains: %s | REST endpoints: %s\n", enabledChains("goldrush", ChainInfo.SupportsGoldRush), strings.Join(config.GoldRushRESTEndpoints, ", "))
+	} else {
+		fmt.Println("  goldrush: disabled (GOLDRUSH_API_KEY absent)")
+	}
+	if config.SyveAPIKey != "" {
+		fmt.Printf("  syve: enabled | chains: %s | polled every %v\n", enabledChains("syve", syveSupports), config.SyvePollInterval)
+	} else {
+		fmt.Println("  syve: disabled (SYVE_API_KEY absent)")
+	}
+	fmt.Printf("  geckoterminal: enabled | chains: %s\n", enabledChains("geckoterminal", geckoTerminalCovers))
+	fmt.Printf("  Pools: %d monitored, %d overrides\n", len(monitoredPools()), len(config.PoolOverrides))
+	if overridden := config.ProviderURLs.overridden(); len(overridden) > 0 {
+		fmt.Printf("  Provider URLs overridden: %s\n", strings.Join(overridden, ", "))
+	}
+	if len(config.CEXReferenceSymbols) > 0 {
+		fmt.Printf("  CEX reference: %s\n", strings.Join(config.CEXReferenceSymbols, ", "))
+	}
+
+	fmt.Printf("  Intervals: REST %v, quotes %v, stats window %v (printed every %v), leaderboard %v, first-to-index window %v\n",
+		config.RESTInterval, config.QuoteInterval, config.StatsWindow, config.StatsPrintInterval, config.LeaderboardInterval, config.FirstToIndexWindow)
+	fmt.Printf("  Thresholds: outliers %s, percentiles from %d samples, min trade volume $%g, REST retries %d, log sample rate %d\n",
+		config.OutlierPolicy, config.StatsMinSamples, config.MinTradeVolumeUSD, config.RESTRetries, config.LogSampleRate)
+	if len(config.QuoteImpactSizes) > 0 {
+		var sizes []string
+		for _, size := range config.QuoteImpactSizes {
+			sizes = append(sizes, "$"+formatSizeUSD(size))
+		}
+		fmt.Printf("  Quote price impact: %s\n", strings.Join(sizes, ", "))
+	}
+	if config.RESTWarmup {
+		fmt.Printf("  REST warm-up: at startup, repeated every %v (0: never)\n", config.RESTWarmupInterval)
+	}
+	if len(config.BlockTimestampRPC) > 0 {
+		fmt.Printf("  Block timestamp checks: 1 in %d events on %d chains\n", config.BlockTimestampSampleRate, len(config.BlockTimestampRPC))
+	}
+}
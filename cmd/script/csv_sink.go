@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// CSV Sink
+// Appends every measured trade to a local CSV file for spreadsheet analysis
+// ============================================================================
+
+const (
+	csvSinkBufferSize    = 1000
+	csvSinkFlushInterval = 5 * time.Second
+)
+
+// What the CSV sink does once its file reaches CSV_MAX_SIZE_MB, see CSV_ROTATION
+const (
+	csvRotationRotate   = "rotate"   // Rename the file to <path>.1, replacing the previous one, and start a new file
+	csvRotationTruncate = "truncate" // Empty the file and start over
+)
+
+// csvHeader is written at the top of every new or emptied file
+var csvHeader = []string{"timestamp", "provider", "chain", "tx_hash", "lag_ms"}
+
+// countingWriter counts the bytes written through it, to size the file without a stat per row
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+type CSVSink struct {
+	path     string
+	maxBytes int64 // 0: unbounded
+	rotation string
+
+	file    *os.File
+	counter *countingWriter
+	writer  *csv.Writer
+
+	queue chan NormalizedTrade
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewCSVSink opens path for appending, writing the header if the file is new or empty,
+// and starts the background writer. maxMB of 0 lets the file grow without bound.
+func NewCSVSink(path string, maxMB int, rotation string) (*CSVSink, error) {
+	sink := &CSVSink{
+		path:     path,
+		maxBytes: int64(maxMB) << 20,
+		rotation: rotation,
+		queue:    make(chan NormalizedTrade, csvSinkBufferSize),
+		done:     make(chan struct{}),
+	}
+	if err := sink.open(os.O_APPEND); err != nil {
+		return nil, err
+	}
+
+	go sink.writeLoop()
+
+	return sink, nil
+}
+
+// Publish queues a trade for writing, dropping it if the buffer is full
+func (s *CSVSink) Publish(trade NormalizedTrade) {
+	select {
+	case s.queue <- trade:
+	default:
+		RecordSinkDropped("csv")
+	}
+}
+
+// Close stops accepting trades, writes what is buffered and closes the file
+func (s *CSVSink) Close() error {
+	s.once.Do(func() {
+		close(s.queue)
+	})
+	<-s.done
+
+	s.writer.Flush()
+	return s.file.Close()
+}
+
+// open opens the file with mode (os.O_APPEND or os.O_TRUNC) and writes the header if it is empty
+func (s *CSVSink) open(mode int) error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|mode, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open CSV_PATH: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat CSV_PATH: %w", err)
+	}
+
+	s.file = file
+	s.counter = &countingWriter{w: file, n: info.Size()}
+	s.writer = csv.NewWriter(s.counter)
+	if info.Size() == 0 {
+		s.writer.Write(csvHeader)
+	}
+	return nil
+}
+
+func (s *CSVSink) writeLoop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(csvSinkFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case trade, ok := <-s.queue:
+			if !ok {
+				return
+			}
+			s.write(trade)
+		case <-ticker.C:
+			s.writer.Flush()
+			if err := s.writer.Error(); err != nil {
+				log.Printf("[CSV-SINK] Write failed: %v", err)
+			}
+		}
+	}
+}
+
+func (s *CSVSink) write(trade NormalizedTrade) {
+	s.writer.Write([]string{
+		trade.ReceivedAt.UTC().Format(time.RFC3339Nano),
+		trade.Provider,
+		trade.Chain,
+		trade.TxHash,
+		strconv.FormatInt(trade.LagMs, 10),
+	})
+
+	// Rows sit in the csv.Writer buffer until it fills, so the size lags by at most that buffer
+	if s.maxBytes > 0 && s.counter.n >= s.maxBytes {
+		if err := s.rotate(); err != nil {
+			log.Printf("[CSV-SINK] %s failed: %v", s.rotation, err)
+		}
+	}
+}
+
+// rotate starts a new file once the current one reaches maxBytes, per the rotation mode
+func (s *CSVSink) rotate() error {
+	s.writer.Flush()
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	if s.rotation == csvRotationRotate {
+		if err := os.Rename(s.path, s.path+".1"); err != nil {
+			return err
+		}
+		return s.open(os.O_APPEND)
+	}
+	return s.open(os.O_TRUNC)
+}
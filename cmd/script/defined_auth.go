@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -22,14 +23,38 @@ type DefinedTokenResponse struct {
 
 // JWT token cache to avoid rate limiting
 type tokenCache struct {
-	mu          sync.RWMutex
-	token       string
-	expiresAt   time.Time
-	lastRefresh time.Time
+	mu           sync.RWMutex
+	token        string
+	expiresAt    time.Time
+	lastRefresh  time.Time
+	cookieSource string // Session cookie the token was generated from, see definedCookieSource
+	invalidated  bool   // Dropped after an auth failure, so the next generation is a session refresh
 }
 
 var globalTokenCache = &tokenCache{}
 
+// Where the session cookie behind the cached JWT came from
+const (
+	cookieSourceEnv     = "env"     // DEFINED_SESSION_COOKIE as configured
+	cookieSourceScraped = "scraped" // Scraped by RefreshSessionCookie
+)
+
+var (
+	scrapedCookieMu sync.RWMutex
+	scrapedCookie   string // Last cookie RefreshSessionCookie scraped
+)
+
+// definedCookieSource tells a scraped session cookie apart from the configured one
+func definedCookieSource(sessionCookie string) string {
+	scrapedCookieMu.RLock()
+	defer scrapedCookieMu.RUnlock()
+
+	if scrapedCookie != "" && sessionCookie == scrapedCookie {
+		return cookieSourceScraped
+	}
+	return cookieSourceEnv
+}
+
 // decodeJWTExpiration extracts the expiration time from a JWT token
 func decodeJWTExpiration(token string) (time.Time, error) {
 	parts := strings.Split(token, ".")
@@ -57,8 +82,9 @@ func decodeJWTExpiration(token string) (time.Time, error) {
 	return time.Unix(claims.Exp, 0), nil
 }
 
-// GetDefinedJWTToken returns a cached JWT token or generates a new one if expired
-func GetDefinedJWTToken(sessionCookie string) (string, error) {
+// GetDefinedJWTToken returns a cached JWT token or generates a new one if expired.
+// ctx bounds the generation request, so a shutdown does not wait on it.
+func GetDefinedJWTToken(ctx context.Context, sessionCookie string) (string, error) {
 	globalTokenCache.mu.RLock()
 
 	// Check if we have a valid cached token
@@ -66,6 +92,7 @@ func GetDefinedJWTToken(sessionCookie string) (string, error) {
 	if globalTokenCache.token != "" && time.Now().Before(globalTokenCache.expiresAt.Add(-1*time.Hour)) {
 		token := globalTokenCache.token
 		globalTokenCache.mu.RUnlock()
+		RecordDefinedTokenCacheHit()
 		return token, nil
 	}
 	globalTokenCache.mu.RUnlock()
@@ -76,14 +103,20 @@ func GetDefinedJWTToken(sessionCookie string) (string, error) {
 
 	// Double-check after acquiring write lock
 	if globalTokenCache.token != "" && time.Now().Before(globalTokenCache.expiresAt.Add(-1*time.Hour)) {
+		RecordDefinedTokenCacheHit()
 		return globalTokenCache.token, nil
 	}
 
 	// Generate new token
-	token, err := generateDefinedJWTToken(sessionCookie)
+	token, err := generateDefinedJWTToken(ctx, sessionCookie)
+	if globalTokenCache.invalidated && ctx.Err() == nil {
+		RecordSessionRefreshAttempt(err == nil)
+		globalTokenCache.invalidated = err != nil
+	}
 	if err != nil {
 		return "", err
 	}
+	RecordDefinedTokenGeneration()
 
 	// Decode expiration from token
 	expiresAt, err := decodeJWTExpiration(token)
@@ -96,6 +129,7 @@ func GetDefinedJWTToken(sessionCookie string) (string, error) {
 	globalTokenCache.token = token
 	globalTokenCache.expiresAt = expiresAt
 	globalTokenCache.lastRefresh = time.Now()
+	globalTokenCache.cookieSource = definedCookieSource(sessionCookie)
 
 	timeUntilExpiry := time.Until(expiresAt)
 	fmt.Printf("[DEFINED-AUTH] JWT token refreshed. Expires in %.1fh (at %s)\n",
@@ -104,8 +138,31 @@ func GetDefinedJWTToken(sessionCookie string) (string, error) {
 	return token, nil
 }
 
+// definedTokenSecondsUntilExpiry reports how long the cached JWT has left, 0 if none is cached
+func definedTokenSecondsUntilExpiry() float64 {
+	globalTokenCache.mu.RLock()
+	defer globalTokenCache.mu.RUnlock()
+
+	if globalTokenCache.token == "" {
+		return 0
+	}
+	return max(time.Until(globalTokenCache.expiresAt).Seconds(), 0)
+}
+
+// definedTokenAge reports how long the cached JWT has been in use and the source of its
+// session cookie; ok is false if none is cached
+func definedTokenAge() (age time.Duration, cookieSource string, ok bool) {
+	globalTokenCache.mu.RLock()
+	defer globalTokenCache.mu.RUnlock()
+
+	if globalTokenCache.token == "" {
+		return 0, "", false
+	}
+	return time.Since(globalTokenCache.lastRefresh), globalTokenCache.cookieSource, true
+}
+
 // generateDefinedJWTToken generates a new JWT token from Defined.fi session cookie
-func generateDefinedJWTToken(sessionCookie string) (string, error) {
+func generateDefinedJWTToken(ctx context.Context, sessionCookie string) (string, error) {
 	client := &http.Client{Timeout: 10 * time.Second}
 
 	reqBody := map[string]interface{}{
@@ -115,7 +172,7 @@ func generateDefinedJWTToken(sessionCookie string) (string, error) {
 	}
 
 	bodyBytes, _ := json.Marshal(reqBody)
-	req, _ := http.NewRequest("POST", "https://www.defined.fi/api", bytes.NewBuffer(bodyBytes))
+	req, _ := http.NewRequestWithContext(ctx, "POST", providerURLs.DefinedAPI, bytes.NewBuffer(bodyBytes))
 
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
@@ -140,6 +197,8 @@ func generateDefinedJWTToken(sessionCookie string) (string, error) {
 	respBody, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode == 429 {
+		RecordDefinedTokenRateLimited()
+
 		// Parse retry-after header if available
 		retryAfter := resp.Header.Get("Retry-After")
 		if retryAfter != "" {
@@ -149,7 +208,7 @@ func generateDefinedJWTToken(sessionCookie string) (string, error) {
 	}
 
 	if resp.StatusCode != 200 {
-		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody[:min(len(respBody), 100)]))
+		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, safeTruncate(string(respBody), 100))
 	}
 
 	var tokenResp DefinedTokenResponse
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// withProviderURL points one provider endpoint at url for the test
+func withProviderURL(t *testing.T, field *string, url string) {
+	t.Helper()
+	saved := providerURLs
+	*field = url
+	t.Cleanup(func() { providerURLs = saved })
+}
+
+// withFreshTokenCache gives the test an empty Defined.fi JWT cache
+func withFreshTokenCache(t *testing.T) {
+	t.Helper()
+	saved := globalTokenCache
+	globalTokenCache = &tokenCache{}
+	t.Cleanup(func() { globalTokenCache = saved })
+}
+
+// testJWT returns an unsigned JWT expiring at exp, enough for decodeJWTExpiration
+func testJWT(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return "eyJhbGciOiJub25lIn0." + payload + ".sig"
+}
+
+// definedTokenServer answers JWT generation requests with the given statuses in turn, issuing a token on 200
+func definedTokenServer(t *testing.T, statuses ...int) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		status := http.StatusOK
+		if len(statuses) > 0 {
+			status, statuses = statuses[0], statuses[1:]
+		}
+		mu.Unlock()
+
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"createApiTokens":[{"token":%q}]}}`, testJWT(time.Now().Add(24*time.Hour)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSessionRefreshCountedAfterAuthFailure(t *testing.T) {
+	withFreshTokenCache(t)
+	server := definedTokenServer(t, http.StatusOK, http.StatusInternalServerError, http.StatusOK)
+	withProviderURL(t, &providerURLs.DefinedAPI, server.URL)
+
+	success := sessionRefreshAttempts.WithLabelValues("success")
+	failure := sessionRefreshAttempts.WithLabelValues("error")
+	successBefore, failureBefore := testutil.ToFloat64(success), testutil.ToFloat64(failure)
+
+	steps := []struct {
+		name        string
+		invalidate  bool
+		wantErr     bool
+		wantSuccess float64
+		wantFailure float64
+	}{
+		{name: "first token", wantSuccess: 0, wantFailure: 0},
+		{name: "cached token", wantSuccess: 0, wantFailure: 0},
+		{name: "refresh after auth failure fails", invalidate: true, wantErr: true, wantSuccess: 0, wantFailure: 1},
+		{name: "retried refresh succeeds", wantSuccess: 1, wantFailure: 1},
+		{name: "cached again", wantSuccess: 1, wantFailure: 1},
+	}
+	for _, step := range steps {
+		if step.invalidate {
+			InvalidateTokenCache()
+		}
+		_, err := GetDefinedJWTToken(context.Background(), "cookie")
+		if (err != nil) != step.wantErr {
+			t.Fatalf("%s: GetDefinedJWTToken error = %v, want error %v", step.name, err, step.wantErr)
+		}
+		if got := testutil.ToFloat64(success) - successBefore; got != step.wantSuccess {
+			t.Errorf("%s: session_refresh_attempts_total{result=\"success\"} = %v, want %v", step.name, got, step.wantSuccess)
+		}
+		if got := testutil.ToFloat64(failure) - failureBefore; got != step.wantFailure {
+			t.Errorf("%s: session_refresh_attempts_total{result=\"error\"} = %v, want %v", step.name, got, step.wantFailure)
+		}
+	}
+}
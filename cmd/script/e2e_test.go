@@ -0,0 +1,528 @@
+//go:build e2e
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// ============================================================================
+// End-to-end test (go test -tags e2e ./cmd/script)
+// Runs the full monitor set against in-process mock providers that stream
+// synthetic trades of a known lag, then stops it and checks the metrics it
+// recorded and that every monitor returned within a deadline
+// ============================================================================
+
+const (
+	e2eDuration         = 30 * time.Second
+	e2eShutdownDeadline = 15 * time.Second
+
+	// Every mock trade is sent e2eTradeLag after its on-chain time, one per feed every e2eTradeInterval
+	e2eTradeLag      = 2 * time.Second
+	e2eTradeInterval = 200 * time.Millisecond
+
+	// Head lag above e2eTradeLag still accepted: Codex timestamps are whole seconds, plus local delivery
+	e2eLagSlack = 1500 * time.Millisecond
+)
+
+// e2eSettings stand in for the environment and .env, so the run never reaches a real provider.
+// PROVIDER_URLS is added once the mock server is up, see mockProviderURLs.
+var e2eSettings = map[string]string{
+	"MOBULA_API_KEY":         "e2e",
+	"DEFINED_SESSION_COOKIE": "e2e",
+	"GOLDRUSH_API_KEY":       "e2e",
+	"SYVE_API_KEY":           "e2e",
+	"MONITOR_REGION":         "e2e",
+	"CEX_REFERENCE_SYMBOLS":  "BTCUSDT",
+	"REST_INTERVAL":          "5s",
+	"QUOTE_INTERVAL":         "10s",
+	"STATS_PRINT_INTERVAL":   "10s",
+	"LEADERBOARD_INTERVAL":   "10s",
+	"STATS_MIN_SAMPLES":      "1",
+}
+
+// e2eHeadLagFeeds are the WebSocket feeds whose mock trades must come out as head lag
+var e2eHeadLagFeeds = []string{"mobula", "codex", "geckoterminal"}
+
+func TestEndToEnd(t *testing.T) {
+	mock := newMockProviders()
+	defer mock.Close()
+	saved := providerURLs
+	t.Cleanup(func() { initProviderURLs(saved) })
+
+	config := &Config{Sources: make(map[string]string)}
+	for _, field := range config.configFields() {
+		if value, ok := e2eSettings[field.key]; ok {
+			*field.target = value
+		}
+		if field.key == "PROVIDER_URLS" {
+			*field.target = mockProviderURLs(mock.server.URL)
+		}
+	}
+	if err := applyConfigDefaults(config); err != nil {
+		t.Fatalf("applyConfigDefaults: %v", err)
+	}
+	applyHotSettings(config)
+	initProviderChains(config.ProviderChains)
+	initProviderURLs(config.ProviderURLs)
+	initMetricLabels(config.MetricLabels)
+	initLaunchpads(config.Launchpads)
+	initPoolOverrides(config.PoolOverrides)
+	initMetadataQueue(config.MetadataQueueSize)
+	initMetadataLimits(config.MetadataConcurrency)
+	initMoralisQueue(config.MoralisQueueSize)
+
+	promRec := NewPrometheusRecorder(config.EWMAAlpha, config.TradeTypeMetrics, config.PreciseLagMetrics)
+	latencyWindow := NewLatencyWindow(e2eDuration, config.OutlierPolicy, "", config.StatsMinSamples)
+	promRec.AddSink(latencyWindow)
+	firstToIndex := NewFirstToIndex(config.FirstToIndexWindow)
+	promRec.AddSink(firstToIndex)
+
+	var wg sync.WaitGroup
+	stopChan := make(chan struct{})
+	startMonitors(config, promRec, latencyWindow, firstToIndex, stopChan, &wg)
+
+	time.Sleep(e2eDuration)
+	stopStart := time.Now()
+	close(stopChan)
+	if !waitOrTimeout(&wg, e2eShutdownDeadline) {
+		// Sinks stay open, a monitor still running may yet publish to them
+		t.Fatalf("monitors still running %v after stopChan closed", e2eShutdownDeadline)
+	}
+	promRec.Close()
+	t.Logf("every monitor stopped in %dms", time.Since(stopStart).Milliseconds())
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	checkE2EMetrics(t, families)
+}
+
+// checkE2EMetrics checks that each monitor recorded what its mock provider served
+func checkE2EMetrics(t *testing.T, families []*dto.MetricFamily) {
+	t.Helper()
+
+	for _, feed := range e2eHeadLagFeeds {
+		trades, _ := e2eMetric(families, "trades_observed_total", map[string]string{"aggregator": feed})
+		samples, sum := e2eMetric(families, "head_lag_summary_milliseconds", map[string]string{"aggregator": feed})
+		if trades == 0 || samples == 0 {
+			t.Errorf("%s: no trades recorded", feed)
+			continue
+		}
+		mean := time.Duration(sum/samples) * time.Millisecond
+		if mean < e2eTradeLag || mean > e2eTradeLag+e2eLagSlack {
+			t.Errorf("%s: mean head lag %v over %.0f trades, want %v to %v", feed, mean, trades, e2eTradeLag, e2eTradeLag+e2eLagSlack)
+			continue
+		}
+		t.Logf("%s: %.0f trades, mean head lag %v", feed, trades, mean)
+	}
+
+	if cex, _ := e2eMetric(families, "cex_reference_latency_milliseconds", map[string]string{"exchange": "binance"}); cex == 0 {
+		t.Error("cex-reference: no trades recorded")
+	}
+
+	for _, aggregator := range []string{"mobula", "codex", "goldrush"} {
+		if calls, _ := e2eMetric(families, "rest_api_latency_milliseconds", map[string]string{"aggregator": aggregator}); calls == 0 {
+			t.Errorf("%s REST: no calls recorded", aggregator)
+		}
+	}
+	if quotes, _ := e2eMetric(families, "quote_api_latency_milliseconds", nil); quotes == 0 {
+		t.Error("quote APIs: no calls recorded")
+	}
+
+	// Every key is set, so a monitor reporting itself skipped is miswired
+	for _, family := range families {
+		if family.GetName() != "provider_enabled" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if metric.GetGauge().GetValue() == 1 {
+				continue
+			}
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			t.Errorf("%s %s: skipped", labels["provider"], labels["monitor"])
+		}
+	}
+}
+
+// e2eMetric adds up the series of a metric matching labels, nil matching all: count is the value of a
+// counter or gauge or the observations of a histogram or summary, sum the summaries' observed total
+func e2eMetric(families []*dto.MetricFamily, name string, labels map[string]string) (count float64, sum float64) {
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	series:
+		for _, metric := range family.GetMetric() {
+			for key, value := range labels {
+				matched := false
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == key && label.GetValue() == value {
+						matched = true
+						break
+					}
+				}
+				if !matched {
+					continue series
+				}
+			}
+
+			switch {
+			case metric.Counter != nil:
+				count += metric.GetCounter().GetValue()
+			case metric.Gauge != nil:
+				count += metric.GetGauge().GetValue()
+			case metric.Histogram != nil:
+				count += float64(metric.GetHistogram().GetSampleCount())
+			case metric.Summary != nil:
+				count += float64(metric.GetSummary().GetSampleCount())
+				sum += metric.GetSummary().GetSampleSum()
+			}
+		}
+	}
+	return count, sum
+}
+
+// mockProviderURLs is the PROVIDER_URLS value sending every monitor's provider traffic to the mock server at base
+func mockProviderURLs(base string) string {
+	ws := "ws" + strings.TrimPrefix(base, "http")
+	return strings.Join([]string{
+		"mobula-ws=" + ws + "/mobula",
+		"mobula-pulse-ws=" + ws + "/mobula-pulse",
+		"mobula-rest=" + base + "/mobula",
+		"codex-ws=" + ws + "/codex",
+		"codex-graphql=" + base + "/codex",
+		"defined-api=" + base + "/defined",
+		"geckoterminal-ws=" + ws + "/geckoterminal",
+		"geckoterminal-rest=" + base + "/geckoterminal",
+		"goldrush-rest=" + base + "/goldrush",
+		"syve-rest=" + base + "/syve",
+		"binance-ws=" + ws + "/binance",
+		"jupiter-quote=" + base + "/quote/jupiter",
+		"jupiter-tokens=" + base + "/jupiter/tokens",
+		"openocean-quote=" + base + "/quote/openocean",
+		"paraswap-quote=" + base + "/quote/paraswap",
+		"kyberswap-quote=" + base + "/quote/kyberswap",
+		"lifi-quote=" + base + "/quote/lifi",
+	}, ",")
+}
+
+// mockProviders serves every provider the monitors reach from one local server. REST paths get an
+// empty JSON object, enough for a latency sample; WebSocket paths stream trades once subscribed.
+type mockProviders struct {
+	server  *httptest.Server
+	closing chan struct{}
+}
+
+var mockUpgrader = websocket.Upgrader{
+	Subprotocols: []string{"graphql-transport-ws"},
+	CheckOrigin:  func(*http.Request) bool { return true }, // GeckoTerminal sends its own Origin
+}
+
+func newMockProviders() *mockProviders {
+	m := &mockProviders{closing: make(chan struct{})}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsWebSocketUpgrade(r) {
+			m.serveFeed(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("{}"))
+	})
+	mux.HandleFunc("POST /defined", serveMockDefinedToken)
+
+	m.server = httptest.NewServer(mux)
+	return m
+}
+
+// Close ends every mock feed, then the server
+func (m *mockProviders) Close() {
+	close(m.closing)
+	m.server.Close()
+}
+
+// serveMockDefinedToken issues a JWT valid for a day, which is all the Codex monitors check
+func serveMockDefinedToken(w http.ResponseWriter, r *http.Request) {
+	claims, _ := json.Marshal(map[string]int64{"exp": time.Now().Add(24 * time.Hour).Unix()})
+	token := "e2e." + base64.RawURLEncoding.EncodeToString(claims) + ".e2e"
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": map[string]any{"createApiTokens": []map[string]string{{"token": token}}},
+	})
+}
+
+// mockFeed is one mock WebSocket connection. Subscriptions arrive on the read side while trades go
+// out on a ticker, so writes are serialized.
+type mockFeed struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu            sync.Mutex
+	subscriptions []any
+}
+
+func (f *mockFeed) write(v any) error {
+	f.writeMu.Lock()
+	defer f.writeMu.Unlock()
+
+	f.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	return f.conn.WriteJSON(v)
+}
+
+func (f *mockFeed) subscribe(subscription any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subscriptions = append(f.subscriptions, subscription)
+}
+
+// subscription returns the subscriptions round-robin, false before the first one
+func (f *mockFeed) subscription(seq int) (any, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.subscriptions) == 0 {
+		return nil, false
+	}
+	return f.subscriptions[seq%len(f.subscriptions)], true
+}
+
+// mockFeedProtocol is how a mock provider answers client frames and what its trades look like
+type mockFeedProtocol struct {
+	handle func(f *mockFeed, message []byte)
+	trade  func(subscription any, seq int, onChainTime time.Time) any // nil for a feed that streams no trades
+}
+
+// mockFeedProtocols are keyed by the path mockProviderURLs gives each feed
+var mockFeedProtocols = map[string]mockFeedProtocol{
+	"/mobula":        {handleMockMobula, mockMobulaTrade},
+	"/codex":         {handleMockCodex, mockCodexTrade},
+	"/geckoterminal": {handleMockGecko, mockGeckoTrade},
+	"/mobula-pulse":  {func(*mockFeed, []byte) {}, nil},
+	"/binance":       {func(*mockFeed, []byte) {}, mockBinanceTrade},
+}
+
+// serveFeed runs a mock feed until the client leaves or the mocks close
+func (m *mockProviders) serveFeed(w http.ResponseWriter, r *http.Request) {
+	protocol, ok := mockFeedProtocols[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	conn, err := mockUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	f := &mockFeed{conn: conn}
+	if r.URL.Path == "/binance" {
+		// The symbols are in the stream URL instead of a subscribe frame
+		f.subscribe(strings.ToUpper(strings.TrimSuffix(r.URL.Query().Get("streams"), "@aggTrade")))
+	}
+
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			protocol.handle(f, message)
+		}
+	}()
+
+	ticker := time.NewTicker(e2eTradeInterval)
+	defer ticker.Stop()
+
+	for seq := 0; ; {
+		select {
+		case <-m.closing:
+			return
+		case <-readDone:
+			return
+		case <-ticker.C:
+		}
+		if protocol.trade == nil {
+			continue
+		}
+		subscription, ok := f.subscription(seq)
+		if !ok {
+			continue
+		}
+		if err := f.write(protocol.trade(subscription, seq, time.Now().Add(-e2eTradeLag))); err != nil {
+			return
+		}
+		seq++
+	}
+}
+
+// e2eTxHash is a transaction hash unique to a feed and sequence number
+func e2eTxHash(feed string, seq int) string {
+	return fmt.Sprintf("0xe2e%s%08d", feed, seq)
+}
+
+type mockMobulaSubscription struct {
+	Blockchain string `json:"blockchain"`
+	Address    string `json:"address"`
+}
+
+// handleMockMobula subscribes to every item of a fast-trade frame; pings need no answer
+func handleMockMobula(f *mockFeed, message []byte) {
+	var frame struct {
+		Type    string `json:"type"`
+		Payload struct {
+			Items []mockMobulaSubscription `json:"items"`
+		} `json:"payload"`
+	}
+	if json.Unmarshal(message, &frame) != nil || frame.Type != "fast-trade" {
+		return
+	}
+	for _, item := range frame.Payload.Items {
+		f.subscribe(item)
+	}
+}
+
+func mockMobulaTrade(subscription any, seq int, onChainTime time.Time) any {
+	item := subscription.(mockMobulaSubscription)
+	return map[string]any{
+		"blockchain":     item.Blockchain,
+		"pair":           item.Address,
+		"hash":           e2eTxHash("mobula", seq),
+		"type":           "buy",
+		"date":           onChainTime.UnixMilli(),
+		"timestamp":      time.Now().UnixMilli(),
+		"tokenPrice":     1,
+		"tokenAmountUsd": 1000,
+	}
+}
+
+type mockCodexSubscription struct {
+	id          string
+	address     string
+	networkID   int
+	unconfirmed bool
+}
+
+// handleMockCodex speaks enough graphql-transport-ws for the monitor: it acknowledges the connection,
+// answers pings and records pool subscriptions, leaving metadata subscriptions quiet
+func handleMockCodex(f *mockFeed, message []byte) {
+	var frame CodexWSMessage
+	if json.Unmarshal(message, &frame) != nil {
+		return
+	}
+
+	switch frame.Type {
+	case "connection_init":
+		f.write(map[string]string{"type": "connection_ack"})
+	case "ping":
+		f.write(map[string]string{"type": "pong"})
+	case "subscribe":
+		var payload struct {
+			Query     string `json:"query"`
+			Variables struct {
+				ID        string `json:"id"`
+				Address   string `json:"address"`
+				NetworkID int    `json:"networkId"`
+			} `json:"variables"`
+		}
+		if json.Unmarshal(frame.Payload, &payload) != nil || !strings.HasPrefix(frame.ID, "headlag_") {
+			return
+		}
+
+		subscription := mockCodexSubscription{id: frame.ID, address: payload.Variables.Address, networkID: payload.Variables.NetworkID}
+		if strings.Contains(payload.Query, "onUnconfirmedEventsCreated") {
+			// Unconfirmed subscriptions name the pool as "<address>:<networkId>"
+			subscription.unconfirmed = true
+			address, network, _ := strings.Cut(payload.Variables.ID, ":")
+			subscription.address = address
+			fmt.Sscanf(network, "%d", &subscription.networkID)
+		}
+		f.subscribe(subscription)
+	}
+}
+
+func mockCodexTrade(subscription any, seq int, onChainTime time.Time) any {
+	sub := subscription.(mockCodexSubscription)
+	field := "onEventsCreated"
+	if sub.unconfirmed {
+		field = "onUnconfirmedEventsCreated"
+	}
+	return map[string]any{
+		"type": "next",
+		"id":   sub.id,
+		"payload": map[string]any{
+			"data": map[string]any{
+				field: map[string]any{
+					"address":   sub.address,
+					"networkId": sub.networkID,
+					"events": []map[string]any{{
+						"blockNumber":      seq + 1,
+						"timestamp":        onChainTime.Unix(),
+						"transactionHash":  e2eTxHash("codex", seq),
+						"eventType":        codexSwapEvent,
+						"eventDisplayType": "Buy",
+						"data":             map[string]any{"priceUsdTotal": "1000"},
+					}},
+				},
+			},
+		},
+	}
+}
+
+// handleMockGecko confirms every SwapChannel subscription, keyed by its identifier
+func handleMockGecko(f *mockFeed, message []byte) {
+	var frame GeckoActionCableMessage
+	if json.Unmarshal(message, &frame) != nil || frame.Command != "subscribe" {
+		return
+	}
+	f.write(GeckoActionCableMessage{Type: "confirm_subscription", Identifier: frame.Identifier})
+	f.subscribe(frame.Identifier)
+}
+
+func mockGeckoTrade(subscription any, seq int, onChainTime time.Time) any {
+	return map[string]any{
+		"identifier": subscription.(string),
+		"message": map[string]any{
+			"type": "newSwap",
+			"data": map[string]any{
+				"block_timestamp": onChainTime.UnixMilli(),
+				"tx_hash":         e2eTxHash("gecko", seq),
+				"vo":              "1000",
+				"ty":              "b",
+			},
+		},
+	}
+}
+
+// mockBinanceTrade streams aggTrades as matched now, Binance having no indexation step
+func mockBinanceTrade(subscription any, seq int, onChainTime time.Time) any {
+	symbol := subscription.(string)
+	return map[string]any{
+		"stream": strings.ToLower(symbol) + "@aggTrade",
+		"data": map[string]any{
+			"e": "aggTrade",
+			"s": symbol,
+			"T": time.Now().UnixMilli(),
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// First-to-Index Race
+// Matches the same transaction across the head lag feeds to see which provider
+// streamed it first, and which transactions some providers never streamed
+// ============================================================================
+
+// firstToIndexMaxPending caps the transactions waiting for the other providers.
+// Transactions first seen while the cap is reached are not raced.
+const firstToIndexMaxPending = 50000
+
+// pendingTx is a transaction reported by at least one head lag feed, waiting for the others
+type pendingTx struct {
+	chain    string
+	region   string
+	first    string
+	firstAt  time.Time
+	seen     map[string]time.Time // Provider -> when it first reported the transaction
+	expected []string             // Providers subscribed to the pool, nil if the pool is not in the registry
+}
+
+// FirstToIndex is a Sink that races providers on each transaction. A transaction is settled
+// as soon as every provider subscribed to its pool reported it, or once the compare window
+// has passed since the first report, whichever comes first.
+type FirstToIndex struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingTx
+}
+
+func NewFirstToIndex(window time.Duration) *FirstToIndex {
+	return &FirstToIndex{
+		window:  window,
+		pending: make(map[string]*pendingTx),
+	}
+}
+
+// Publish adds a provider's report of a transaction. Repeated reports from the same
+// provider, one per swap of a multi-swap transaction, count once.
+func (f *FirstToIndex) Publish(trade NormalizedTrade) {
+	if trade.TxHash == "" {
+		return
+	}
+
+	txHash := trade.TxHash
+	if strings.HasPrefix(txHash, "0x") {
+		txHash = strings.ToLower(txHash)
+	}
+	// Each region races on its own: network distance to the providers differs per vantage point
+	key := trade.Region + ":" + trade.Chain + ":" + txHash
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tx, ok := f.pending[key]
+	if !ok {
+		if len(f.pending) >= firstToIndexMaxPending {
+			return
+		}
+		f.pending[key] = &pendingTx{
+			chain:    trade.Chain,
+			region:   trade.Region,
+			first:    trade.Provider,
+			firstAt:  trade.ReceivedAt,
+			seen:     map[string]time.Time{trade.Provider: trade.ReceivedAt},
+			expected: expectedProviders(trade.Pool),
+		}
+		return
+	}
+	if _, ok := tx.seen[trade.Provider]; !ok {
+		tx.seen[trade.Provider] = trade.ReceivedAt
+	}
+
+	if tx.expected != nil && len(tx.seen) >= len(tx.expected) {
+		delete(f.pending, key)
+		tx.settle()
+	}
+}
+
+// Flush settles every transaction whose compare window has passed
+func (f *FirstToIndex) Flush(now time.Time) {
+	f.mu.Lock()
+	var expired []*pendingTx
+	for key, tx := range f.pending {
+		if now.Sub(tx.firstAt) >= f.window {
+			expired = append(expired, tx)
+			delete(f.pending, key)
+		}
+	}
+	f.mu.Unlock()
+
+	for _, tx := range expired {
+		tx.settle()
+	}
+}
+
+func (f *FirstToIndex) Close() error {
+	return nil
+}
+
+// settle credits the first provider when at least two reported the transaction, records how far
+// each of them was from the median report, and counts transactions that only part of the
+// subscribed providers reported
+func (tx *pendingTx) settle() {
+	if len(tx.seen) >= 2 {
+		RecordFirstToIndex(tx.first, tx.chain, tx.region)
+		tx.recordRelativeLag()
+	}
+	if tx.expected != nil && len(tx.seen) < len(tx.expected) {
+		seenBy := make([]string, 0, len(tx.seen))
+		for provider := range tx.seen {
+			seenBy = append(seenBy, provider)
+		}
+		sort.Strings(seenBy)
+		RecordTxPartiallySeen(strings.Join(seenBy, "+"), tx.chain, tx.region)
+	}
+}
+
+// recordRelativeLag records each provider's report time minus the median report time of the transaction.
+// All providers saw the same on-chain event, so this equals its lag minus the median lag, without
+// depending on how precisely each provider timestamps the event.
+func (tx *pendingTx) recordRelativeLag() {
+	times := make([]time.Time, 0, len(tx.seen))
+	for _, at := range tx.seen {
+		times = append(times, at)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	// With an even count the median lies halfway between the two middle reports
+	median := times[len(times)/2]
+	if len(times)%2 == 0 {
+		median = times[len(times)/2-1].Add(median.Sub(times[len(times)/2-1]) / 2)
+	}
+
+	for provider, at := range tx.seen {
+		RecordProviderRelativeLag(provider, tx.chain, float64(at.Sub(median).Milliseconds()), tx.region)
+	}
+}
+
+// expectedProviders returns the compared providers subscribed to a pool, nil if the pool is not in the registry
+func expectedProviders(address string) []string {
+	pool, ok := lookupPool(address)
+	if !ok {
+		return nil
+	}
+
+	providers := []string{}
+	for _, provider := range comparedProviders {
+		if provider.covers(pool) {
+			providers = append(providers, provider.name)
+		}
+	}
+	return providers
+}
+
+// runFirstToIndexFlusher settles expired transactions several times per compare window
+func runFirstToIndexFlusher(race *FirstToIndex, stopChan <-chan struct{}) {
+	ticker := time.NewTicker(max(race.window/4, 100*time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case now := <-ticker.C:
+			race.Flush(now)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Frame Capture
+// Appends every raw WebSocket frame of the head lag feeds to CAPTURE_DIR, one
+// JSON line per frame, for the replay subcommand
+// ============================================================================
+
+// CapturedFrame is one line of a capture file
+type CapturedFrame struct {
+	Provider   string    `json:"provider"`
+	ReceivedAt time.Time `json:"received_at"`
+	Frame      string    `json:"frame"` // Raw frame as received, not necessarily valid JSON
+}
+
+var frameCapture = struct {
+	sync.Mutex
+	dir   string
+	files map[string]*os.File // provider -> <dir>/<provider>.jsonl
+}{files: make(map[string]*os.File)}
+
+// initFrameCapture enables capture into dir, creating it if needed; an empty dir leaves capture off
+func initFrameCapture(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create CAPTURE_DIR: %w", err)
+	}
+
+	frameCapture.Lock()
+	defer frameCapture.Unlock()
+	frameCapture.dir = dir
+	return nil
+}
+
+// captureFrame appends a frame to the provider's capture file; a no-op unless capture is enabled
+func captureFrame(provider string, receivedAt time.Time, frame []byte) {
+	frameCapture.Lock()
+	defer frameCapture.Unlock()
+	if frameCapture.dir == "" {
+		return
+	}
+
+	file, ok := frameCapture.files[provider]
+	if !ok {
+		path := filepath.Join(frameCapture.dir, provider+".jsonl")
+		var err error
+		file, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			// Capture is a debugging aid, losing it must not stop the monitor
+			log.Printf("[CAPTURE] Failed to open %s, capture disabled: %v", path, err)
+			frameCapture.dir = ""
+			return
+		}
+		frameCapture.files[provider] = file
+	}
+
+	line, err := json.Marshal(CapturedFrame{Provider: provider, ReceivedAt: receivedAt, Frame: string(frame)})
+	if err != nil {
+		return
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		log.Printf("[CAPTURE] Failed to write %s frame, capture disabled: %v", provider, err)
+		frameCapture.dir = ""
+	}
+}
+
+// closeFrameCapture closes every capture file
+func closeFrameCapture() {
+	frameCapture.Lock()
+	defer frameCapture.Unlock()
+	for provider, file := range frameCapture.files {
+		file.Close()
+		delete(frameCapture.files, provider)
+	}
+	frameCapture.dir = ""
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"sync"
 	"time"
 
@@ -15,47 +18,53 @@ import (
 // ============================================================================
 
 const (
-	geckoWSURL     = "wss://cables.geckoterminal.com/cable"
 	geckoOrigin    = "https://www.geckoterminal.com"
 	geckoUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36"
 )
 
-// GeckoTerminal pools (pool_id extracted via reverse engineering)
+// GeckoTerminal pools (pool_id extracted via reverse engineering).
+// Registry chains without an entry here are skipped, see logGeckoSkippedChains.
 var geckoTerminalPools = []struct {
 	Name    string
 	Network string
 	PoolID  string
 	Chain   string
+	Address string // Same pool in the registry
 }{
 	{
 		Name:    "ETH/USDC Uniswap V3",
 		Network: "eth",
 		PoolID:  "147971598",
 		Chain:   "ethereum",
+		Address: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
 	},
 	{
 		Name:    "SOL/USDC Raydium",
 		Network: "solana",
 		PoolID:  "162715608",
 		Chain:   "solana",
+		Address: "7qbRF6YsyGuLUVs6Y1q64bdVrfe4ZcUUz1JRdoVNUJnm",
 	},
 	{
 		Name:    "WETH/USDC Base",
 		Network: "base",
 		PoolID:  "162840764",
 		Chain:   "base",
+		Address: "0x4c36388be6f416a29c8d8eee81c771ce6be14b18",
 	},
 	{
 		Name:    "WBNB/BUSD PancakeSwap",
 		Network: "bsc",
 		PoolID:  "24",
 		Chain:   "bnb",
+		Address: "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16",
 	},
 	{
 		Name:    "WETH/USDC Arbitrum",
 		Network: "arbitrum",
 		PoolID:  "162634438",
 		Chain:   "arbitrum",
+		Address: "0xc6962004f452be9203591991d15f6b388e09e8d0",
 	},
 }
 
@@ -65,6 +74,7 @@ type GeckoActionCableMessage struct {
 	Command    string          `json:"command,omitempty"`
 	Identifier string          `json:"identifier,omitempty"`
 	Message    json.RawMessage `json:"message,omitempty"`
+	Reason     string          `json:"reason,omitempty"` // Why the server sent "disconnect"
 }
 
 type GeckoChannelIdentifier struct {
@@ -75,17 +85,24 @@ type GeckoChannelIdentifier struct {
 // Swap data from SwapChannel
 type GeckoSwapData struct {
 	Data struct {
-		BlockTimestamp int64  `json:"block_timestamp"` // On-chain timestamp (ms)
-		TxHash         string `json:"tx_hash"`
+		BlockTimestamp int64     `json:"block_timestamp"` // On-chain timestamp (ms)
+		TxHash         string    `json:"tx_hash"`
+		Vo             usdAmount `json:"vo"` // Swap volume in USD, see MIN_TRADE_VOLUME_USD
+		Ty             string    `json:"ty"` // Direction: "b" buy, "s" sell
 		// Other fields available but not needed for head lag
 	} `json:"data"`
 	Type string `json:"type"` // "newSwap"
 }
 
-func runGeckoTerminalHeadLagMonitor(config *Config, stopChan <-chan struct{}, wg *sync.WaitGroup) {
+func runGeckoTerminalHeadLagMonitor(config *Config, rec Recorder, stopChan <-chan struct{}, wg *sync.WaitGroup) {
 	defer wg.Done()
 
+	rec.RecordProviderStatus("geckoterminal", "websocket", "", config.MonitorRegion)
 	fmt.Println("[HEAD-LAG][GECKO] Starting WebSocket monitor...")
+	logGeckoSkippedChains()
+
+	ctx, cancel := stopContext(stopChan)
+	defer cancel()
 
 	reconnectDelay := 5 * time.Second
 	maxReconnectDelay := 60 * time.Second
@@ -96,18 +113,16 @@ func runGeckoTerminalHeadLagMonitor(config *Config, stopChan <-chan struct{}, wg
 			fmt.Println("[HEAD-LAG][GECKO] Monitor stopped")
 			return
 		default:
-			err := connectAndMonitorGecko(config, stopChan)
+			err := connectAndMonitorGecko(ctx, config, rec, stopChan)
 			if err != nil {
 				log.Printf("[HEAD-LAG][GECKO] Connection error: %v. Reconnecting in %v...", err, reconnectDelay)
 
-				select {
-				case <-stopChan:
+				if !backoffOrStop(rec, "geckoterminal", stopChan, reconnectDelay, config.MonitorRegion) {
 					return
-				case <-time.After(reconnectDelay):
-					reconnectDelay = reconnectDelay * 2
-					if reconnectDelay > maxReconnectDelay {
-						reconnectDelay = maxReconnectDelay
-					}
+				}
+				reconnectDelay = reconnectDelay * 2
+				if reconnectDelay > maxReconnectDelay {
+					reconnectDelay = maxReconnectDelay
 				}
 			} else {
 				reconnectDelay = 5 * time.Second
@@ -116,44 +131,92 @@ func runGeckoTerminalHeadLagMonitor(config *Config, stopChan <-chan struct{}, wg
 	}
 }
 
-func connectAndMonitorGecko(config *Config, stopChan <-chan struct{}) error {
+// logGeckoSkippedChains reports registry chains that GeckoTerminal cannot be benchmarked on
+func logGeckoSkippedChains() {
+	for _, chain := range chainRegistry {
+		covered := false
+		for _, pool := range geckoTerminalPools {
+			if pool.Chain == chain.ChainName {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			fmt.Printf("[HEAD-LAG][GECKO] Skipping %s: no GeckoTerminal pool_id known\n", chain.ChainName)
+		} else if !chainEnabled("geckoterminal", chain.ChainName) {
+			fmt.Printf("[HEAD-LAG][GECKO] Skipping %s: disabled by PROVIDER_CHAINS\n", chain.ChainName)
+		}
+	}
+}
+
+func connectAndMonitorGecko(ctx context.Context, config *Config, rec Recorder, stopChan <-chan struct{}) error {
 	headers := map[string][]string{
 		"Origin":     {geckoOrigin},
 		"User-Agent": {geckoUserAgent},
 	}
 
-	conn, _, err := websocket.DefaultDialer.Dial(geckoWSURL, headers)
+	raw, _, err := websocket.DefaultDialer.DialContext(ctx, providerURLs.GeckoTerminalWS, headers)
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
 		return fmt.Errorf("dial failed: %w", err)
 	}
+	conn := newWSConn(raw)
 	defer conn.Close()
 
 	// Channel for messages
 	done := make(chan struct{})
 
-	// Read messages goroutine
+	// Read messages goroutine; GeckoTerminal pings every few seconds, so the
+	// read deadline only fires on a dead connection
+	readDeadline := config.ReadDeadlines["geckoterminal"]
 	go func() {
 		defer close(done)
 		for {
+			setReadDeadline(conn.Conn, readDeadline)
 			_, message, err := conn.ReadMessage()
 			if err != nil {
+				// net.ErrClosed is our own Close on shutdown or reconnect, not a feed error
+				if !errors.Is(err, net.ErrClosed) {
+					rec.RecordWebSocketError("geckoterminal", classifyWebSocketError(err), config.MonitorRegion)
+				}
 				return
 			}
+			receiveTime := time.Now().UTC()
+			rec.RecordMessageReceived("geckoterminal", config.MonitorRegion)
+			rec.RecordMonitorSuccess("geckoterminal", "websocket", config.MonitorRegion)
+			captureFrame("geckoterminal", receiveTime, message)
 
-			handleGeckoMessage(config, conn, message)
+			handleGeckoMessage(config, rec, conn, message, receiveTime)
 		}
 	}()
 
 	// Wait for welcome message
-	time.Sleep(2 * time.Second)
+	if !sleepOrStop(stopChan, 2*time.Second) {
+		return nil
+	}
 
 	// Subscribe to SwapChannel for all monitored pools
+	var subscribed []MonitoredPool
 	for _, pool := range geckoTerminalPools {
-		subscribeToGeckoSwapChannel(conn, pool.PoolID, pool.Name)
-		time.Sleep(100 * time.Millisecond)
+		if !chainEnabled("geckoterminal", pool.Chain) {
+			continue
+		}
+		if err := subscribeToGeckoSwapChannel(conn, pool.PoolID, pool.Name); err != nil {
+			rec.RecordSubscriptionError("geckoterminal", pool.Chain, pool.Address, config.MonitorRegion)
+			recordWebSocketWriteError(rec, "geckoterminal", err, config.MonitorRegion)
+			return fmt.Errorf("subscribe to %s failed: %w", pool.Name, err)
+		} else if registered, ok := lookupPool(pool.Address); ok {
+			subscribed = append(subscribed, registered)
+		}
+		if !sleepOrStop(stopChan, 100*time.Millisecond) {
+			return nil
+		}
 	}
 
-	fmt.Printf("[HEAD-LAG][GECKO] Subscribed to %d pools\n", len(geckoTerminalPools))
+	rec.RecordPoolsSubscribed("geckoterminal", subscribed, config.MonitorRegion)
+	fmt.Printf("[HEAD-LAG][GECKO] Subscribed to %d pools\n", len(subscribed))
 
 	// Heartbeat ticker
 	pingTicker := time.NewTicker(25 * time.Second)
@@ -173,9 +236,11 @@ func connectAndMonitorGecko(config *Config, stopChan <-chan struct{}) error {
 	}
 }
 
-func handleGeckoMessage(config *Config, conn *websocket.Conn, message []byte) {
+// handleGeckoMessage handles one GeckoTerminal frame received at receiveTime; conn is nil when replaying
+func handleGeckoMessage(config *Config, rec Recorder, conn *wsConn, message []byte, receiveTime time.Time) {
 	var msg GeckoActionCableMessage
 	if err := json.Unmarshal(message, &msg); err != nil {
+		recordParseFailure(rec, "geckoterminal", message, err, config.MonitorRegion)
 		return
 	}
 
@@ -185,35 +250,61 @@ func handleGeckoMessage(config *Config, conn *websocket.Conn, message []byte) {
 
 	case "ping":
 		// Respond to ping with pong
+		if conn == nil {
+			break
+		}
 		pong := GeckoActionCableMessage{
 			Type: "pong",
 		}
-		conn.WriteJSON(pong)
+		if err := writeWebSocket(conn, wsWritePong, pong); err != nil {
+			recordWebSocketWriteError(rec, "geckoterminal", err, config.MonitorRegion)
+		}
 
 	case "confirm_subscription":
 		// Subscription confirmed
 
 	case "reject_subscription":
+		// The connection stays up but the pool never streams, so count it rather than show a quiet pool
 		log.Printf("[HEAD-LAG][GECKO] Subscription rejected: %s", msg.Identifier)
+		var channelIdent GeckoChannelIdentifier
+		if err := json.Unmarshal([]byte(msg.Identifier), &channelIdent); err == nil {
+			rec.RecordSubscriptionError("geckoterminal", geckoPoolChain(channelIdent.PoolID), geckoPoolAddress(channelIdent.PoolID), config.MonitorRegion)
+		} else {
+			rec.RecordSubscriptionError("geckoterminal", "unknown", "unknown", config.MonitorRegion)
+		}
+
+	case "disconnect":
+		// The server is dropping the connection (e.g. "unauthorized", "server_restart"); close it
+		// now so the monitor reconnects instead of waiting for the read deadline
+		log.Printf("[HEAD-LAG][GECKO] Server disconnect: %s", msg.Reason)
+		rec.RecordWebSocketError("geckoterminal", wsErrorClosed, config.MonitorRegion)
+		if conn != nil {
+			conn.Close()
+		}
 
 	default:
 		// Handle data messages
 		if msg.Message != nil {
-			handleGeckoDataMessage(config, msg.Identifier, msg.Message)
+			handleGeckoDataMessage(config, rec, msg.Identifier, msg.Message, receiveTime)
 		}
 	}
 }
 
-func handleGeckoDataMessage(config *Config, identifier string, message json.RawMessage) {
+func handleGeckoDataMessage(config *Config, rec Recorder, identifier string, message json.RawMessage, receiveTime time.Time) {
 	// Parse swap data
 	var swapData GeckoSwapData
 	if err := json.Unmarshal(message, &swapData); err != nil {
+		recordParseFailure(rec, "geckoterminal", message, err, config.MonitorRegion)
 		return
 	}
 
 	if swapData.Type != "newSwap" {
 		return
 	}
+	if swapData.Data.BlockTimestamp == 0 {
+		rec.RecordMissingTimestamp("geckoterminal", config.MonitorRegion)
+		return
+	}
 
 	// Extract channel info to get pool
 	var channelIdent GeckoChannelIdentifier
@@ -222,40 +313,78 @@ func handleGeckoDataMessage(config *Config, identifier string, message json.RawM
 	}
 
 	// Find which pool this is
-	var poolChain string
-	for _, pool := range geckoTerminalPools {
-		if pool.PoolID == channelIdent.PoolID {
-			poolChain = pool.Chain
-			break
-		}
+	poolChain := geckoPoolChain(channelIdent.PoolID)
+	if poolChain == "" {
+		return
 	}
 
-	if poolChain == "" {
+	if belowMinTradeVolume(config, rec, "geckoterminal", poolChain, swapData.Data.Vo) {
 		return
 	}
 
 	// Calculate head lag
-	receiveTime := time.Now().UTC()
 	onChainTime := time.UnixMilli(swapData.Data.BlockTimestamp)
-	lagMs := receiveTime.Sub(onChainTime).Milliseconds()
+	lag := receiveTime.Sub(onChainTime)
+	lagMs := lag.Milliseconds()
 	lagSeconds := float64(lagMs) / 1000.0
 
 	// Record metrics
-	RecordHeadLag("geckoterminal", poolChain, lagMs, lagSeconds, config.MonitorRegion)
+	rec.RecordTrade(NormalizedTrade{
+		Provider:    "geckoterminal",
+		Chain:       poolChain,
+		Pool:        geckoPoolAddress(channelIdent.PoolID),
+		TxHash:      swapData.Data.TxHash,
+		OnChainTime: onChainTime,
+		ReceivedAt:  receiveTime,
+		LagMs:       lagMs,
+		Lag:         lag,
+		Region:      config.MonitorRegion,
+		TradeType:   normalizeTradeType(swapData.Data.Ty),
+	})
 
 	// Log occasionally (not every trade)
-	if lagMs > 10000 || time.Now().Second()%30 == 0 {
+	if lagMs > 10000 || sampleTradeLog("geckoterminal") {
 		timestamp := receiveTime.Format("15:04:05")
 		txHash := swapData.Data.TxHash
 		if len(txHash) > 12 {
-			txHash = txHash[:10] + "..."
+			txHash = safeTruncate(txHash, 10) + "..."
 		}
 		fmt.Printf("[HEAD-LAG][GECKO][%s][%s] Lag: %.2fs | Tx: %s\n",
 			timestamp, poolChain, lagSeconds, txHash)
 	}
 }
 
-func subscribeToGeckoSwapChannel(conn *websocket.Conn, poolID, poolName string) {
+// geckoPoolChain returns the chain of a monitored GeckoTerminal pool_id, empty if it is not monitored
+func geckoPoolChain(poolID string) string {
+	for _, pool := range geckoTerminalPools {
+		if pool.PoolID == poolID {
+			return pool.Chain
+		}
+	}
+	return ""
+}
+
+// geckoPoolAddress returns the pool address of a monitored GeckoTerminal pool_id
+func geckoPoolAddress(poolID string) string {
+	for _, pool := range geckoTerminalPools {
+		if pool.PoolID == poolID {
+			return pool.Address
+		}
+	}
+	return ""
+}
+
+// geckoPoolForAddress returns the GeckoTerminal pool_id subscribed for a pool address, empty if none
+func geckoPoolForAddress(address string) string {
+	for _, pool := range geckoTerminalPools {
+		if normalizePoolAddress(pool.Address) == normalizePoolAddress(address) {
+			return pool.PoolID
+		}
+	}
+	return ""
+}
+
+func subscribeToGeckoSwapChannel(conn *wsConn, poolID, poolName string) error {
 	identifier := GeckoChannelIdentifier{
 		Channel: "SwapChannel",
 		PoolID:  poolID,
@@ -268,8 +397,9 @@ func subscribeToGeckoSwapChannel(conn *websocket.Conn, poolID, poolName string)
 		Identifier: string(identifierJSON),
 	}
 
-	if err := conn.WriteJSON(subscribeMsg); err != nil {
+	if err := writeWebSocket(conn, wsWriteSubscribe, subscribeMsg); err != nil {
 		log.Printf("[HEAD-LAG][GECKO] Error subscribing to %s: %v", poolName, err)
-		return
+		return err
 	}
+	return nil
 }
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// The subscribe frame must match GeckoTerminal's ActionCable wire format: the identifier is
+// itself a JSON string
+func TestGeckoSubscribeWireFormat(t *testing.T) {
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, frame, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		received <- string(frame)
+	}))
+	defer server.Close()
+
+	raw, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	conn := newWSConn(raw)
+	defer conn.Close()
+
+	if err := subscribeToGeckoSwapChannel(conn, "24", "WBNB/BUSD PancakeSwap"); err != nil {
+		t.Fatalf("subscribeToGeckoSwapChannel: %v", err)
+	}
+	want := `{"command":"subscribe","identifier":"{\"channel\":\"SwapChannel\",\"pool_id\":\"24\"}"}`
+	if got := strings.TrimSuffix(<-received, "\n"); got != want { // WriteJSON ends frames with a newline
+		t.Errorf("subscribe frame = %s, want %s", got, want)
+	}
+}
+
+// Every GeckoTerminal pool must be a registry pool on the same chain, under GeckoTerminal's
+// network slug rather than our chain name
+func TestGeckoPoolsMatchRegistry(t *testing.T) {
+	slugs := map[string]string{
+		"ethereum": "eth",
+		"solana":   "solana",
+		"base":     "base",
+		"bnb":      "bsc",
+		"arbitrum": "arbitrum",
+	}
+	for _, pool := range geckoTerminalPools {
+		registered, ok := lookupPool(pool.Address)
+		if !ok {
+			t.Errorf("%s: address %s not in the pool registry", pool.Name, pool.Address)
+			continue
+		}
+		if registered.ChainName != pool.Chain {
+			t.Errorf("%s: chain = %q, registry has %q", pool.Name, pool.Chain, registered.ChainName)
+		}
+		if want, ok := slugs[pool.Chain]; !ok || pool.Network != want {
+			t.Errorf("%s: network = %q, want GeckoTerminal slug %q", pool.Name, pool.Network, want)
+		}
+		if got := geckoPoolForAddress(pool.Address); got != pool.PoolID {
+			t.Errorf("%s: geckoPoolForAddress = %q, want %q", pool.Name, got, pool.PoolID)
+		}
+	}
+}
+
+// Control frames carry no trade: only rejections and server disconnects are recorded
+func TestGeckoControlFrames(t *testing.T) {
+	tests := []struct {
+		name   string
+		frame  string
+		method string
+		want   []string
+	}{
+		{name: "welcome", frame: `{"type":"welcome"}`},
+		{name: "ping while replaying", frame: `{"type":"ping","message":1700000000}`},
+		{name: "confirm", frame: `{"type":"confirm_subscription","identifier":"{\"channel\":\"SwapChannel\",\"pool_id\":\"24\"}"}`},
+		{
+			name:   "reject",
+			frame:  `{"type":"reject_subscription","identifier":"{\"channel\":\"SwapChannel\",\"pool_id\":\"24\"}"}`,
+			method: "RecordSubscriptionError",
+			want:   []string{"geckoterminal bnb 0x58f876857a02d6762e0101bb5c46a8c1ed44dc16 eu"},
+		},
+		{
+			name:   "reject with unparseable identifier",
+			frame:  `{"type":"reject_subscription","identifier":"SwapChannel"}`,
+			method: "RecordSubscriptionError",
+			want:   []string{"geckoterminal unknown unknown eu"},
+		},
+		{
+			name:   "disconnect",
+			frame:  `{"type":"disconnect","reason":"server_restart","reconnect":true}`,
+			method: "RecordWebSocketError",
+			want:   []string{"geckoterminal " + wsErrorClosed + " eu"},
+		},
+	}
+	for _, tt := range tests {
+		rec := &fakeRecorder{}
+		handleGeckoMessage(&Config{MonitorRegion: "eu"}, rec, nil, []byte(tt.frame), time.Now())
+
+		recorded := 0
+		for _, calls := range rec.log {
+			recorded += len(calls)
+		}
+		if recorded != len(tt.want) {
+			t.Errorf("%s: recorded %v, want only %s %q", tt.name, rec.log, tt.method, tt.want)
+			continue
+		}
+		if got := rec.calls(tt.method); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: %s calls = %q, want %q", tt.name, tt.method, got, tt.want)
+		}
+	}
+}
+
+// A disconnect frame closes the connection itself rather than waiting for the read deadline
+func TestGeckoDisconnectClosesConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.ReadMessage() // Until the client goes away
+	}))
+	defer server.Close()
+
+	raw, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	conn := newWSConn(raw)
+	defer conn.Close()
+
+	handleGeckoMessage(&Config{MonitorRegion: "eu"}, &fakeRecorder{}, conn, []byte(`{"type":"disconnect","reason":"unauthorized"}`), time.Now())
+	if _, _, err := conn.ReadMessage(); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("read after disconnect = %v, want net.ErrClosed", err)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// GoldRush REST Monitor
+// GoldRush (formerly Covalent) address endpoints, probed for every EVM pool it covers
+// ============================================================================
+
+// goldRushRESTEndpoints are the probes GOLDRUSH_REST_ENDPOINTS can select, by endpoint label.
+// Both are keyed by the pool's address, the way a wallet or a contract is looked up.
+var goldRushRESTEndpoints = map[string]string{
+	"balances":     "balances_v2",
+	"transactions": "transactions_v3",
+}
+
+// callGoldRushAPI makes a GET request to a GoldRush address endpoint and measures its latency.
+// An HTTP error status fails the call, so it is recorded as an error rather than a latency sample.
+func callGoldRushAPI(apiKey string, endpoint string, poolAddress string, goldRushChain string, chainName string) (float64, int, error) {
+	path := fmt.Sprintf("/v1/%s/address/%s/%s/", goldRushChain, url.PathEscape(poolAddress), goldRushRESTEndpoints[endpoint])
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	req, err := http.NewRequest("GET", providerURLs.GoldRushREST+path, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if endpoint == "transactions" {
+		// Newest page only, without the decoded logs that make the response large
+		q := url.Values{}
+		q.Add("no-logs", "true")
+		req.URL.RawQuery = q.Encode()
+	}
+
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	// Measure latency
+	startTime := time.Now()
+	resp, err := client.Do(req)
+	latencyMs := float64(time.Since(startTime).Milliseconds())
+
+	if err != nil {
+		return latencyMs, 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 400 {
+		return latencyMs, resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	// Not a critical error, we still measured latency
+	if !json.Valid(body) {
+		log.Printf("[GOLDRUSH-REST][%s] Response parse warning: %s returned invalid JSON (status: %d)", chainName, endpoint, resp.StatusCode)
+	}
+
+	return latencyMs, resp.StatusCode, nil
+}
+
+// monitorGoldRushREST continuously monitors GoldRush REST API latency
+func monitorGoldRushREST(config *Config, rec Recorder, stopChan <-chan struct{}) {
+	fmt.Println("Starting GoldRush REST API monitor...")
+	fmt.Printf("   Monitoring %d pools with %v interval\n", len(monitoredPools()), currentRESTInterval())
+	fmt.Printf("   Endpoints: %s\n", strings.Join(config.GoldRushRESTEndpoints, ", "))
+	logSkippedChains("[GOLDRUSH-REST]", "goldrush", ChainInfo.SupportsGoldRush)
+	fmt.Println()
+
+	if config.GoldRushAPIKey == "" {
+		rec.RecordProviderStatus("goldrush", "rest", skipReasonMissingGoldRushKey, config.MonitorRegion)
+		fmt.Println("GOLDRUSH_API_KEY not set in .env file. Skipping GoldRush REST monitor.")
+		return
+	}
+	rec.RecordProviderStatus("goldrush", "rest", "", config.MonitorRegion)
+
+	ticker := time.NewTicker(currentRESTInterval())
+	defer ticker.Stop()
+
+	warmup := newRESTWarmup(config)
+
+	// Run once immediately
+	if warmup.due(time.Now()) {
+		warmUpGoldRushREST(config)
+	}
+	performGoldRushRESTChecks(config, rec, stopChan)
+
+	// Then run on every tick, picking up interval changes from SIGHUP reloads
+	for {
+		select {
+		case <-stopChan:
+			fmt.Println("GoldRush REST monitor stopped")
+			return
+		case <-ticker.C:
+			if warmup.due(time.Now()) {
+				warmUpGoldRushREST(config)
+			}
+			performGoldRushRESTChecks(config, rec, stopChan)
+			ticker.Reset(currentRESTInterval())
+		}
+	}
+}
+
+// performGoldRushRESTChecks probes every selected endpoint for the pools GoldRush covers
+func performGoldRushRESTChecks(config *Config, rec Recorder, stopChan <-chan struct{}) {
+	timestamp := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	for _, pool := range monitoredPools() {
+		if !pool.SupportsGoldRush() || !chainEnabled("goldrush", pool.ChainName) {
+			continue
+		}
+
+		for _, endpoint := range config.GoldRushRESTEndpoints {
+			checkGoldRushEndpoint(config, rec, stopChan, endpoint, pool, timestamp)
+		}
+	}
+}
+
+// warmUpGoldRushREST sends one discarded request per endpoint, for the first pool GoldRush covers
+func warmUpGoldRushREST(config *Config) {
+	warmUpEndpoints("[GOLDRUSH-REST]", config.GoldRushRESTEndpoints, func(endpoint string) bool {
+		for _, pool := range monitoredPools() {
+			if pool.SupportsGoldRush() && chainEnabled("goldrush", pool.ChainName) {
+				callGoldRushAPI(config.GoldRushAPIKey, endpoint, pool.Address, pool.GoldRushChain, pool.ChainName)
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// checkGoldRushEndpoint probes one GoldRush endpoint for a pool and records the outcome
+func checkGoldRushEndpoint(config *Config, rec Recorder, stopChan <-chan struct{}, endpoint string, pool MonitoredPool, timestamp string) {
+	latencyMs, statusCode, err := withRESTRetry(stopChan, config.RESTRetries, func() (float64, int, error) {
+		return callGoldRushAPI(config.GoldRushAPIKey, endpoint, pool.Address, pool.GoldRushChain, pool.ChainName)
+	})
+
+	if err != nil {
+		rec.RecordRESTError("goldrush", endpoint, pool.ChainName, getErrorType(statusCode), config.MonitorRegion)
+		fmt.Printf("[GOLDRUSH-REST][%s][%s][%s] ERROR | Latency: %.0fms | Status: %d | Error: %v\n",
+			timestamp, pool.ChainName, endpoint, latencyMs, statusCode, err)
+		return
+	}
+
+	rec.RecordRESTLatency("goldrush", endpoint, pool.ChainName, latencyMs, statusCode, config.MonitorRegion)
+	rec.RecordMonitorSuccess("goldrush", "rest", config.MonitorRegion)
+	fmt.Printf("[GOLDRUSH-REST][%s][%s][%s] %s | Latency: %.0fms | Status: %d\n",
+		timestamp, pool.ChainName, endpoint, getStatusEmoji(statusCode), latencyMs, statusCode)
+}
+
+// runGoldRushRESTMonitor is the entry point for the GoldRush REST monitor
+func runGoldRushRESTMonitor(config *Config, rec Recorder, stopChan <-chan struct{}) {
+	monitorGoldRushREST(config, rec, stopChan)
+}
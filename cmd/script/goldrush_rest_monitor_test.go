@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// An HTTP error is a failed call: it is counted in rest_api_errors_total, never as a latency sample
+func TestGoldRushEndpointRecordsHTTPErrors(t *testing.T) {
+	tests := []struct {
+		status      int
+		wantLatency []string
+		wantErrors  []string
+	}{
+		{status: http.StatusOK, wantLatency: []string{"goldrush balances ethereum 200 eu"}},
+		{status: http.StatusUnauthorized, wantErrors: []string{"goldrush balances ethereum client_error eu"}},
+		{status: http.StatusTooManyRequests, wantErrors: []string{"goldrush balances ethereum client_error eu"}},
+		{status: http.StatusServiceUnavailable, wantErrors: []string{"goldrush balances ethereum server_error eu"}},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			fmt.Fprint(w, `{"data":{}}`)
+		}))
+		withProviderURL(t, &providerURLs.GoldRushREST, server.URL)
+
+		rec := &fakeRecorder{}
+		pool := MonitoredPool{Name: "ETH/USDC", Address: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", ChainInfo: ethereumChain}
+		checkGoldRushEndpoint(&Config{GoldRushAPIKey: "key", MonitorRegion: "eu"}, rec, nil, "balances", pool, "00:00:00")
+		server.Close()
+
+		if got := rec.calls("RecordRESTLatency"); fmt.Sprint(got) != fmt.Sprint(tt.wantLatency) {
+			t.Errorf("HTTP %d: RecordRESTLatency calls = %q, want %q", tt.status, got, tt.wantLatency)
+		}
+		if got := rec.calls("RecordRESTError"); fmt.Sprint(got) != fmt.Sprint(tt.wantErrors) {
+			t.Errorf("HTTP %d: RecordRESTError calls = %q, want %q", tt.status, got, tt.wantErrors)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	latencyv1 "mobula_latency_competitor/proto/latency/v1"
+)
+
+// ============================================================================
+// gRPC Streaming API
+// Serves live measurements to internal services over latency.v1.LatencyService
+// ============================================================================
+
+const grpcSubscriberBufferSize = 256
+
+// GRPCServer is a Sink that fans every measured trade out to the connected
+// StreamMeasurements clients and keeps per provider/chain aggregates for GetStats.
+type GRPCServer struct {
+	latencyv1.UnimplementedLatencyServiceServer
+
+	server *grpc.Server
+
+	mu          sync.Mutex
+	subscribers map[*grpcSubscriber]struct{}
+	stats       map[string]*latencyv1.ProviderChainStats
+	closed      bool
+}
+
+type grpcSubscriber struct {
+	provider string
+	chain    string
+	trades   chan NormalizedTrade
+}
+
+// NewGRPCServer starts serving the LatencyService on addr
+func NewGRPCServer(addr string) (*GRPCServer, error) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("gRPC listen on %s failed: %w", addr, err)
+	}
+
+	s := &GRPCServer{
+		server:      grpc.NewServer(),
+		subscribers: make(map[*grpcSubscriber]struct{}),
+		stats:       make(map[string]*latencyv1.ProviderChainStats),
+	}
+	latencyv1.RegisterLatencyServiceServer(s.server, s)
+
+	go func() {
+		if err := s.server.Serve(lis); err != nil {
+			fmt.Printf("gRPC server error: %v\n", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// Publish updates the aggregates and hands the trade to every matching subscriber.
+// A subscriber that is not keeping up misses the trade instead of stalling the read loop.
+func (s *GRPCServer) Publish(trade NormalizedTrade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	s.updateStats(trade)
+
+	for sub := range s.subscribers {
+		if !sub.matches(trade) {
+			continue
+		}
+		select {
+		case sub.trades <- trade:
+		default:
+			RecordSinkDropped("grpc")
+		}
+	}
+}
+
+// Close ends all open streams and stops the server
+func (s *GRPCServer) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		for sub := range s.subscribers {
+			close(sub.trades)
+			delete(s.subscribers, sub)
+		}
+	}
+	s.mu.Unlock()
+
+	s.server.GracefulStop()
+	return nil
+}
+
+// StreamMeasurements sends every matching trade until the client disconnects or the server shuts down
+func (s *GRPCServer) StreamMeasurements(req *latencyv1.StreamMeasurementsRequest, stream grpc.ServerStreamingServer[latencyv1.Measurement]) error {
+	sub := &grpcSubscriber{
+		provider: req.GetProvider(),
+		chain:    req.GetChain(),
+		trades:   make(chan NormalizedTrade, grpcSubscriberBufferSize),
+	}
+
+	if !s.subscribe(sub) {
+		return nil
+	}
+	defer s.unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case trade, ok := <-sub.trades:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(toMeasurement(trade)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetStats returns the aggregates sorted by provider then chain
+func (s *GRPCServer) GetStats(ctx context.Context, req *latencyv1.GetStatsRequest) (*latencyv1.GetStatsResponse, error) {
+	s.mu.Lock()
+	stats := make([]*latencyv1.ProviderChainStats, 0, len(s.stats))
+	for _, st := range s.stats {
+		stats = append(stats, &latencyv1.ProviderChainStats{
+			Provider:       st.Provider,
+			Chain:          st.Chain,
+			Count:          st.Count,
+			LastLagMs:      st.LastLagMs,
+			AvgLagMs:       st.AvgLagMs,
+			MinLagMs:       st.MinLagMs,
+			MaxLagMs:       st.MaxLagMs,
+			LastReceivedAt: st.LastReceivedAt,
+		})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Provider != stats[j].Provider {
+			return stats[i].Provider < stats[j].Provider
+		}
+		return stats[i].Chain < stats[j].Chain
+	})
+
+	return &latencyv1.GetStatsResponse{Stats: stats}, nil
+}
+
+func (s *GRPCServer) subscribe(sub *grpcSubscriber) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.subscribers[sub] = struct{}{}
+	return true
+}
+
+func (s *GRPCServer) unsubscribe(sub *grpcSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Close may already have removed and closed it
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.trades)
+	}
+}
+
+// updateStats folds a trade into its provider/chain aggregate. A negative lag is clock
+// skew and stays out of the aggregate, as it does for the head lag metrics; subscribers
+// still receive the trade. Callers must hold s.mu.
+func (s *GRPCServer) updateStats(trade NormalizedTrade) {
+	if trade.LagMs < 0 {
+		return
+	}
+	key := trade.Provider + ":" + trade.Chain
+	st, ok := s.stats[key]
+	if !ok {
+		st = &latencyv1.ProviderChainStats{
+			Provider: trade.Provider,
+			Chain:    trade.Chain,
+			MinLagMs: trade.LagMs,
+			MaxLagMs: trade.LagMs,
+		}
+		s.stats[key] = st
+	}
+
+	st.Count++
+	st.LastLagMs = trade.LagMs
+	st.AvgLagMs += (float64(trade.LagMs) - st.AvgLagMs) / float64(st.Count)
+	st.MinLagMs = min(st.MinLagMs, trade.LagMs)
+	st.MaxLagMs = max(st.MaxLagMs, trade.LagMs)
+	st.LastReceivedAt = timestamppb.New(trade.ReceivedAt)
+}
+
+func (sub *grpcSubscriber) matches(trade NormalizedTrade) bool {
+	return (sub.provider == "" || sub.provider == trade.Provider) &&
+		(sub.chain == "" || sub.chain == trade.Chain)
+}
+
+func toMeasurement(trade NormalizedTrade) *latencyv1.Measurement {
+	return &latencyv1.Measurement{
+		Provider:    trade.Provider,
+		Chain:       trade.Chain,
+		Pool:        trade.Pool,
+		TxHash:      trade.TxHash,
+		BlockNumber: trade.BlockNumber,
+		OnChainTime: timestamppb.New(trade.OnChainTime),
+		ReceivedAt:  timestamppb.New(trade.ReceivedAt),
+		LagMs:       trade.LagMs,
+		Region:      trade.Region,
+	}
+}
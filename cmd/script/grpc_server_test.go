@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	latencyv1 "mobula_latency_competitor/proto/latency/v1"
+)
+
+func TestGRPCServerStats(t *testing.T) {
+	tests := []struct {
+		name string
+		lags []int64
+		want *latencyv1.ProviderChainStats // nil when no aggregate is kept
+	}{
+		{
+			name: "measured",
+			lags: []int64{300, 100, 200},
+			want: &latencyv1.ProviderChainStats{Count: 3, LastLagMs: 200, AvgLagMs: 200, MinLagMs: 100, MaxLagMs: 300},
+		},
+		{
+			name: "clock skew left out",
+			lags: []int64{300, -50, 100},
+			want: &latencyv1.ProviderChainStats{Count: 2, LastLagMs: 100, AvgLagMs: 200, MinLagMs: 100, MaxLagMs: 300},
+		},
+		{
+			name: "only clock skew",
+			lags: []int64{-50},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewGRPCServer("127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("NewGRPCServer: %v", err)
+			}
+			defer s.Close()
+
+			for _, lag := range tt.lags {
+				s.Publish(NormalizedTrade{Provider: "codex", Chain: "ethereum", LagMs: lag, ReceivedAt: time.Now()})
+			}
+
+			resp, err := s.GetStats(context.Background(), &latencyv1.GetStatsRequest{})
+			if err != nil {
+				t.Fatalf("GetStats: %v", err)
+			}
+			if tt.want == nil {
+				if len(resp.Stats) != 0 {
+					t.Errorf("GetStats = %v, want no aggregate", resp.Stats)
+				}
+				return
+			}
+			if len(resp.Stats) != 1 {
+				t.Fatalf("GetStats returned %d aggregates, want 1", len(resp.Stats))
+			}
+			got := resp.Stats[0]
+			if got.Count != tt.want.Count || got.LastLagMs != tt.want.LastLagMs || got.AvgLagMs != tt.want.AvgLagMs ||
+				got.MinLagMs != tt.want.MinLagMs || got.MaxLagMs != tt.want.MaxLagMs {
+				t.Errorf("stats = count %d last %d avg %v min %d max %d, want count %d last %d avg %v min %d max %d",
+					got.Count, got.LastLagMs, got.AvgLagMs, got.MinLagMs, got.MaxLagMs,
+					tt.want.Count, tt.want.LastLagMs, tt.want.AvgLagMs, tt.want.MinLagMs, tt.want.MaxLagMs)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -16,54 +18,6 @@ import (
 // Measures indexation latency: time between on-chain event and WebSocket receipt
 // ============================================================================
 
-// Pool configurations for head lag monitoring
-type HeadLagPool struct {
-	Name       string // Human readable name
-	Blockchain string // For Mobula: "evm:1", "solana", etc.
-	NetworkID  int    // For Codex: 1, 1399811149, etc.
-	Address    string // Pool address
-	ChainName  string // Normalized chain name for metrics
-}
-
-// Pools to monitor - high activity pools for accurate lag measurement
-var headLagPools = []HeadLagPool{
-	{
-		Name:       "ETH/USDC Uniswap V3",
-		Blockchain: "evm:1",
-		NetworkID:  1,
-		Address:    "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
-		ChainName:  "ethereum",
-	},
-	{
-		Name:       "SOL/USDC Raydium",
-		Blockchain: "solana",
-		NetworkID:  1399811149,
-		Address:    "7qbRF6YsyGuLUVs6Y1q64bdVrfe4ZcUUz1JRdoVNUJnm",
-		ChainName:  "solana",
-	},
-	{
-		Name:       "WETH/USDC Base",
-		Blockchain: "evm:8453",
-		NetworkID:  8453,
-		Address:    "0x4c36388be6f416a29c8d8eee81c771ce6be14b18",
-		ChainName:  "base",
-	},
-	{
-		Name:       "WBNB/BUSD PancakeSwap",
-		Blockchain: "evm:56",
-		NetworkID:  56,
-		Address:    "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16",
-		ChainName:  "bnb",
-	},
-	{
-		Name:       "WETH/USDC Arbitrum",
-		Blockchain: "evm:42161",
-		NetworkID:  42161,
-		Address:    "0xc6962004f452be9203591991d15f6b388e09e8d0",
-		ChainName:  "arbitrum",
-	},
-}
-
 // ============================================================================
 // Mobula WebSocket Monitor
 // ============================================================================
@@ -76,17 +30,25 @@ type MobulaTradeEvent struct {
 	Pair       string  `json:"pair"`
 	Type       string  `json:"type"`
 	TokenPrice float64 `json:"tokenPrice"`
+
+	TokenAmountUSD usdAmount `json:"tokenAmountUsd"` // Trade value, see MIN_TRADE_VOLUME_USD
 }
 
-func runMobulaHeadLagMonitor(config *Config, stopChan <-chan struct{}, wg *sync.WaitGroup) {
+func runMobulaHeadLagMonitor(config *Config, rec Recorder, stopChan <-chan struct{}, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	if config.MobulaAPIKey == "" {
+		rec.RecordProviderStatus("mobula", "websocket", skipReasonMissingMobulaAPIKey, config.MonitorRegion)
 		fmt.Println("[HEAD-LAG][MOBULA] API key not set, skipping")
 		return
 	}
+	rec.RecordProviderStatus("mobula", "websocket", "", config.MonitorRegion)
 
 	fmt.Println("[HEAD-LAG][MOBULA] Starting WebSocket monitor...")
+	logSkippedChains("[HEAD-LAG][MOBULA]", "mobula", ChainInfo.SupportsMobula)
+
+	ctx, cancel := stopContext(stopChan)
+	defer cancel()
 
 	reconnectDelay := 5 * time.Second
 	maxReconnectDelay := 60 * time.Second
@@ -97,18 +59,16 @@ func runMobulaHeadLagMonitor(config *Config, stopChan <-chan struct{}, wg *sync.
 			fmt.Println("[HEAD-LAG][MOBULA] Monitor stopped")
 			return
 		default:
-			err := connectAndMonitorMobula(config, stopChan)
+			err := connectAndMonitorMobula(ctx, config, rec, stopChan)
 			if err != nil {
 				log.Printf("[HEAD-LAG][MOBULA] Connection error: %v. Reconnecting in %v...", err, reconnectDelay)
 				
-				select {
-				case <-stopChan:
+				if !backoffOrStop(rec, "mobula", stopChan, reconnectDelay, config.MonitorRegion) {
 					return
-				case <-time.After(reconnectDelay):
-					reconnectDelay = reconnectDelay * 2
-					if reconnectDelay > maxReconnectDelay {
-						reconnectDelay = maxReconnectDelay
-					}
+				}
+				reconnectDelay = reconnectDelay * 2
+				if reconnectDelay > maxReconnectDelay {
+					reconnectDelay = maxReconnectDelay
 				}
 			} else {
 				// Reset delay on clean disconnect
@@ -118,49 +78,96 @@ func runMobulaHeadLagMonitor(config *Config, stopChan <-chan struct{}, wg *sync.
 	}
 }
 
-func connectAndMonitorMobula(config *Config, stopChan <-chan struct{}) error {
-	conn, _, err := websocket.DefaultDialer.Dial("wss://api.mobula.io", nil)
+// dialMobulaFastTrade connects to the Mobula WebSocket and subscribes to fast-trade for pools.
+// It returns the pools subscribed; pools on chains Mobula does not cover are left out.
+func dialMobulaFastTrade(ctx context.Context, apiKey string, pools []MonitoredPool) (*wsConn, []MonitoredPool, error) {
+	raw, _, err := websocket.DefaultDialer.DialContext(ctx, providerURLs.MobulaWS, nil)
 	if err != nil {
-		return fmt.Errorf("dial failed: %w", err)
+		return nil, nil, fmt.Errorf("dial failed: %w", err)
 	}
-	defer conn.Close()
+	conn := newWSConn(raw)
 
 	// Build subscription items
 	var items []map[string]interface{}
-	for _, pool := range headLagPools {
+	var subscribed []MonitoredPool
+	for _, pool := range pools {
+		if !pool.SupportsMobula() || !chainEnabled("mobula", pool.ChainName) {
+			continue
+		}
 		items = append(items, map[string]interface{}{
 			"blockchain": pool.Blockchain,
-			"address":    pool.Address,
+			"address":    pool.AddressFor("mobula"),
 		})
+		subscribed = append(subscribed, pool)
 	}
 
 	// Subscribe to fast-trade
 	subscribeMsg := map[string]interface{}{
 		"type":          "fast-trade",
-		"authorization": config.MobulaAPIKey,
+		"authorization": apiKey,
 		"payload": map[string]interface{}{
 			"assetMode": false,
 			"items":     items,
 		},
 	}
 
-	if err := conn.WriteJSON(subscribeMsg); err != nil {
-		return fmt.Errorf("subscribe failed: %w", err)
+	if err := writeWebSocket(conn, wsWriteSubscribe, subscribeMsg); err != nil {
+		return nil, nil, fmt.Errorf("subscribe failed: %w", err)
 	}
 
-	fmt.Printf("[HEAD-LAG][MOBULA] Subscribed to %d pools\n", len(items))
+	return conn, subscribed, nil
+}
+
+// maxTransportLag bounds a sane transport lag; anything longer points at a bogus provider timestamp
+const maxTransportLag = time.Minute
+
+// transportLagMs returns the time from a provider processing an event (providerMs, Unix ms) to our receipt.
+// ok is false when the provider sent no timestamp, or one that puts transport outside [0, maxTransportLag].
+func transportLagMs(receiveTime time.Time, onChainTime time.Time, providerMs int64) (int64, bool) {
+	if providerMs <= 0 {
+		return 0, false
+	}
+	processedAt := time.UnixMilli(providerMs)
+	if processedAt.Before(onChainTime) {
+		return 0, false
+	}
+	lag := receiveTime.Sub(processedAt)
+	if lag < 0 || lag > maxTransportLag {
+		return 0, false
+	}
+	return lag.Milliseconds(), true
+}
+
+func connectAndMonitorMobula(ctx context.Context, config *Config, rec Recorder, stopChan <-chan struct{}) error {
+	conn, subscribed, err := dialMobulaFastTrade(ctx, config.MobulaAPIKey, monitoredPools())
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		recordWebSocketWriteError(rec, "mobula", err, config.MonitorRegion)
+		return err
+	}
+	defer conn.Close()
+
+	// Unblock the read below on shutdown instead of waiting for the next message
+	defer context.AfterFunc(ctx, func() { conn.Close() })()
+
+	rec.RecordPoolsSubscribed("mobula", subscribed, config.MonitorRegion)
+	fmt.Printf("[HEAD-LAG][MOBULA] Subscribed to %d pools\n", len(subscribed))
 
-	// Start ping goroutine
+	// Start ping goroutine; the pongs keep quiet pools within the read deadline
+	readDeadline := config.ReadDeadlines["mobula"]
 	pingDone := make(chan struct{})
 	go func() {
-		ticker := time.NewTicker(25 * time.Second)
+		ticker := time.NewTicker(keepaliveInterval(readDeadline))
 		defer ticker.Stop()
 		for {
 			select {
 			case <-pingDone:
 				return
 			case <-ticker.C:
-				if err := conn.WriteJSON(map[string]string{"event": "ping"}); err != nil {
+				if err := writeWebSocket(conn, wsWritePing, map[string]string{"event": "ping"}); err != nil {
+					recordWebSocketWriteError(rec, "mobula", err, config.MonitorRegion)
 					return
 				}
 			}
@@ -174,57 +181,111 @@ func connectAndMonitorMobula(config *Config, stopChan <-chan struct{}) error {
 		case <-stopChan:
 			return nil
 		default:
-			conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+			setReadDeadline(conn.Conn, readDeadline)
 			_, message, err := conn.ReadMessage()
 			if err != nil {
-				return fmt.Errorf("read failed: %w", err)
+				if ctx.Err() != nil {
+					return nil
+				}
+				errorType := classifyWebSocketError(err)
+				rec.RecordWebSocketError("mobula", errorType, config.MonitorRegion)
+				return fmt.Errorf("read failed (%s): %w", errorType, err)
 			}
+			receiveTime := time.Now().UTC()
+			rec.RecordMessageReceived("mobula", config.MonitorRegion)
+			rec.RecordMonitorSuccess("mobula", "websocket", config.MonitorRegion)
+			captureFrame("mobula", receiveTime, message)
 
-			// Parse message
-			var trade MobulaTradeEvent
-			if err := json.Unmarshal(message, &trade); err != nil {
-				continue
-			}
+			handleMobulaMessage(config, rec, message, receiveTime)
+		}
+	}
+}
 
-			// Skip non-trade messages (pong, etc)
-			if trade.Hash == "" || trade.Date == 0 {
-				continue
-			}
+// handleMobulaMessage records the head lag of one Mobula frame received at receiveTime
+func handleMobulaMessage(config *Config, rec Recorder, message []byte, receiveTime time.Time) {
+	var trade MobulaTradeEvent
+	if err := json.Unmarshal(message, &trade); err != nil {
+		recordParseFailure(rec, "mobula", message, err, config.MonitorRegion)
+		return
+	}
 
-			// Calculate head lag
-			receiveTime := time.Now().UTC()
-			onChainTime := time.UnixMilli(trade.Date)
-			lagMs := receiveTime.Sub(onChainTime).Milliseconds()
-			lagSeconds := float64(lagMs) / 1000.0
+	// Skip non-trade messages (pong, etc)
+	if trade.Hash == "" {
+		return
+	}
+	if trade.Date == 0 {
+		rec.RecordMissingTimestamp("mobula", config.MonitorRegion)
+		return
+	}
 
-			// Get chain name from pool config
-			chainName := getChainNameFromBlockchain(trade.Blockchain)
+	// Calculate head lag
+	onChainTime := time.UnixMilli(trade.Date)
+	lag := receiveTime.Sub(onChainTime)
+	lagMs := lag.Milliseconds()
+	lagSeconds := float64(lagMs) / 1000.0
 
-			// Record metric
-			RecordHeadLag("mobula", chainName, lagMs, lagSeconds, config.MonitorRegion)
+	// Get chain name from pool config
+	chainName := getChainNameFromBlockchain(trade.Blockchain)
 
-			// Log occasionally (not every trade)
-			if lagMs > 5000 || time.Now().Second()%30 == 0 {
-				timestamp := receiveTime.Format("15:04:05")
-				fmt.Printf("[HEAD-LAG][MOBULA][%s][%s] Lag: %.2fs | Tx: %s\n",
-					timestamp, chainName, lagSeconds, trade.Hash)
-			}
-		}
+	if belowMinTradeVolume(config, rec, "mobula", chainName, trade.TokenAmountUSD) {
+		return
+	}
+
+	// Record metric
+	rec.RecordTrade(NormalizedTrade{
+		Provider:    "mobula",
+		Chain:       chainName,
+		Pool:        trade.Pair,
+		TxHash:      trade.Hash,
+		OnChainTime: onChainTime,
+		ReceivedAt:  receiveTime,
+		LagMs:       lagMs,
+		Lag:         lag,
+		Region:      config.MonitorRegion,
+		TradeType:   normalizeTradeType(trade.Type),
+	})
+
+	// Split off the network path from Mobula to us, using Mobula's processing time
+	if transportMs, ok := transportLagMs(receiveTime, onChainTime, trade.Timestamp); ok {
+		rec.RecordTransportLag("mobula", chainName, float64(transportMs), config.MonitorRegion)
+	}
+
+	// Log occasionally (not every trade)
+	if lagMs > 5000 || sampleTradeLog("mobula") {
+		timestamp := receiveTime.Format("15:04:05")
+		fmt.Printf("[HEAD-LAG][MOBULA][%s][%s] Lag: %.2fs | Tx: %s\n",
+			timestamp, chainName, lagSeconds, trade.Hash)
 	}
 }
 
 func getChainNameFromBlockchain(blockchain string) string {
+	for _, chain := range chainRegistry {
+		if chain.Blockchain == blockchain {
+			return chain.ChainName
+		}
+	}
+
 	switch blockchain {
-	case "Ethereum", "evm:1":
+	case "Ethereum":
 		return "ethereum"
-	case "Solana", "solana":
+	case "Solana":
 		return "solana"
-	case "Base", "evm:8453":
+	case "Base":
 		return "base"
-	case "BNB Smart Chain (BEP20)", "BSC", "evm:56":
+	case "BNB Smart Chain (BEP20)", "BSC":
 		return "bnb"
-	case "Arbitrum", "evm:42161":
+	case "Arbitrum":
 		return "arbitrum"
+	case "Polygon":
+		return "polygon"
+	case "Avalanche C-Chain", "Avalanche":
+		return "avalanche"
+	case "Optimistic", "Optimism":
+		return "optimism"
+	case "Monad":
+		return "monad"
+	case "Sui":
+		return "sui"
 	default:
 		return blockchain
 	}
@@ -235,30 +296,174 @@ func getChainNameFromBlockchain(blockchain string) string {
 // ============================================================================
 
 type CodexWSMessage struct {
-	Type    string                 `json:"type"`
-	ID      string                 `json:"id,omitempty"`
-	Payload map[string]interface{} `json:"payload,omitempty"`
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"` // Event data for "next", a list of GraphQL errors for "error"
+}
+
+type CodexEventsCreated struct {
+	Address   string `json:"address"`
+	NetworkID int    `json:"networkId"`
+	Events    []struct {
+		BlockNumber      int64  `json:"blockNumber"`
+		Timestamp        int64  `json:"timestamp"`
+		TransactionHash  string `json:"transactionHash"`
+		EventType        string `json:"eventType"`
+		EventDisplayType string `json:"eventDisplayType"` // Buy or Sell for swaps
+		Data             struct {
+			PriceUsdTotal usdAmount `json:"priceUsdTotal"` // Swap value, see MIN_TRADE_VOLUME_USD
+		} `json:"data"`
+	} `json:"events"`
 }
 
 type CodexEventData struct {
 	Data struct {
-		OnEventsCreated struct {
-			Address   string `json:"address"`
-			NetworkID int    `json:"networkId"`
-			Events    []struct {
-				BlockNumber     int64  `json:"blockNumber"`
-				Timestamp       int64  `json:"timestamp"`
-				TransactionHash string `json:"transactionHash"`
-				EventType       string `json:"eventType"`
-			} `json:"events"`
-		} `json:"onEventsCreated"`
+		OnEventsCreated            CodexEventsCreated `json:"onEventsCreated"`
+		OnUnconfirmedEventsCreated CodexEventsCreated `json:"onUnconfirmedEventsCreated"`
+	} `json:"data"`
+}
+
+type CodexPairMetadataData struct {
+	Data struct {
+		OnPairMetadataUpdated *struct {
+			ID string `json:"id"`
+		} `json:"onPairMetadataUpdated"`
 	} `json:"data"`
 }
 
-func runCodexHeadLagMonitor(config *Config, stopChan <-chan struct{}, wg *sync.WaitGroup) {
+// Codex head lag subscription modes, see CODEX_CONFIRMATION
+const (
+	codexConfirmed   = "confirmed"
+	codexUnconfirmed = "unconfirmed"
+)
+
+// codexEventTypes are the Codex event types CODEX_EVENT_TYPES may name, which also bounds the event_type label
+var codexEventTypes = []string{"Swap", "Mint", "Burn", "Sync", "PoolBalanceChanged", "Collect", "CollectProtocol"}
+
+// codexSwapEvent is the event type recorded as a trade; the others only reach event_lag_milliseconds
+const codexSwapEvent = "Swap"
+
+// parseCodexEventTypes parses CODEX_EVENT_TYPES, a comma-separated list of Codex event types
+func parseCodexEventTypes(raw string) ([]string, error) {
+	var eventTypes []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		i := slices.IndexFunc(codexEventTypes, func(eventType string) bool {
+			return strings.EqualFold(eventType, name)
+		})
+		if i < 0 {
+			return nil, fmt.Errorf("invalid CODEX_EVENT_TYPES: unknown event type %q (expected %s)", name, strings.Join(codexEventTypes, ", "))
+		}
+		if !slices.Contains(eventTypes, codexEventTypes[i]) {
+			eventTypes = append(eventTypes, codexEventTypes[i])
+		}
+	}
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("invalid CODEX_EVENT_TYPES: %q", raw)
+	}
+	return eventTypes, nil
+}
+
+// codexUnconfirmedNetworks are the networks Codex streams unconfirmed events for
+var codexUnconfirmedNetworks = map[int]bool{
+	solanaChain.NetworkID: true,
+}
+
+// codexConfirmationFor returns the mode a pool is subscribed with: unconfirmed when
+// configured and Codex supports it on the pool's network, confirmed otherwise
+func codexConfirmationFor(pool MonitoredPool, mode string) string {
+	if mode == codexUnconfirmed && codexUnconfirmedNetworks[pool.NetworkID] {
+		return codexUnconfirmed
+	}
+	return codexConfirmed
+}
+
+// codexPoolSubscription builds the GraphQL subscription for a pool's events in the given mode
+func codexPoolSubscription(pool MonitoredPool, confirmation string) map[string]interface{} {
+	if confirmation == codexUnconfirmed {
+		return map[string]interface{}{
+			"query": `subscription OnUnconfirmedPoolEvents($id: String!) {
+				onUnconfirmedEventsCreated(id: $id) {
+					address
+					networkId
+					events {
+						blockNumber
+						timestamp
+						transactionHash
+						eventType
+						eventDisplayType
+						data {
+							... on UnconfirmedSwapEventData {
+								priceUsdTotal
+							}
+						}
+					}
+				}
+			}`,
+			"variables": map[string]interface{}{
+				"id": fmt.Sprintf("%s:%d", pool.AddressFor("codex"), pool.NetworkID),
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"query": `subscription OnPoolEvents($address: String!, $networkId: Int!) {
+			onEventsCreated(address: $address, networkId: $networkId) {
+				address
+				networkId
+				events {
+					blockNumber
+					timestamp
+					transactionHash
+					eventType
+					eventDisplayType
+					data {
+						... on SwapEventData {
+							priceUsdTotal
+						}
+					}
+				}
+			}
+		}`,
+		"variables": map[string]interface{}{
+			"address":   pool.AddressFor("codex"),
+			"networkId": pool.NetworkID,
+		},
+	}
+}
+
+// codexPairMetadataSubscription builds the GraphQL subscription for a pool's metadata updates
+func codexPairMetadataSubscription(pool MonitoredPool) map[string]interface{} {
+	return map[string]interface{}{
+		"query": `subscription OnPairMetadataUpdated($id: String) {
+			onPairMetadataUpdated(id: $id) {
+				id
+			}
+		}`,
+		"variables": map[string]interface{}{
+			"id": fmt.Sprintf("%s:%d", pool.AddressFor("codex"), pool.NetworkID),
+		},
+	}
+}
+
+func runCodexHeadLagMonitor(config *Config, rec Recorder, stopChan <-chan struct{}, wg *sync.WaitGroup) {
 	defer wg.Done()
 
-	fmt.Println("[HEAD-LAG][CODEX] Starting WebSocket monitor (via Defined.fi auth)...")
+	if config.DefinedSessionCookie == "" {
+		rec.RecordProviderStatus("codex", "websocket", skipReasonMissingDefinedCookie, config.MonitorRegion)
+		fmt.Println("[HEAD-LAG][CODEX] DEFINED_SESSION_COOKIE not set, skipping")
+		return
+	}
+	rec.RecordProviderStatus("codex", "websocket", "", config.MonitorRegion)
+
+	fmt.Printf("[HEAD-LAG][CODEX] Starting WebSocket monitor (via Defined.fi auth, %s events)...\n", config.CodexConfirmation)
+	logSkippedChains("[HEAD-LAG][CODEX]", "codex", ChainInfo.SupportsCodex)
+
+	ctx, cancel := stopContext(stopChan)
+	defer cancel()
 
 	reconnectDelay := 30 * time.Second
 	maxReconnectDelay := 5 * time.Minute
@@ -269,7 +474,7 @@ func runCodexHeadLagMonitor(config *Config, stopChan <-chan struct{}, wg *sync.W
 			fmt.Println("[HEAD-LAG][CODEX] Monitor stopped")
 			return
 		default:
-			err := connectAndMonitorCodex(config, stopChan)
+			err := connectAndMonitorCodex(ctx, config, rec, stopChan)
 			if err != nil {
 				log.Printf("[HEAD-LAG][CODEX] Connection error: %v", err)
 
@@ -280,18 +485,17 @@ func runCodexHeadLagMonitor(config *Config, stopChan <-chan struct{}, wg *sync.W
 					reconnectDelay = 2 * time.Minute
 				} else if strings.Contains(err.Error(), "authentication") || strings.Contains(err.Error(), "401") {
 					log.Printf("[HEAD-LAG][CODEX] Authentication error - invalidating token cache")
+					RecordAuthFailure("codex", "websocket")
 					InvalidateTokenCache()
 				}
 
 				log.Printf("[HEAD-LAG][CODEX] Reconnecting in %v...", reconnectDelay)
-				select {
-				case <-stopChan:
+				if !backoffOrStop(rec, "codex", stopChan, reconnectDelay, config.MonitorRegion) {
 					return
-				case <-time.After(reconnectDelay):
-					reconnectDelay = reconnectDelay * 2
-					if reconnectDelay > maxReconnectDelay {
-						reconnectDelay = maxReconnectDelay
-					}
+				}
+				reconnectDelay = reconnectDelay * 2
+				if reconnectDelay > maxReconnectDelay {
+					reconnectDelay = maxReconnectDelay
 				}
 			} else {
 				reconnectDelay = 5 * time.Second
@@ -300,22 +504,28 @@ func runCodexHeadLagMonitor(config *Config, stopChan <-chan struct{}, wg *sync.W
 	}
 }
 
-func connectAndMonitorCodex(config *Config, stopChan <-chan struct{}) error {
+// dialCodexWS gets a JWT from the Defined.fi session cookie, connects to the Codex GraphQL
+// WebSocket and waits for the server to acknowledge the connection. Cancelling ctx aborts every step.
+func dialCodexWS(ctx context.Context, sessionCookie string) (*wsConn, error) {
 	// Get JWT token from Defined.fi session cookie (required - cookie alone doesn't work)
-	jwtToken, err := GetDefinedJWTToken(config.DefinedSessionCookie)
+	jwtToken, err := GetDefinedJWTToken(ctx, sessionCookie)
 	if err != nil {
-		return fmt.Errorf("failed to get JWT token: %w", err)
+		return nil, fmt.Errorf("failed to get JWT token: %w", err)
 	}
 
 	dialer := websocket.Dialer{
 		Subprotocols: []string{"graphql-transport-ws"},
 	}
 
-	conn, _, err := dialer.Dial("wss://graph.codex.io/graphql", nil)
+	raw, _, err := dialer.DialContext(ctx, providerURLs.CodexWS, nil)
 	if err != nil {
-		return fmt.Errorf("dial failed: %w", err)
+		return nil, fmt.Errorf("dial failed: %w", err)
 	}
-	defer conn.Close()
+	conn := newWSConn(raw)
+
+	// Abort the handshake on cancellation instead of waiting out the ack deadline
+	stopClose := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stopClose()
 
 	// Connection init with Bearer token
 	initMsg := map[string]interface{}{
@@ -324,57 +534,111 @@ func connectAndMonitorCodex(config *Config, stopChan <-chan struct{}) error {
 			"Authorization": fmt.Sprintf("Bearer %s", jwtToken),
 		},
 	}
-	if err := conn.WriteJSON(initMsg); err != nil {
-		return fmt.Errorf("init failed: %w", err)
+	if err := writeWebSocket(conn, wsWriteInit, initMsg); err != nil {
+		return nil, fmt.Errorf("init failed: %w", err)
 	}
 
 	// Wait for ack
 	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
 	_, msg, err := conn.ReadMessage()
 	if err != nil {
-		return fmt.Errorf("ack read failed: %w", err)
+		conn.Close()
+		return nil, fmt.Errorf("ack read failed: %w", err)
 	}
 
 	var ackMsg CodexWSMessage
 	if err := json.Unmarshal(msg, &ackMsg); err != nil || ackMsg.Type != "connection_ack" {
-		return fmt.Errorf("unexpected ack: %s", string(msg))
+		conn.Close()
+		return nil, fmt.Errorf("unexpected ack: %s", string(msg))
 	}
 
-	// Subscribe to each pool
-	for i, pool := range headLagPools {
-		subID := fmt.Sprintf("headlag_%d", i)
+	if !stopClose() {
+		return nil, ctx.Err()
+	}
+	return conn, nil
+}
 
+func connectAndMonitorCodex(ctx context.Context, config *Config, rec Recorder, stopChan <-chan struct{}) error {
+	conn, err := dialCodexWS(ctx, config.DefinedSessionCookie)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		recordWebSocketWriteError(rec, "codex", err, config.MonitorRegion)
+		return err
+	}
+	defer conn.Close()
+
+	// Unblock the read below on shutdown instead of waiting for the next message
+	defer context.AfterFunc(ctx, func() { conn.Close() })()
+
+	// Subscribe to each pool, remembering which subscription is which pool to attribute errors
+	var subscribed []MonitoredPool
+	unconfirmed := 0
+	feed := newCodexFeed()
+	for i, pool := range monitoredPools() {
+		if !pool.SupportsCodex() || !chainEnabled("codex", pool.ChainName) {
+			continue
+		}
+		subID, metadataID := codexSubscriptionIDs(i)
+
+		confirmation := codexConfirmationFor(pool, config.CodexConfirmation)
 		subMsg := map[string]interface{}{
-			"type": "subscribe",
-			"id":   subID,
-			"payload": map[string]interface{}{
-				"query": `subscription OnPoolEvents($address: String!, $networkId: Int!) {
-					onEventsCreated(address: $address, networkId: $networkId) {
-						address
-						networkId
-						events {
-							blockNumber
-							timestamp
-							transactionHash
-							eventType
-						}
-					}
-				}`,
-				"variables": map[string]interface{}{
-					"address":   pool.Address,
-					"networkId": pool.NetworkID,
-				},
-			},
+			"type":    "subscribe",
+			"id":      subID,
+			"payload": codexPoolSubscription(pool, confirmation),
 		}
 
-		if err := conn.WriteJSON(subMsg); err != nil {
+		if err := writeWebSocket(conn, wsWriteSubscribe, subMsg); err != nil {
+			rec.RecordSubscriptionError("codex", pool.ChainName, pool.Address, config.MonitorRegion)
+			recordWebSocketWriteError(rec, "codex", err, config.MonitorRegion)
 			return fmt.Errorf("subscribe to %s failed: %w", pool.Name, err)
 		}
 
-		time.Sleep(100 * time.Millisecond) // Small delay between subscriptions
+		feed.subscriptions[subID] = pool
+		subscribed = append(subscribed, pool)
+		if confirmation == codexUnconfirmed {
+			unconfirmed++
+		}
+
+		if config.CodexMetadataUpdates {
+			if err := writeWebSocket(conn, wsWriteSubscribe, map[string]interface{}{
+				"type":    "subscribe",
+				"id":      metadataID,
+				"payload": codexPairMetadataSubscription(pool),
+			}); err != nil {
+				recordWebSocketWriteError(rec, "codex", err, config.MonitorRegion)
+				return fmt.Errorf("metadata subscribe to %s failed: %w", pool.Name, err)
+			}
+			feed.metadataSubscriptions[metadataID] = pool
+		}
+		if !sleepOrStop(stopChan, 100*time.Millisecond) { // Small delay between subscriptions
+			return nil
+		}
 	}
 
-	fmt.Printf("[HEAD-LAG][CODEX] Subscribed to %d pools\n", len(headLagPools))
+	rec.RecordPoolsSubscribed("codex", subscribed, config.MonitorRegion)
+	fmt.Printf("[HEAD-LAG][CODEX] Subscribed to %d pools (%d unconfirmed, %d with metadata updates)\n", len(subscribed), unconfirmed, len(feed.metadataSubscriptions))
+
+	// graphql-transport-ws pings are answered with a pong, which keeps quiet pools within the read deadline
+	readDeadline := config.ReadDeadlines["codex"]
+	pingDone := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(keepaliveInterval(readDeadline))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pingDone:
+				return
+			case <-ticker.C:
+				if err := writeWebSocket(conn, wsWritePing, map[string]string{"type": "ping"}); err != nil {
+					recordWebSocketWriteError(rec, "codex", err, config.MonitorRegion)
+					return
+				}
+			}
+		}
+	}()
+	defer close(pingDone)
 
 	// Read messages
 	for {
@@ -382,111 +646,215 @@ func connectAndMonitorCodex(config *Config, stopChan <-chan struct{}) error {
 		case <-stopChan:
 			return nil
 		default:
-			conn.SetReadDeadline(time.Now().Add(60 * time.Second))
+			setReadDeadline(conn.Conn, readDeadline)
 			_, message, err := conn.ReadMessage()
 			if err != nil {
-				return fmt.Errorf("read failed: %w", err)
+				if ctx.Err() != nil {
+					return nil
+				}
+				errorType := classifyWebSocketError(err)
+				rec.RecordWebSocketError("codex", errorType, config.MonitorRegion)
+				return fmt.Errorf("read failed (%s): %w", errorType, err)
 			}
+			receiveTime := time.Now().UTC()
+			rec.RecordMessageReceived("codex", config.MonitorRegion)
+			rec.RecordMonitorSuccess("codex", "websocket", config.MonitorRegion)
+			captureFrame("codex", receiveTime, message)
 
-			// Parse message
-			var wsMsg CodexWSMessage
-			if err := json.Unmarshal(message, &wsMsg); err != nil {
-				continue
-			}
+			feed.handleMessage(config, rec, message, receiveTime)
+		}
+	}
+}
 
-			// Skip non-data messages
-			if wsMsg.Type != "next" || wsMsg.Payload == nil {
-				continue
-			}
+// codexFeed is the per-connection state needed to attribute Codex frames
+type codexFeed struct {
+	subscriptions         map[string]MonitoredPool // Subscription ID -> pool, to attribute errors
+	metadataSubscriptions map[string]MonitoredPool
 
-			// Parse event data
-			payloadBytes, _ := json.Marshal(wsMsg.Payload)
-			var eventData CodexEventData
-			if err := json.Unmarshal(payloadBytes, &eventData); err != nil {
-				continue
-			}
+	// Codex metadata updates carry no event time, so their lag is measured from the on-chain
+	// time of the latest swap streamed for the pool, once per swap
+	lastSwap map[string]time.Time
+}
 
-			events := eventData.Data.OnEventsCreated.Events
-			if len(events) == 0 {
-				continue
-			}
+func newCodexFeed() *codexFeed {
+	return &codexFeed{
+		subscriptions:         make(map[string]MonitoredPool),
+		metadataSubscriptions: make(map[string]MonitoredPool),
+		lastSwap:              make(map[string]time.Time),
+	}
+}
 
-			networkID := eventData.Data.OnEventsCreated.NetworkID
+// codexSubscriptionIDs returns the trade and metadata subscription IDs of the i-th monitored pool
+func codexSubscriptionIDs(i int) (string, string) {
+	return fmt.Sprintf("headlag_%d", i), fmt.Sprintf("metadata_%d", i)
+}
 
-			for _, event := range events {
-				if event.EventType != "Swap" || event.TransactionHash == "" {
-					continue
-				}
+// handleMessage records the head lag of one Codex frame received at receiveTime
+func (f *codexFeed) handleMessage(config *Config, rec Recorder, message []byte, receiveTime time.Time) {
+	var wsMsg CodexWSMessage
+	if err := json.Unmarshal(message, &wsMsg); err != nil {
+		recordParseFailure(rec, "codex", message, err, config.MonitorRegion)
+		return
+	}
 
-				// Calculate head lag
-				receiveTime := time.Now().UTC()
-				onChainTime := time.Unix(event.Timestamp, 0)
-				lagMs := receiveTime.Sub(onChainTime).Milliseconds()
-				lagSeconds := float64(lagMs) / 1000.0
+	// A rejected subscription only affects its own pool, the others keep streaming
+	if wsMsg.Type == "error" {
+		if pool, ok := f.metadataSubscriptions[wsMsg.ID]; ok {
+			// Metadata updates are not streamed on every network, the pool's trades still are
+			delete(f.metadataSubscriptions, wsMsg.ID)
+			log.Printf("[HEAD-LAG][CODEX] Metadata updates unavailable for %s (%s), skipping: %s", pool.Name, pool.Address, string(wsMsg.Payload))
+			return
+		}
+		if pool, ok := f.subscriptions[wsMsg.ID]; ok {
+			rec.RecordSubscriptionError("codex", pool.ChainName, pool.Address, config.MonitorRegion)
+			log.Printf("[HEAD-LAG][CODEX] Subscription to %s (%s) failed: %s", pool.Name, pool.Address, string(wsMsg.Payload))
+		}
+		return
+	}
 
-				// Get chain name
-				chainName := getChainNameFromNetworkID(networkID)
+	// Skip non-data messages
+	if wsMsg.Type != "next" || len(wsMsg.Payload) == 0 {
+		return
+	}
 
-				// Record metrics
-				RecordHeadLag("codex", chainName, lagMs, lagSeconds, config.MonitorRegion)
-				RecordCodexBlockNumber(chainName, event.BlockNumber, config.MonitorRegion)
+	if pool, ok := f.metadataSubscriptions[wsMsg.ID]; ok {
+		var metadataData CodexPairMetadataData
+		if err := json.Unmarshal(wsMsg.Payload, &metadataData); err != nil {
+			recordParseFailure(rec, "codex", message, err, config.MonitorRegion)
+			return
+		}
+		if metadataData.Data.OnPairMetadataUpdated == nil {
+			return
+		}
+		swapTime, ok := f.lastSwap[normalizePoolAddress(pool.Address)]
+		if !ok {
+			return
+		}
+		delete(f.lastSwap, normalizePoolAddress(pool.Address))
+
+		lag := receiveTime.Sub(swapTime)
+		rec.RecordTrade(NormalizedTrade{
+			Provider:    "codex-metadata",
+			Chain:       pool.ChainName,
+			Pool:        pool.Address,
+			OnChainTime: swapTime,
+			ReceivedAt:  receiveTime,
+			LagMs:       lag.Milliseconds(),
+			Lag:         lag,
+			Region:      config.MonitorRegion,
+		})
+		return
+	}
 
-				// Log occasionally
-				if lagMs > 5000 || time.Now().Second()%30 == 0 {
-					timestamp := receiveTime.Format("15:04:05")
-					fmt.Printf("[HEAD-LAG][CODEX][%s][%s] Lag: %.2fs | Block: %d | Tx: %s\n",
-						timestamp, chainName, lagSeconds, event.BlockNumber, event.TransactionHash)
-				}
-			}
+	// Parse event data
+	var eventData CodexEventData
+	if err := json.Unmarshal(wsMsg.Payload, &eventData); err != nil {
+		recordParseFailure(rec, "codex", message, err, config.MonitorRegion)
+		return
+	}
+
+	created, confirmation := eventData.Data.OnEventsCreated, codexConfirmed
+	if len(eventData.Data.OnUnconfirmedEventsCreated.Events) > 0 {
+		created, confirmation = eventData.Data.OnUnconfirmedEventsCreated, codexUnconfirmed
+	}
+
+	networkID := created.NetworkID
+	poolAddress := canonicalPoolAddress("codex", created.Address)
+
+	for _, event := range created.Events {
+		if !slices.Contains(config.CodexEventTypes, event.EventType) || event.TransactionHash == "" {
+			continue
+		}
+		if event.Timestamp == 0 {
+			rec.RecordMissingTimestamp("codex", config.MonitorRegion)
+			continue
+		}
+
+		// Calculate head lag
+		onChainTime := time.Unix(event.Timestamp, 0)
+		lag := receiveTime.Sub(onChainTime)
+		lagMs := lag.Milliseconds()
+		lagSeconds := float64(lagMs) / 1000.0
+
+		// Get chain name
+		chainName := getChainNameFromNetworkID(networkID)
+
+		if lagMs >= 0 {
+			rec.RecordEventLag("codex", chainName, event.EventType, float64(lagMs), config.MonitorRegion)
+		}
+		// Mints, burns and other pool events are measured by type only; trades are swaps
+		if event.EventType != codexSwapEvent {
+			continue
+		}
+
+		if belowMinTradeVolume(config, rec, "codex", chainName, event.Data.PriceUsdTotal) {
+			continue
+		}
+
+		// Record metrics
+		rec.RecordTrade(NormalizedTrade{
+			Provider:     "codex",
+			Chain:        chainName,
+			Pool:         poolAddress,
+			TxHash:       event.TransactionHash,
+			BlockNumber:  event.BlockNumber,
+			OnChainTime:  onChainTime,
+			ReceivedAt:   receiveTime,
+			LagMs:        lagMs,
+			Lag:          lag,
+			Region:       config.MonitorRegion,
+			Confirmation: confirmation,
+			TradeType:    normalizeTradeType(event.EventDisplayType),
+		})
+		rec.RecordCodexBlockNumber(chainName, event.BlockNumber, config.MonitorRegion)
+		if config.CodexMetadataUpdates {
+			f.lastSwap[normalizePoolAddress(poolAddress)] = onChainTime
+		}
+
+		// Log occasionally
+		if lagMs > 5000 || sampleTradeLog("codex") {
+			timestamp := receiveTime.Format("15:04:05")
+			fmt.Printf("[HEAD-LAG][CODEX][%s][%s] Lag: %.2fs | Block: %d | Tx: %s | %s\n",
+				timestamp, chainName, lagSeconds, event.BlockNumber, event.TransactionHash, confirmation)
 		}
 	}
 }
 
 func getChainNameFromNetworkID(networkID int) string {
-	switch networkID {
-	case 1:
-		return "ethereum"
-	case 1399811149:
-		return "solana"
-	case 8453:
-		return "base"
-	case 56:
-		return "bnb"
-	case 42161:
-		return "arbitrum"
-	default:
-		return fmt.Sprintf("network_%d", networkID)
+	if chain, ok := lookupNetworkID(networkID); ok {
+		return chain.ChainName
 	}
+	return fmt.Sprintf("network_%d", networkID)
 }
 
 // ============================================================================
 // Main Head Lag Monitor
 // ============================================================================
 
-func runHeadLagMonitor(config *Config, stopChan <-chan struct{}) {
+func runHeadLagMonitor(config *Config, rec Recorder, stopChan <-chan struct{}) {
 	fmt.Println()
-	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
-	fmt.Println("║              HEAD LAG MONITOR (WebSocket-based)              ║")
-	fmt.Println("╠══════════════════════════════════════════════════════════════╣")
-	fmt.Println("║  Measures: Time between on-chain event and WebSocket receipt ║")
-	fmt.Println("║  Providers: Mobula + Codex + GeckoTerminal                   ║")
-	fmt.Printf("║  Pools: %d high-activity pools across 5 chains               ║\n", len(headLagPools))
-	fmt.Println("╚══════════════════════════════════════════════════════════════╝")
+	fmt.Println(decorated("╔══════════════════════════════════════════════════════════════╗"))
+	fmt.Println(decorated("║              HEAD LAG MONITOR (WebSocket-based)              ║"))
+	fmt.Println(decorated("╠══════════════════════════════════════════════════════════════╣"))
+	fmt.Println(decorated("║  Measures: Time between on-chain event and WebSocket receipt ║"))
+	fmt.Println(decorated("║  Providers: Mobula + Codex + GeckoTerminal                   ║"))
+	fmt.Printf(decorated("║  Pools: %d high-activity pools across %d chains               ║\n"), len(monitoredPools()), len(chainRegistry))
+	fmt.Println(decorated("╚══════════════════════════════════════════════════════════════╝"))
 	fmt.Println()
 
 	var wg sync.WaitGroup
 
 	// Start Mobula monitor
 	wg.Add(1)
-	go runMobulaHeadLagMonitor(config, stopChan, &wg)
+	go runMobulaHeadLagMonitor(config, rec, stopChan, &wg)
 
 	// Start Codex monitor
 	wg.Add(1)
-	go runCodexHeadLagMonitor(config, stopChan, &wg)
+	go runCodexHeadLagMonitor(config, rec, stopChan, &wg)
 
 	// Start GeckoTerminal monitor
 	wg.Add(1)
-	go runGeckoTerminalHeadLagMonitor(config, stopChan, &wg)
+	go runGeckoTerminalHeadLagMonitor(config, rec, stopChan, &wg)
 
 	// Wait for all to finish
 	wg.Wait()
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// Each feed encodes the trade direction its own way; the recorded trade_type must agree across them
+func TestTradeDirectionFromFrames(t *testing.T) {
+	config := &Config{MonitorRegion: "eu", CodexEventTypes: []string{codexSwapEvent}}
+	receiveTime := time.UnixMilli(1700000001500)
+
+	tests := []struct {
+		name  string
+		feed  func(rec Recorder, frame []byte)
+		frame string
+		want  string
+	}{
+		{
+			name:  "Mobula buy",
+			feed:  func(rec Recorder, frame []byte) { handleMobulaMessage(config, rec, frame, receiveTime) },
+			frame: `{"blockchain":"evm:1","date":1700000000000,"hash":"0xabc","pair":"0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640","type":"buy"}`,
+			want:  "buy",
+		},
+		{
+			name:  "Mobula sell",
+			feed:  func(rec Recorder, frame []byte) { handleMobulaMessage(config, rec, frame, receiveTime) },
+			frame: `{"blockchain":"evm:1","date":1700000000000,"hash":"0xabc","pair":"0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640","type":"sell"}`,
+			want:  "sell",
+		},
+		{
+			name:  "Codex Sell",
+			feed:  func(rec Recorder, frame []byte) { newCodexFeed().handleMessage(config, rec, frame, receiveTime) },
+			frame: `{"type":"next","id":"headlag_0","payload":{"data":{"onEventsCreated":{"address":"0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640","networkId":1,"events":[{"blockNumber":1,"timestamp":1700000000,"transactionHash":"0xabc","eventType":"Swap","eventDisplayType":"Sell"}]}}}}`,
+			want:  "sell",
+		},
+		{
+			name:  "Codex Buy",
+			feed:  func(rec Recorder, frame []byte) { newCodexFeed().handleMessage(config, rec, frame, receiveTime) },
+			frame: `{"type":"next","id":"headlag_0","payload":{"data":{"onEventsCreated":{"address":"0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640","networkId":1,"events":[{"blockNumber":1,"timestamp":1700000000,"transactionHash":"0xabc","eventType":"Swap","eventDisplayType":"Buy"}]}}}}`,
+			want:  "buy",
+		},
+		{
+			name:  "GeckoTerminal b",
+			feed:  func(rec Recorder, frame []byte) { handleGeckoMessage(config, rec, nil, frame, receiveTime) },
+			frame: `{"identifier":"{\"channel\":\"SwapChannel\",\"pool_id\":\"147971598\"}","message":{"type":"newSwap","data":{"block_timestamp":1700000000000,"tx_hash":"0xabc","ty":"b"}}}`,
+			want:  "buy",
+		},
+		{
+			name:  "GeckoTerminal s",
+			feed:  func(rec Recorder, frame []byte) { handleGeckoMessage(config, rec, nil, frame, receiveTime) },
+			frame: `{"identifier":"{\"channel\":\"SwapChannel\",\"pool_id\":\"147971598\"}","message":{"type":"newSwap","data":{"block_timestamp":1700000000000,"tx_hash":"0xabc","ty":"s"}}}`,
+			want:  "sell",
+		},
+	}
+	for _, tt := range tests {
+		rec := &fakeRecorder{}
+		tt.feed(rec, []byte(tt.frame))
+
+		trades := rec.recordedTrades()
+		if len(trades) != 1 {
+			t.Errorf("%s: recorded %d trades, want 1", tt.name, len(trades))
+			continue
+		}
+		if trades[0].TradeType != tt.want || trades[0].Chain != "ethereum" {
+			t.Errorf("%s: trade on %q with trade_type %q, want ethereum and %q", tt.name, trades[0].Chain, trades[0].TradeType, tt.want)
+		}
+	}
+}
+
+func TestTransportLagMs(t *testing.T) {
+	onChain := time.UnixMilli(1700000000000)
+	receiveTime := onChain.Add(2 * time.Second)
+
+	tests := []struct {
+		name       string
+		providerMs int64
+		want       int64
+		wantOK     bool
+	}{
+		{"processed after the chain", onChain.Add(1500 * time.Millisecond).UnixMilli(), 500, true},
+		{"processed on receipt", receiveTime.UnixMilli(), 0, true},
+		{"no timestamp", 0, 0, false},
+		{"negative timestamp", -1, 0, false},
+		{"processed before the chain", onChain.Add(-time.Millisecond).UnixMilli(), 0, false},
+		{"processed after receipt", receiveTime.Add(time.Millisecond).UnixMilli(), 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := transportLagMs(receiveTime, onChain, tt.providerMs)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: transportLagMs = %d, %v, want %d, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	// Transport longer than maxTransportLag points at a bogus provider timestamp
+	if _, ok := transportLagMs(onChain.Add(maxTransportLag+time.Second), onChain, onChain.UnixMilli()); ok {
+		t.Errorf("transportLagMs above maxTransportLag: ok = true, want false")
+	}
+}
+
+func TestParseCodexEventTypes(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{raw: "Swap", want: []string{"Swap"}},
+		{raw: "swap, mint ,BURN", want: []string{"Swap", "Mint", "Burn"}},
+		{raw: "Swap,swap,,Mint", want: []string{"Swap", "Mint"}},
+		{raw: "Swap,Transfer", wantErr: true},
+		{raw: " , ", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseCodexEventTypes(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseCodexEventTypes(%q) error = %v, want error %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("parseCodexEventTypes(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ============================================================================
+// healthcheck
+// Probes the running monitor's /healthz on METRICS_ADDR, for container health
+// checks in images without curl
+// ============================================================================
+
+const healthCheckTimeout = 5 * time.Second
+
+// runHealthCheck returns 0 if /healthz answers 200 within healthCheckTimeout, 1 otherwise
+func runHealthCheck(envFile string) int {
+	config, err := loadEnv(envFile)
+	if err != nil {
+		fmt.Printf("healthcheck: %v\n", err)
+		return 1
+	}
+
+	url := "http://" + healthCheckHost(config.MetricsAddr) + "/healthz"
+	client := &http.Client{Timeout: healthCheckTimeout}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		fmt.Printf("healthcheck: %v\n", err)
+		return 1
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		fmt.Printf("healthcheck: %s returned %d\n", url, resp.StatusCode)
+		return 1
+	}
+	return 0
+}
+
+// healthCheckHost turns a listen address into one to dial: an empty or wildcard host becomes loopback
+func healthCheckHost(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
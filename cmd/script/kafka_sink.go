@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ============================================================================
+// Kafka Sink
+// Produces every measured trade as JSON, keyed by provider:chain
+// ============================================================================
+
+const (
+	kafkaSinkBufferSize = 1000
+	kafkaSinkBatchSize  = 100
+)
+
+type KafkaSink struct {
+	writer  *kafka.Writer
+	brokers []string
+
+	queue chan NormalizedTrade
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewKafkaSink creates a producer for the given comma-separated broker list and starts the background flusher.
+// Brokers are dialed lazily so a Kafka outage never blocks startup.
+func NewKafkaSink(brokers string, topic string) (*KafkaSink, error) {
+	var addrs []string
+	for _, broker := range strings.Split(brokers, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			addrs = append(addrs, broker)
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("invalid KAFKA_BROKERS: no brokers in %q", brokers)
+	}
+
+	sink := &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(addrs...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			BatchSize:    kafkaSinkBatchSize,
+			BatchTimeout: 100 * time.Millisecond,
+			WriteTimeout: 5 * time.Second,
+			RequiredAcks: kafka.RequireOne,
+		},
+		brokers: addrs,
+		queue:   make(chan NormalizedTrade, kafkaSinkBufferSize),
+		done:    make(chan struct{}),
+	}
+
+	go sink.flushLoop()
+
+	return sink, nil
+}
+
+// Publish queues a trade for production, dropping it if the buffer is full
+func (s *KafkaSink) Publish(trade NormalizedTrade) {
+	select {
+	case s.queue <- trade:
+	default:
+		RecordSinkDropped("kafka")
+	}
+}
+
+// Close stops accepting trades, flushes what is buffered and closes the producer
+func (s *KafkaSink) Close() error {
+	s.once.Do(func() {
+		close(s.queue)
+	})
+	<-s.done
+
+	return s.writer.Close()
+}
+
+// Probe dials the first reachable broker and checks the topic exists
+func (s *KafkaSink) Probe() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var lastErr error
+	for _, broker := range s.brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			lastErr = fmt.Errorf("dial %s failed: %w", broker, err)
+			continue
+		}
+		defer conn.Close()
+
+		if _, err := conn.ReadPartitions(s.writer.Topic); err != nil {
+			return fmt.Errorf("topic %q: %w", s.writer.Topic, err)
+		}
+		return nil
+	}
+	return lastErr
+}
+
+func (s *KafkaSink) flushLoop() {
+	defer close(s.done)
+
+	batch := make([]kafka.Message, 0, kafkaSinkBatchSize)
+	for trade := range s.queue {
+		batch = append(batch, s.message(trade))
+
+		// Drain whatever else is already queued so one write carries a full batch
+	drain:
+		for len(batch) < kafkaSinkBatchSize {
+			select {
+			case next, ok := <-s.queue:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, s.message(next))
+			default:
+				break drain
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := s.writer.WriteMessages(ctx, batch...); err != nil {
+			log.Printf("[KAFKA-SINK] Write of %d messages failed: %v", len(batch), err)
+		}
+		cancel()
+
+		batch = batch[:0]
+	}
+}
+
+func (s *KafkaSink) message(trade NormalizedTrade) kafka.Message {
+	// NormalizedTrade only holds strings, ints and times, so Marshal cannot fail
+	value, _ := json.Marshal(trade)
+
+	return kafka.Message{
+		Key:   []byte(trade.Provider + ":" + trade.Chain),
+		Value: value,
+		Time:  trade.ReceivedAt,
+	}
+}
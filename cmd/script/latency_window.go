@@ -0,0 +1,443 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ============================================================================
+// Sliding-Window Aggregator
+// Keeps the recent head lag samples per provider/chain for human-facing summaries
+// ============================================================================
+
+// latencyWindowMaxSamples caps each series so a busy pool cannot grow the window unbounded
+const latencyWindowMaxSamples = 4096
+
+// madMinSamples is how many samples a series needs before MAD flagging kicks in
+const madMinSamples = 20
+
+// madRefreshInterval bounds how often a series recomputes its median and MAD
+const madRefreshInterval = time.Second
+
+// madScale turns a median absolute deviation into a standard-deviation equivalent for normal data
+const madScale = 1.4826
+
+// Outlier policies for the summaries. Raw samples always reach Prometheus unchanged.
+const (
+	outlierPolicyNone = "none" // Summarize every sample
+	outlierPolicyTrim = "trim" // Drop the top and bottom OUTLIER_TRIM_PERCENT before summarizing
+	outlierPolicyMAD  = "mad"  // Flag samples beyond OUTLIER_MAD_K MADs from the median and leave them out
+)
+
+// OutlierPolicy decides which samples the summaries leave out
+type OutlierPolicy struct {
+	Mode        string
+	TrimPercent float64
+	MADK        float64
+}
+
+func (p OutlierPolicy) String() string {
+	switch p.Mode {
+	case outlierPolicyTrim:
+		return fmt.Sprintf("trim %g%%", p.TrimPercent)
+	case outlierPolicyMAD:
+		return fmt.Sprintf("mad k=%g", p.MADK)
+	default:
+		return outlierPolicyNone
+	}
+}
+
+// WindowStats summarizes one provider/chain series over the window
+type WindowStats struct {
+	Provider string  `json:"provider"`
+	Chain    string  `json:"chain"`
+	Region   string  `json:"region"`
+	Samples  int     `json:"samples"`  // Samples in the window, outliers included
+	Outliers int     `json:"outliers"` // Samples left out of the summary
+	Min      float64 `json:"min_ms"`
+	Avg      float64 `json:"avg_ms"`
+	Max      float64 `json:"max_ms"`
+	P50      float64 `json:"p50_ms"`
+	P90      float64 `json:"p90_ms"`
+	P95      float64 `json:"p95_ms"`
+	P99      float64 `json:"p99_ms"`
+
+	// Fewer than STATS_MIN_SAMPLES samples left after outliers: the percentiles are zero here and
+	// null in JSON, as they would say nothing about the provider
+	Insufficient bool `json:"insufficient_data"`
+}
+
+// MarshalJSON reports the percentiles of an insufficient series as null
+func (s WindowStats) MarshalJSON() ([]byte, error) {
+	type plain WindowStats
+	if !s.Insufficient {
+		return json.Marshal(plain(s))
+	}
+	return json.Marshal(struct {
+		plain
+		P50 *float64 `json:"p50_ms"`
+		P90 *float64 `json:"p90_ms"`
+		P95 *float64 `json:"p95_ms"`
+		P99 *float64 `json:"p99_ms"`
+	}{plain: plain(s)})
+}
+
+type windowSample struct {
+	at      time.Time
+	lagMs   float64
+	outlier bool
+}
+
+// windowSeries is a ring buffer of the samples of one provider/chain, oldest first
+type windowSeries struct {
+	provider string
+	chain    string
+	region   string
+	samples  []windowSample
+	head     int
+	n        int
+
+	// Running sum of the samples not flagged as outliers, for the mean
+	sum float64
+
+	// Cached median and scaled MAD for flagging new samples
+	median    float64
+	spread    float64
+	refreshed time.Time
+}
+
+// LatencyWindow is a Sink that aggregates head lag per provider/chain over a sliding window
+type LatencyWindow struct {
+	window     time.Duration
+	policy     OutlierPolicy
+	baseline   string // BASELINE_PROVIDER, empty for none
+	minSamples int    // STATS_MIN_SAMPLES, below which a series reports no percentiles
+
+	mu     sync.Mutex
+	series map[string]*windowSeries
+}
+
+// NewLatencyWindow creates an aggregator over the given window. Series with fewer than minSamples
+// samples report insufficient data instead of percentiles. With a baseline provider, /stats also
+// reports every other provider's p50 and p95 as a delta from it.
+func NewLatencyWindow(window time.Duration, policy OutlierPolicy, baseline string, minSamples int) *LatencyWindow {
+	return &LatencyWindow{
+		window:     window,
+		policy:     policy,
+		baseline:   baseline,
+		minSamples: minSamples,
+		series:     make(map[string]*windowSeries),
+	}
+}
+
+// Publish adds a trade to its series, flagging it as an outlier under the MAD policy.
+// Trades with negative lag are clock skew and stay out of the summaries, as do trades
+// on pools not every provider on the chain subscribes to (see comparisonEligible).
+func (w *LatencyWindow) Publish(trade NormalizedTrade) {
+	if trade.LagMs < 0 {
+		return
+	}
+	if !tradePoolComparable(trade.Pool) {
+		RecordComparisonExcluded(trade.Provider, trade.Chain, trade.Region)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := trade.Provider + ":" + trade.Chain + ":" + trade.Region
+	s, ok := w.series[key]
+	if !ok {
+		s = &windowSeries{
+			provider: trade.Provider,
+			chain:    trade.Chain,
+			region:   trade.Region,
+			samples:  make([]windowSample, latencyWindowMaxSamples),
+		}
+		w.series[key] = s
+	}
+
+	s.expire(trade.ReceivedAt.Add(-w.window))
+
+	sample := windowSample{at: trade.ReceivedAt, lagMs: float64(trade.LagMs)}
+	if w.policy.Mode == outlierPolicyMAD && s.isOutlier(sample, w.policy.MADK) {
+		sample.outlier = true
+		RecordLatencyOutlier(trade.Provider, trade.Chain, trade.Region)
+	}
+	s.push(sample)
+}
+
+// Close is a no-op, the window holds no external resources
+func (w *LatencyWindow) Close() error {
+	return nil
+}
+
+// Snapshot summarizes every series with samples in the window, sorted by provider, chain then region
+func (w *LatencyWindow) Snapshot() []WindowStats {
+	return w.SnapshotAt(time.Now())
+}
+
+// SnapshotAt is Snapshot with the window ending at now, for replays of past frames
+func (w *LatencyWindow) SnapshotAt(now time.Time) []WindowStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cutoff := now.Add(-w.window)
+
+	var stats []WindowStats
+	for _, s := range w.series {
+		s.expire(cutoff)
+		if s.n == 0 {
+			continue
+		}
+		stats = append(stats, s.summarize(w.policy, w.minSamples))
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Provider != stats[j].Provider {
+			return stats[i].Provider < stats[j].Provider
+		}
+		if stats[i].Chain != stats[j].Chain {
+			return stats[i].Chain < stats[j].Chain
+		}
+		return stats[i].Region < stats[j].Region
+	})
+	return stats
+}
+
+func (s *windowSeries) at(i int) *windowSample {
+	return &s.samples[(s.head+i)%len(s.samples)]
+}
+
+// push appends a sample, evicting the oldest one when the buffer is full
+func (s *windowSeries) push(sample windowSample) {
+	if s.n == len(s.samples) {
+		s.evict()
+	}
+	*s.at(s.n) = sample
+	s.n++
+	s.add(sample, 1)
+}
+
+// expire drops samples received before cutoff
+func (s *windowSeries) expire(cutoff time.Time) {
+	for s.n > 0 && s.at(0).at.Before(cutoff) {
+		s.evict()
+	}
+}
+
+// evict drops the oldest sample
+func (s *windowSeries) evict() {
+	s.add(*s.at(0), -1)
+	s.head = (s.head + 1) % len(s.samples)
+	s.n--
+}
+
+// add updates the running sum with a sample entering (sign 1) or leaving (sign -1) the window
+func (s *windowSeries) add(sample windowSample, sign int) {
+	if sample.outlier {
+		return
+	}
+	s.sum += float64(sign) * sample.lagMs
+}
+
+// isOutlier reports whether a sample is more than k scaled MADs from the window median
+func (s *windowSeries) isOutlier(sample windowSample, k float64) bool {
+	if s.n < madMinSamples {
+		return false
+	}
+
+	if sample.at.Sub(s.refreshed) >= madRefreshInterval {
+		values := s.values(false)
+		s.median = percentile(values, 50)
+
+		deviations := make([]float64, len(values))
+		for i, v := range values {
+			deviations[i] = math.Abs(v - s.median)
+		}
+		sort.Float64s(deviations)
+		s.spread = madScale * percentile(deviations, 50)
+		s.refreshed = sample.at
+	}
+
+	// A flat series has no spread to measure against
+	if s.spread == 0 {
+		return false
+	}
+	return math.Abs(sample.lagMs-s.median) > k*s.spread
+}
+
+// values returns the sorted lags in the window, optionally leaving out flagged outliers
+func (s *windowSeries) values(skipOutliers bool) []float64 {
+	values := make([]float64, 0, s.n)
+	for i := 0; i < s.n; i++ {
+		sample := s.at(i)
+		if skipOutliers && sample.outlier {
+			continue
+		}
+		values = append(values, sample.lagMs)
+	}
+	sort.Float64s(values)
+	return values
+}
+
+func (s *windowSeries) summarize(policy OutlierPolicy, minSamples int) WindowStats {
+	values := s.values(policy.Mode == outlierPolicyMAD)
+	sum := s.sum
+
+	if policy.Mode == outlierPolicyTrim {
+		trim := int(float64(len(values)) * policy.TrimPercent / 100)
+		if 2*trim < len(values) {
+			// Take the trimmed ends back out of the running sum instead of re-adding the middle
+			for _, v := range values[:trim] {
+				sum -= v
+			}
+			for _, v := range values[len(values)-trim:] {
+				sum -= v
+			}
+			values = values[trim : len(values)-trim]
+		}
+	}
+
+	stats := WindowStats{
+		Provider: s.provider,
+		Chain:    s.chain,
+		Region:   s.region,
+		Samples:  s.n,
+		Outliers: s.n - len(values),
+	}
+	if len(values) < minSamples {
+		stats.Insufficient = true
+	} else {
+		stats.P50 = percentile(values, 50)
+		stats.P90 = percentile(values, 90)
+		stats.P95 = percentile(values, 95)
+		stats.P99 = percentile(values, 99)
+	}
+	if len(values) > 0 {
+		stats.Min = values[0]
+		stats.Max = values[len(values)-1]
+		stats.Avg = sum / float64(len(values))
+	}
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted values, 0 if empty
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// registerWindowSamples exports stats_window_samples and stats_window_sufficient from the /stats window
+func registerWindowSamples(window *LatencyWindow) {
+	prometheus.MustRegister(windowSamplesCollector{window: window})
+}
+
+// windowSamplesCollector exports the sample count behind each series, evaluated on scrape
+type windowSamplesCollector struct {
+	window *LatencyWindow
+}
+
+func (windowSamplesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- statsWindowSamples
+	ch <- statsWindowSufficient
+}
+
+func (c windowSamplesCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.window.Snapshot() {
+		sufficient := 1.0
+		if s.Insufficient {
+			sufficient = 0
+		}
+		ch <- prometheus.MustNewConstMetric(statsWindowSamples, prometheus.GaugeValue, float64(s.Samples-s.Outliers), s.Provider, s.Chain, s.Region)
+		ch <- prometheus.MustNewConstMetric(statsWindowSufficient, prometheus.GaugeValue, sufficient, s.Provider, s.Chain, s.Region)
+	}
+}
+
+// runLatencyWindowPrinter prints the window summary every interval until stopChan closes
+func runLatencyWindowPrinter(window *LatencyWindow, interval time.Duration, stopChan <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			printWindowStats(window)
+		}
+	}
+}
+
+func printWindowStats(window *LatencyWindow) {
+	printWindowSummary(window, window.Snapshot())
+}
+
+func printWindowSummary(window *LatencyWindow, stats []WindowStats) {
+	if len(stats) == 0 {
+		return
+	}
+
+	fmt.Printf("\n=== Head Lag, last %v (outliers: %s, percentiles from %d samples) ===\n", window.window, window.policy, window.minSamples)
+	fmt.Printf("%-16s %-10s %8s %8s %9s %9s %9s %9s %9s %9s\n", "PROVIDER", "CHAIN", "SAMPLES", "OUTLIERS", "MIN ms", "AVG ms", "MAX ms", "P50 ms", "P90 ms", "P99 ms")
+	for _, s := range stats {
+		if s.Insufficient {
+			fmt.Printf("%-16s %-10s %8d %8d %9.0f %9.0f %9.0f %29s\n", s.Provider, s.Chain, s.Samples, s.Outliers, s.Min, s.Avg, s.Max, "insufficient data")
+			continue
+		}
+		fmt.Printf("%-16s %-10s %8d %8d %9.0f %9.0f %9.0f %9.0f %9.0f %9.0f\n", s.Provider, s.Chain, s.Samples, s.Outliers, s.Min, s.Avg, s.Max, s.P50, s.P90, s.P99)
+	}
+	fmt.Println()
+}
+
+// ServeStats serves the window summary as JSON on /stats
+func (w *LatencyWindow) ServeStats(rw http.ResponseWriter, r *http.Request) {
+	stats := w.Snapshot()
+	if stats == nil {
+		stats = []WindowStats{}
+	}
+
+	var deltas []BaselineDelta
+	if w.baseline != "" {
+		deltas = baselineDeltas(stats, w.baseline)
+		if deltas == nil {
+			deltas = []BaselineDelta{}
+		}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(struct {
+		Window           string               `json:"window"`
+		OutlierPolicy    string               `json:"outlier_policy"`
+		Stats            []WindowStats        `json:"stats"`
+		Matrix           []ChainMatrix        `json:"matrix"`
+		Surfaces         []SurfaceAttribution `json:"surfaces"`
+		Baseline         string               `json:"baseline,omitempty"`
+		BaselineDeltas   []BaselineDelta      `json:"baseline_deltas,omitempty"` // Only with BASELINE_PROVIDER
+		MinSamples       int                  `json:"min_samples"`
+		MetadataCoverage []CoverageSummary    `json:"metadata_coverage"`
+	}{
+		Window:           w.window.String(),
+		OutlierPolicy:    w.policy.String(),
+		Stats:            stats,
+		Matrix:           comparisonMatrix(stats, poolLivenessSnapshot(time.Now())),
+		Surfaces:         surfaceAttribution(stats, w.window, time.Now()),
+		Baseline:         w.baseline,
+		BaselineDeltas:   deltas,
+		MinSamples:       w.minSamples,
+		MetadataCoverage: coverageSnapshot(),
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Launchpad Discovery Race
+// Matches the same new token across launchpad feeds to see which provider surfaced it first
+// ============================================================================
+
+// launchpadRaceWindow is how long a token waits for the other providers to report it
+const launchpadRaceWindow = 10 * time.Minute
+
+// launchpadSighting is a token reported by at least one launchpad feed
+type launchpadSighting struct {
+	first   string
+	firstAt time.Time
+	seen    map[string]bool
+}
+
+// launchpadRace correlates launchpad discoveries by chain and token address
+type launchpadRace struct {
+	mu        sync.Mutex
+	sightings map[string]*launchpadSighting
+	lastPrune time.Time
+}
+
+func newLaunchpadRace() *launchpadRace {
+	return &launchpadRace{
+		sightings: make(map[string]*launchpadSighting),
+		lastPrune: time.Now(),
+	}
+}
+
+// launchpadRaceResult is the outcome of a provider reporting a token another provider already reported
+type launchpadRaceResult struct {
+	winner  string        // Provider that reported the token first
+	behind  time.Duration // How long after the winner this provider reported it
+	decided bool          // True for the first report after the winner's, which settles the race
+}
+
+// observe records provider's discovery of a token. It returns ok=false for the first report of a token
+// and for repeated reports from the same provider, which are de-duplicated.
+func (r *launchpadRace) observe(provider string, chain string, tokenAddress string, seenAt time.Time) (launchpadRaceResult, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.prune(seenAt)
+
+	// EVM addresses come in mixed case depending on the provider, Solana addresses are case-sensitive
+	key := chain + ":" + normalizePoolAddress(tokenAddress)
+
+	sighting, ok := r.sightings[key]
+	if !ok {
+		r.sightings[key] = &launchpadSighting{
+			first:   provider,
+			firstAt: seenAt,
+			seen:    map[string]bool{provider: true},
+		}
+		return launchpadRaceResult{}, false
+	}
+	if sighting.seen[provider] {
+		return launchpadRaceResult{}, false
+	}
+	sighting.seen[provider] = true
+
+	return launchpadRaceResult{
+		winner:  sighting.first,
+		behind:  seenAt.Sub(sighting.firstAt),
+		decided: len(sighting.seen) == 2,
+	}, true
+}
+
+// prune drops sightings older than launchpadRaceWindow, at most once a minute
+func (r *launchpadRace) prune(now time.Time) {
+	if now.Sub(r.lastPrune) < time.Minute {
+		return
+	}
+	r.lastPrune = now
+
+	for key, sighting := range r.sightings {
+		if now.Sub(sighting.firstAt) > launchpadRaceWindow {
+			delete(r.sightings, key)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ============================================================================
+// Leaderboard
+// Ranks providers per chain by p50 head lag over the sliding window
+// ============================================================================
+
+// runLeaderboardPrinter prints the per-chain leaderboard every interval until stopChan closes
+func runLeaderboardPrinter(window *LatencyWindow, interval time.Duration, stopChan <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			printLeaderboard(window)
+		}
+	}
+}
+
+// leaderboard groups window stats by chain and region, fastest p50 first within each group.
+// Groups are keyed "<chain> [<region>]", so providers are only ranked from the same vantage point.
+func leaderboard(stats []WindowStats) (chains []string, ranked map[string][]WindowStats) {
+	ranked = make(map[string][]WindowStats)
+	for _, s := range stats {
+		if s.Insufficient {
+			continue // Too few samples to rank on, see STATS_MIN_SAMPLES
+		}
+		if !isComparedProvider(s.Provider) {
+			continue
+		}
+		key := fmt.Sprintf("%s [%s]", s.Chain, s.Region)
+		if _, ok := ranked[key]; !ok {
+			chains = append(chains, key)
+		}
+		ranked[key] = append(ranked[key], s)
+	}
+
+	sort.Strings(chains)
+	for _, chain := range chains {
+		entries := ranked[chain]
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].P50 < entries[j].P50
+		})
+	}
+	return chains, ranked
+}
+
+func printLeaderboard(window *LatencyWindow) {
+	chains, ranked := leaderboard(window.Snapshot())
+	if len(chains) == 0 {
+		return
+	}
+
+	fmt.Printf("\n=== Fastest Provider per Chain, p50 head lag over last %v (* = leader) ===\n", window.window)
+	for _, chain := range chains {
+		fmt.Printf("%s:\n", chain)
+		for i, s := range ranked[chain] {
+			marker := " "
+			if i == 0 {
+				marker = "*"
+			}
+			fmt.Printf("  %s %d. %-16s %9.0f ms  (%d samples)\n", marker, i+1, s.Provider, s.P50, s.Samples-s.Outliers)
+		}
+	}
+	fmt.Println()
+}
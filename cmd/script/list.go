@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ============================================================================
+// list
+// Prints every provider the benchmark can run, the chains it covers, what it
+// needs to be enabled and whether the loaded config enables it
+// ============================================================================
+
+// providerEntry describes a provider and where its monitors run
+type providerEntry struct {
+	provider string
+	monitors []string        // As reported to RecordProviderStatus
+	requires string          // Config key that must be set, empty if none
+	chains   func() []string // Chains the provider covers, nil if it is not chain-based
+}
+
+// providerCatalog lists every provider main starts a monitor for
+var providerCatalog = []providerEntry{
+	{"mobula", []string{"websocket", "rest", "pulse", "metadata"}, "MOBULA_API_KEY", registryChains(ChainInfo.SupportsMobula)},
+	{"codex", []string{"websocket", "rest"}, "DEFINED_SESSION_COOKIE", registryChains(ChainInfo.SupportsCodex)},
+	{"geckoterminal", []string{"websocket"}, "", registryChains(geckoTerminalCovers)},
+	{"goldrush", []string{"rest"}, "GOLDRUSH_API_KEY", registryChains(ChainInfo.SupportsGoldRush)},
+	{"syve", []string{"rest"}, "SYVE_API_KEY", registryChains(syveSupports)},
+	{"mobula", []string{"quote"}, "", quoteChains("mobula")},
+	{"jupiter", []string{"quote"}, "", quoteChains("jupiter")},
+	{"openocean", []string{"quote"}, "", quoteChains("openocean")},
+	{"paraswap", []string{"quote"}, "", quoteChains("paraswap")},
+	{"lifi", []string{"quote"}, "", quoteChains("lifi")},
+	{"kyberswap", []string{"quote"}, "", quoteChains("kyberswap")},
+	{"binance", []string{"cex-reference"}, "CEX_REFERENCE_SYMBOLS", nil},
+}
+
+// supportedChainNames lists the registry chains a provider covers
+func supportedChainNames(supported func(ChainInfo) bool) []string {
+	var names []string
+	for _, chain := range chainRegistry {
+		if supported(chain) {
+			names = append(names, chain.ChainName)
+		}
+	}
+	return names
+}
+
+func registryChains(supported func(ChainInfo) bool) func() []string {
+	return func() []string { return supportedChainNames(supported) }
+}
+
+// quoteChains lists the chains the quote monitor quotes a provider on
+func quoteChains(provider string) func() []string {
+	return func() []string {
+		var names []string
+		for _, pair := range quoteImpactPairs() {
+			if pair.provider == provider {
+				names = append(names, pair.chain.Name)
+			}
+		}
+		return names
+	}
+}
+
+// ProviderListing is one provider as printed by list
+type ProviderListing struct {
+	Provider      string   `json:"provider"`
+	Monitors      []string `json:"monitors"`
+	Requires      string   `json:"requires,omitempty"` // Config key the provider needs
+	Chains        []string `json:"chains,omitempty"`
+	EnabledChains []string `json:"enabled_chains,omitempty"` // Chains PROVIDER_CHAINS leaves enabled
+	Enabled       bool     `json:"enabled"`                  // Would run with the loaded config
+}
+
+// providerListings evaluates the catalog against config
+func providerListings(config *Config) []ProviderListing {
+	values := make(map[string]string)
+	for _, field := range config.configFields() {
+		values[field.key] = *field.target
+	}
+
+	listings := make([]ProviderListing, 0, len(providerCatalog))
+	for _, entry := range providerCatalog {
+		listing := ProviderListing{
+			Provider: entry.provider,
+			Monitors: entry.monitors,
+			Requires: entry.requires,
+			Enabled:  entry.requires == "" || values[entry.requires] != "",
+		}
+		if entry.chains != nil {
+			listing.Chains = entry.chains()
+			for _, chain := range listing.Chains {
+				if chainEnabled(entry.provider, chain) {
+					listing.EnabledChains = append(listing.EnabledChains, chain)
+				}
+			}
+			listing.Enabled = listing.Enabled && len(listing.EnabledChains) > 0
+		}
+		listings = append(listings, listing)
+	}
+	return listings
+}
+
+// runList prints the provider listing as text, or as JSON with asJSON, and returns the process exit code
+func runList(envFile string, asJSON bool) int {
+	config, err := loadEnv(envFile)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return 1
+	}
+	initProviderChains(config.ProviderChains)
+
+	listings := providerListings(config)
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(listings); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Println("=== Providers ===")
+	for _, listing := range listings {
+		status := "disabled"
+		if listing.Enabled {
+			status = "enabled"
+		}
+		fmt.Printf("\n%s (%s): %s\n", listing.Provider, strings.Join(listing.Monitors, ", "), status)
+
+		if listing.Requires != "" {
+			fmt.Printf("  Requires: %s\n", listing.Requires)
+		} else {
+			fmt.Println("  Requires: nothing")
+		}
+		if listing.Chains != nil {
+			fmt.Printf("  Chains: %s\n", strings.Join(listing.Chains, ", "))
+			if len(listing.EnabledChains) < len(listing.Chains) {
+				fmt.Printf("  Enabled by PROVIDER_CHAINS: %s\n", orNone(listing.EnabledChains))
+			}
+		}
+	}
+	return 0
+}
+
+// orNone joins names, or returns "none" for an empty list
+func orNone(names []string) string {
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
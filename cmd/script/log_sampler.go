@@ -0,0 +1,36 @@
+package main
+
+import "sync"
+
+// tradeLogCounts counts trades per provider so each monitor logs one in every LOG_SAMPLE_RATE
+var tradeLogCounts struct {
+	sync.Mutex
+	counts map[string]uint64
+}
+
+// sampleTradeLog reports whether a provider's trade should be logged under LOG_SAMPLE_RATE.
+// Callers still log every trade above their lag threshold regardless.
+func sampleTradeLog(provider string) bool {
+	rate := uint64(currentLogSampleRate())
+	if rate == 0 {
+		return false
+	}
+
+	tradeLogCounts.Lock()
+	defer tradeLogCounts.Unlock()
+
+	if tradeLogCounts.counts == nil {
+		tradeLogCounts.counts = make(map[string]uint64)
+	}
+	tradeLogCounts.counts[provider]++
+	return tradeLogCounts.counts[provider]%rate == 0
+}
+
+// safeTruncate returns at most the first n bytes of s, for logging hashes and response bodies
+// that may be shorter than expected or empty
+func safeTruncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
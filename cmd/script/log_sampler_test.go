@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestSafeTruncate(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		n    int
+		want string
+	}{
+		{"longer", "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", 16, "0x88e6a0c2ddd26f"},
+		{"exact", "0x88e6a0c2ddd26f", 16, "0x88e6a0c2ddd26f"},
+		{"shorter hash", "0xabc", 16, "0xabc"},
+		{"empty", "", 16, ""},
+		{"zero", "0xabc", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := safeTruncate(tt.s, tt.n); got != tt.want {
+			t.Errorf("%s: safeTruncate(%q, %d) = %q, want %q", tt.name, tt.s, tt.n, got, tt.want)
+		}
+	}
+}
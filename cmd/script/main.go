@@ -1,7 +1,9 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
@@ -9,16 +11,47 @@ import (
 )
 
 func main() {
+	envFile := flag.String("env-file", "", "path to the env file (default: $ENV_FILE, then .env)")
+	jsonOutput := flag.Bool("json", false, "print the list subcommand's output as JSON")
+	flag.Parse()
+
+	switch flag.Arg(0) {
+	case "check-config":
+		// Also accept flags after the subcommand: check-config --env-file path
+		flag.CommandLine.Parse(flag.Args()[1:])
+		os.Exit(runCheckConfig(*envFile))
+	case "selftest":
+		flag.CommandLine.Parse(flag.Args()[1:])
+		os.Exit(runSelfTest(*envFile))
+	case "list":
+		// list [--env-file path] [--json]
+		flag.CommandLine.Parse(flag.Args()[1:])
+		os.Exit(runList(*envFile, *jsonOutput))
+	case "healthcheck":
+		flag.CommandLine.Parse(flag.Args()[1:])
+		os.Exit(runHealthCheck(*envFile))
+	case "replay":
+		// replay [--env-file path] <capture file>
+		flag.CommandLine.Parse(flag.Args()[1:])
+		if flag.NArg() != 1 {
+			fmt.Println("usage: replay [--env-file path] <capture file>")
+			os.Exit(2)
+		}
+		os.Exit(runReplay(*envFile, flag.Arg(0)))
+	}
+
 	fmt.Println("=== Aggregator Indexation Lag Monitor ===")
 	fmt.Println("Measuring real-time indexation lag (head lag) for blockchain data APIs")
 	fmt.Println("Press Ctrl+C to stop")
 	fmt.Println()
 
-	config, err := loadEnv()
+	config, err := loadEnv(*envFile)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
+	logConfigSources(config)
+	applyHotSettings(config)
 
 	// Use session cookie from environment (scraping requires GUI, doesn't work on Railway)
 	if config.DefinedSessionCookie == "" {
@@ -28,7 +61,75 @@ func main() {
 		fmt.Printf("Using DEFINED_SESSION_COOKIE from environment (length: %d)\n", len(config.DefinedSessionCookie))
 	}
 
-	fmt.Println("Metrics will be exposed on :2112/metrics for Prometheus")
+	initProviderChains(config.ProviderChains)
+	initProviderURLs(config.ProviderURLs)
+	initMetricLabels(config.MetricLabels)
+	initLaunchpads(config.Launchpads)
+	initPlainOutput(config.PlainOutput)
+	registerPoolList(config.Pools)
+	resolveTokenPools(config)
+	initPoolOverrides(config.PoolOverrides)
+	logEffectiveConfig(config)
+	fmt.Println()
+	reportComparisonEligibility(config.MonitorRegion)
+	validatePools(config)
+
+	initMetadataQueue(config.MetadataQueueSize)
+	initMetadataLimits(config.MetadataConcurrency)
+	initMoralisQueue(config.MoralisQueueSize)
+
+	if err := initFrameCapture(config.CaptureDir); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if config.CaptureDir != "" {
+		fmt.Printf("Capturing raw WebSocket frames to %s\n", config.CaptureDir)
+	}
+
+	promRec := NewPrometheusRecorder(config.EWMAAlpha, config.TradeTypeMetrics, config.PreciseLagMetrics)
+
+	sinks, err := newSinks(config)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, sink := range sinks {
+		promRec.AddSink(sink)
+	}
+
+	latencyWindow := NewLatencyWindow(config.StatsWindow, config.OutlierPolicy, config.BaselineProvider, config.StatsMinSamples)
+	promRec.AddSink(latencyWindow)
+	http.HandleFunc("/stats", latencyWindow.ServeStats)
+	http.HandleFunc("/readyz", readinessHandler(config.ReadyMaxAge, config.ReadyQuorum))
+	registerSurfaceAttribution(latencyWindow, config.MonitorRegion)
+	registerWindowSamples(latencyWindow)
+	if config.BaselineProvider != "" {
+		registerBaselineDeltas(latencyWindow)
+	}
+
+	firstToIndex := NewFirstToIndex(config.FirstToIndexWindow)
+	promRec.AddSink(firstToIndex)
+
+	if len(config.BlockTimestampRPC) > 0 {
+		promRec.AddSink(NewBlockTimestampCheck(config.BlockTimestampRPC, config.BlockTimestampSampleRate))
+		fmt.Printf("Checking 1 in %d reported block timestamps against RPC on %d chains\n", config.BlockTimestampSampleRate, len(config.BlockTimestampRPC))
+	}
+
+	// OTel export runs alongside Prometheus, never instead of it
+	var rec Recorder = promRec
+	var otelRec *OTelRecorder
+	if config.OTLPEndpoint != "" {
+		otelRec, err = NewOTelRecorder(promRec, config.OTLPEndpoint, config.OTelExportInterval)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		rec = otelRec
+		fmt.Printf("Exporting OTel metrics to %s every %v\n", config.OTLPEndpoint, config.OTelExportInterval)
+	}
+
+	fmt.Printf("Metrics will be exposed on %s/metrics for Prometheus\n", config.MetricsAddr)
+	fmt.Printf("Head lag summary will be served as JSON on %s/stats\n", config.MetricsAddr)
 	fmt.Println()
 
 	sigChan := make(chan os.Signal, 1)
@@ -40,58 +141,134 @@ func main() {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		fmt.Println("Starting Prometheus metrics server on :2112")
-		if err := StartMetricsServer(":2112"); err != nil {
+		fmt.Printf("Starting Prometheus metrics server on %s\n", config.MetricsAddr)
+		if err := StartMetricsServer(config.MetricsAddr, stopChan); err != nil {
 			fmt.Printf("Metrics server error: %v\n", err)
 		}
 	}()
 
+	startMonitors(config, rec, latencyWindow, firstToIndex, stopChan, &wg)
+
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
+	lastConfig := config
+	for running := true; running; {
+		select {
+		case <-hupChan:
+			lastConfig = reloadConfig(*envFile, lastConfig)
+		case <-sigChan:
+			running = false
+		}
+	}
+
+	fmt.Println("\n\nShutting down monitors...")
+	close(stopChan)
+
+	wg.Wait()
+	closeFrameCapture()
+	promRec.Close()
+	if otelRec != nil {
+		otelRec.Close()
+	}
+	fmt.Println("All monitors stopped")
+}
+
+// startMonitors starts every monitor and periodic printer on wg; each returns once stopChan closes.
+// The e2e test runs the same set against mock providers.
+func startMonitors(config *Config, rec Recorder, latencyWindow *LatencyWindow, firstToIndex *FirstToIndex, stopChan <-chan struct{}, wg *sync.WaitGroup) {
+	// The metadata coverage monitor consumes the tokens Pulse queues; mark it running before
+	// either goroutine starts so Pulse's first tokens are not dropped
+	metadataConsumerRunning.Store(config.MobulaAPIKey != "")
+
 	// Mobula Pulse V2 monitor (for new pool discovery)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		runMobulaPulseMonitor(config, stopChan)
+		runMobulaPulseMonitor(config, rec, stopChan)
+	}()
+
+	// Codex launchpad feed (races Pulse's launchpad tokens)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runCodexLaunchpadMonitor(config, rec, stopChan)
 	}()
 
 	// Mobula REST API monitor
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		runMobulaRESTMonitor(config, stopChan)
+		runMobulaRESTMonitor(config, rec, stopChan)
 	}()
 
 	// Codex REST API monitor
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		runCodexRESTMonitor(config, stopChan)
+		runCodexRESTMonitor(config, rec, stopChan)
+	}()
+
+	// GoldRush REST API monitor (EVM chains)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runGoldRushRESTMonitor(config, rec, stopChan)
+	}()
+
+	// Syve DEX trade index monitor (Ethereum)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runSyveMonitor(config, rec, stopChan)
 	}()
 
 	// Quote API latency monitor (Jupiter, Li.Fi, 1inch, KyberSwap)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		runQuoteAPIMonitor(config, stopChan)
+		runQuoteAPIMonitor(config, rec, stopChan)
 	}()
 
 	// Metadata coverage monitor (Mobula vs Codex)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		runMetadataCoverageMonitor(config, stopChan)
+		runMetadataCoverageMonitor(config, rec, stopChan)
+	}()
+
+	// CEX reference feed (Binance), only when CEX_REFERENCE_SYMBOLS is set
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runCEXReferenceMonitor(config, rec, stopChan)
 	}()
 
 	// Head lag monitor (blockchain head vs aggregator indexed head)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		runHeadLagMonitor(config, stopChan)
+		runHeadLagMonitor(config, rec, stopChan)
 	}()
 
-	<-sigChan
-	fmt.Println("\n\nShutting down monitors...")
-	close(stopChan)
+	// Periodic head lag summary over the sliding window
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runLatencyWindowPrinter(latencyWindow, config.StatsPrintInterval, stopChan)
+	}()
 
-	wg.Wait()
-	fmt.Println("All monitors stopped")
+	// Periodic per-chain leaderboard over the same window
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runLeaderboardPrinter(latencyWindow, config.LeaderboardInterval, stopChan)
+	}()
+
+	// Settles the first-to-index race once each transaction's compare window passes
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runFirstToIndexFlusher(firstToIndex, stopChan)
+	}()
 }
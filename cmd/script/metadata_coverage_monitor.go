@@ -2,12 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,12 +19,6 @@ import (
 // Measures metadata and logo coverage across providers (Mobula, Codex)
 // ============================================================================
 
-const (
-	mobulaTokenDetailsURL = "https://api.mobula.io/api/2/token/details"
-	codexGraphQLURL       = "https://graph.codex.io/graphql"
-	jupiterTokenPageURL   = "https://jup.ag/tokens/"
-)
-
 // TokenToCheck represents a token discovered via Pulse that needs metadata checking
 type TokenToCheck struct {
 	Address    string
@@ -75,7 +72,12 @@ var (
 		Codex:   ProviderCoverage{Provider: "codex"},
 		Jupiter: ProviderCoverage{Provider: "jupiter"},
 	}
-	tokenQueue     = make(chan TokenToCheck, 500)
+	tokenQueue     chan TokenToCheck // Sized by initMetadataQueue
+
+	// Set by startMonitors before runMetadataCoverageMonitor starts, and cleared when it stops;
+	// without a consumer queued tokens would never drain
+	metadataConsumerRunning atomic.Bool
+	metadataNoConsumerOnce  sync.Once
 	metadataClient = &http.Client{Timeout: 10 * time.Second}
 )
 
@@ -109,14 +111,9 @@ func checkMobulaMetadata(token TokenToCheck, apiKey string) MetadataFields {
 	params := url.Values{}
 	params.Add("address", token.Address)
 
-	// Convert chainID to Mobula format
-	blockchain := token.ChainID
-	if blockchain == "solana:solana" {
-		blockchain = "solana"
-	}
-	params.Add("blockchain", blockchain)
+	params.Add("blockchain", canonicalChainID(token.ChainID))
 
-	fullURL := fmt.Sprintf("%s?%s", mobulaTokenDetailsURL, params.Encode())
+	fullURL := fmt.Sprintf("%s?%s", providerURLs.MobulaREST+"/api/2/token/details", params.Encode())
 
 	req, err := http.NewRequest("GET", fullURL, nil)
 	if err != nil {
@@ -219,21 +216,16 @@ type CodexSocialLinks struct {
 	Github    string `json:"github"`
 }
 
+// getCodexNetworkID returns the Codex network ID for a chain ID, 0 if Codex does not index it
 func getCodexNetworkID(chainID string) int {
-	switch chainID {
-	case "solana", "solana:solana":
-		return 1399811149
-	case "evm:1":
-		return 1
-	case "evm:8453":
-		return 8453
-	case "evm:56":
-		return 56
-	case "evm:42161":
-		return 42161
-	default:
-		return 0
-	}
+	chain, _ := lookupChainID(chainID)
+	return chain.NetworkID
+}
+
+// isSolanaChainID reports whether a chain ID is Solana under any of its aliases
+func isSolanaChainID(chainID string) bool {
+	chain, ok := lookupChainID(chainID)
+	return ok && chain.Kind == ChainKindSolana
 }
 
 func checkCodexMetadata(token TokenToCheck, sessionCookie string) MetadataFields {
@@ -245,13 +237,12 @@ func checkCodexMetadata(token TokenToCheck, sessionCookie string) MetadataFields
 		return result
 	}
 
-	// Get JWT token from Defined.fi
-	jwtToken, err := GetDefinedJWTToken(sessionCookie)
+	// Get JWT token from Defined.fi; not tied to shutdown, queued tokens are still checked while draining
+	jwtToken, err := GetDefinedJWTToken(context.Background(), sessionCookie)
 	if err != nil {
 		result.Error = fmt.Sprintf("jwt_token_error: %v", err)
 		return result
 	}
-	apiKey := jwtToken
 
 	// Use token query which returns EnhancedToken with socialLinks and info
 	// https://docs.codex.io/api-reference/queries/token
@@ -294,16 +285,37 @@ func checkCodexMetadata(token TokenToCheck, sessionCookie string) MetadataFields
 		return result
 	}
 
-	req, err := http.NewRequest("POST", codexGraphQLURL, bytes.NewBuffer(jsonBody))
+	result, authFailed := fetchCodexTokenMetadata(jsonBody, jwtToken)
+	if !authFailed {
+		return result
+	}
+
+	// The cached token was rejected before its decoded expiry: retry once with a fresh one
+	RecordAuthFailure("codex", "metadata")
+	InvalidateTokenCache()
+	jwtToken, err = GetDefinedJWTToken(context.Background(), sessionCookie)
 	if err != nil {
-		result.Error = fmt.Sprintf("request_create_error: %v", err)
+		result.Error = fmt.Sprintf("jwt_token_error: %v", err)
 		return result
 	}
+	result, _ = fetchCodexTokenMetadata(jsonBody, jwtToken)
+	return result
+}
+
+// fetchCodexTokenMetadata runs the token query with a JWT, reporting whether Codex rejected the token
+func fetchCodexTokenMetadata(jsonBody []byte, jwtToken string) (MetadataFields, bool) {
+	result := MetadataFields{}
+
+	req, err := http.NewRequest("POST", providerURLs.CodexGraphQL, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		result.Error = fmt.Sprintf("request_create_error: %v", err)
+		return result, false
+	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	if apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	if jwtToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", jwtToken))
 	}
 
 	startTime := time.Now()
@@ -312,30 +324,30 @@ func checkCodexMetadata(token TokenToCheck, sessionCookie string) MetadataFields
 
 	if err != nil {
 		result.Error = fmt.Sprintf("request_error: %v", err)
-		return result
+		return result, false
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		result.Error = fmt.Sprintf("status_%d", resp.StatusCode)
-		return result
+		return result, resp.StatusCode == http.StatusUnauthorized
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		result.Error = fmt.Sprintf("read_error: %v", err)
-		return result
+		return result, false
 	}
 
 	var response CodexTokenResponse
 	if err := json.Unmarshal(body, &response); err != nil {
 		result.Error = fmt.Sprintf("parse_error: %v", err)
-		return result
+		return result, false
 	}
 
 	if len(response.Errors) > 0 {
 		result.Error = fmt.Sprintf("graphql_error: %s", response.Errors[0].Message)
-		return result
+		return result, response.Errors[0].Message == codexUnauthenticatedMessage
 	}
 
 	data := response.Data.Token
@@ -343,7 +355,7 @@ func checkCodexMetadata(token TokenToCheck, sessionCookie string) MetadataFields
 	// Check if token was found
 	if data.Address == "" {
 		result.Error = "token_not_found"
-		return result
+		return result, false
 	}
 
 	// Check each field based on EnhancedToken
@@ -371,7 +383,7 @@ func checkCodexMetadata(token TokenToCheck, sessionCookie string) MetadataFields
 		result.HasTelegram = data.SocialLinks.Telegram != ""
 	}
 
-	return result
+	return result, false
 }
 
 // ============================================================================
@@ -406,13 +418,13 @@ func checkJupiterMetadata(token TokenToCheck) MetadataFields {
 	result := MetadataFields{}
 
 	// Jupiter only supports Solana
-	if token.ChainID != "solana" && token.ChainID != "solana:solana" {
+	if !isSolanaChainID(token.ChainID) {
 		result.Error = "unsupported_chain"
 		return result
 	}
 
 	// Scrape the token page
-	pageURL := jupiterTokenPageURL + token.Address
+	pageURL := providerURLs.JupiterTokens + "/" + token.Address
 
 	req, err := http.NewRequest("GET", pageURL, nil)
 	if err != nil {
@@ -515,6 +527,23 @@ func checkJupiterMetadata(token TokenToCheck) MetadataFields {
 // Stats and Reporting
 // ============================================================================
 
+// metadataCheckResult buckets a check's Error into the result label of metadata_check_result_total
+func metadataCheckResult(checkError string) string {
+	kind, _, _ := strings.Cut(checkError, ":")
+	switch kind {
+	case "":
+		return "success"
+	case "token_not_found", "status_404":
+		return "not_found"
+	case "unsupported_chain":
+		return "unsupported_chain"
+	case "parse_error", "next_data_not_found", "next_data_end_not_found":
+		return "parse_error"
+	default:
+		return "request_error"
+	}
+}
+
 func updateStats(provider string, fields MetadataFields) {
 	coverageStats.mu.Lock()
 	defer coverageStats.mu.Unlock()
@@ -562,108 +591,259 @@ func updateStats(provider string, fields MetadataFields) {
 	}
 }
 
-func printCoverageStats() {
+// CoverageSummary is a provider's metadata coverage as served on /stats.
+// Field coverage is the percentage of successful checks that returned the field; the average
+// latency covers every check, failed ones included, like metadata_api_latency_milliseconds.
+type CoverageSummary struct {
+	Provider     string             `json:"provider"`
+	Checks       int                `json:"checks"`
+	Errors       int                `json:"errors"`
+	AvgLatencyMs float64            `json:"avg_latency_ms"`
+	Coverage     map[string]float64 `json:"coverage_percent,omitempty"`
+}
+
+// coverageSnapshot summarizes every provider's coverage so far, in table order
+func coverageSnapshot() []CoverageSummary {
 	coverageStats.mu.Lock()
 	defer coverageStats.mu.Unlock()
 
-	timestamp := time.Now().UTC().Format("2006-01-02 15:04:05")
-
-	fmt.Printf("\n")
-	fmt.Printf("╔══════════════════════════════════════════════════════════════════════════════╗\n")
-	fmt.Printf("║                    METADATA COVERAGE STATS - %s                   ║\n", timestamp)
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════╣\n")
-	fmt.Printf("║ Provider │ Checks │ Logo  │ Name  │ Symbol│ Desc  │Twitter│Website│Telegram│ Errors │\n")
-	fmt.Printf("╠══════════════════════════════════════════════════════════════════════════════╣\n")
-
+	var summaries []CoverageSummary
 	for _, stats := range []*ProviderCoverage{&coverageStats.Mobula, &coverageStats.Codex, &coverageStats.Jupiter} {
+		summary := CoverageSummary{
+			Provider: stats.Provider,
+			Checks:   stats.TotalChecks,
+			Errors:   stats.ErrorCount,
+		}
 		if stats.TotalChecks == 0 {
-			fmt.Printf("║ %-8s │ %6d │   -   │   -   │   -   │   -   │   -   │   -   │   -    │ %6d ║\n",
-				stats.Provider, stats.TotalChecks, stats.ErrorCount)
+			summaries = append(summaries, summary)
 			continue
 		}
+		summary.AvgLatencyMs = stats.TotalLatencyMs / float64(stats.TotalChecks)
 
 		successChecks := stats.TotalChecks - stats.ErrorCount
 		if successChecks == 0 {
 			successChecks = 1 // Avoid division by zero
 		}
+		percent := func(count int) float64 {
+			return float64(count) / float64(successChecks) * 100
+		}
+		summary.Coverage = map[string]float64{
+			"logo":        percent(stats.LogoCount),
+			"name":        percent(stats.NameCount),
+			"symbol":      percent(stats.SymbolCount),
+			"description": percent(stats.DescCount),
+			"twitter":     percent(stats.TwitterCount),
+			"website":     percent(stats.WebsiteCount),
+			"telegram":    percent(stats.TelegramCount),
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+func printCoverageStats() {
+	summaries := coverageSnapshot()
+
+	timestamp := time.Now().UTC().Format("2006-01-02 15:04:05")
+
+	fmt.Printf("\n")
+	fmt.Print(decorated("╔══════════════════════════════════════════════════════════════════════════════════════════════╗\n"))
+	fmt.Printf(decorated("║                        METADATA COVERAGE STATS - %s                         ║\n"), timestamp)
+	fmt.Print(decorated("╠══════════════════════════════════════════════════════════════════════════════════════════════╣\n"))
+	fmt.Print(decorated("║ Provider │ Checks │ Logo  │ Name  │ Symbol│ Desc  │Twitter│Website│Telegram│ Errors │ Avg ms ║\n"))
+	fmt.Print(decorated("╠══════════════════════════════════════════════════════════════════════════════════════════════╣\n"))
+
+	for _, summary := range summaries {
+		if summary.Checks == 0 {
+			fmt.Printf(decorated("║ %-8s │ %6d │   -   │   -   │   -   │   -   │   -   │   -   │   -    │ %6d │   -    ║\n"),
+				summary.Provider, summary.Checks, summary.Errors)
+			continue
+		}
 
-		fmt.Printf("║ %-8s │ %6d │ %5.1f%%│ %5.1f%%│ %5.1f%%│ %5.1f%%│ %5.1f%%│ %5.1f%%│ %5.1f%% │ %6d ║\n",
-			stats.Provider,
-			stats.TotalChecks,
-			float64(stats.LogoCount)/float64(successChecks)*100,
-			float64(stats.NameCount)/float64(successChecks)*100,
-			float64(stats.SymbolCount)/float64(successChecks)*100,
-			float64(stats.DescCount)/float64(successChecks)*100,
-			float64(stats.TwitterCount)/float64(successChecks)*100,
-			float64(stats.WebsiteCount)/float64(successChecks)*100,
-			float64(stats.TelegramCount)/float64(successChecks)*100,
-			stats.ErrorCount,
+		fmt.Printf(decorated("║ %-8s │ %6d │ %5.1f%%│ %5.1f%%│ %5.1f%%│ %5.1f%%│ %5.1f%%│ %5.1f%%│ %5.1f%% │ %6d │ %6.0f ║\n"),
+			summary.Provider,
+			summary.Checks,
+			summary.Coverage["logo"],
+			summary.Coverage["name"],
+			summary.Coverage["symbol"],
+			summary.Coverage["description"],
+			summary.Coverage["twitter"],
+			summary.Coverage["website"],
+			summary.Coverage["telegram"],
+			summary.Errors,
+			summary.AvgLatencyMs,
 		)
 	}
 
-	fmt.Printf("╚══════════════════════════════════════════════════════════════════════════════╝\n")
+	fmt.Print(decorated("╚══════════════════════════════════════════════════════════════════════════════════════════════╝\n"))
 	fmt.Printf("\n")
 
+	coverageStats.mu.Lock()
 	coverageStats.LastPrint = time.Now()
+	coverageStats.mu.Unlock()
+}
+
+// metadataFieldsTracked lists the coverage fields in table order
+var metadataFieldsTracked = []string{"logo", "name", "symbol", "description", "twitter", "website", "telegram"}
+
+// metadataFieldUnsupported marks the fields a provider never returns, left out of the verdict
+// instead of counting as 0% coverage
+var metadataFieldUnsupported = map[string]map[string]bool{
+	"jupiter": {"description": true, "twitter": true, "website": true, "telegram": true},
+}
+
+// FieldVerdict names the provider with the best and the worst coverage of one field.
+// Spread is the gap between them in percentage points.
+type FieldVerdict struct {
+	Field         string  `json:"field"`
+	Best          string  `json:"best_provider"`
+	BestPercent   float64 `json:"best_percent"`
+	Worst         string  `json:"worst_provider"`
+	WorstPercent  float64 `json:"worst_percent"`
+	SpreadPercent float64 `json:"spread_points"`
+}
+
+// CoverageVerdict is the end-of-run metadata coverage comparison
+type CoverageVerdict struct {
+	TokensChecked int               `json:"tokens_checked"`
+	Fields        []FieldVerdict    `json:"fields"`
+	Providers     []CoverageSummary `json:"providers"`
+}
+
+// coverageVerdict compares providers field by field over the checks so far. Only providers with
+// at least one successful check take part; ties go to the provider listed first in the table.
+func coverageVerdict() CoverageVerdict {
+	summaries := coverageSnapshot()
+
+	coverageStats.mu.Lock()
+	tokensChecked := coverageStats.Mobula.TotalChecks // Mobula is asked about every token
+	coverageStats.mu.Unlock()
+
+	verdict := CoverageVerdict{TokensChecked: tokensChecked, Fields: []FieldVerdict{}, Providers: summaries}
+	for _, field := range metadataFieldsTracked {
+		var fieldVerdict *FieldVerdict
+		for _, summary := range summaries {
+			if summary.Checks == summary.Errors || metadataFieldUnsupported[summary.Provider][field] {
+				continue
+			}
+			percent := summary.Coverage[field]
+			if fieldVerdict == nil {
+				fieldVerdict = &FieldVerdict{Field: field, Best: summary.Provider, BestPercent: percent, Worst: summary.Provider, WorstPercent: percent}
+				continue
+			}
+			if percent > fieldVerdict.BestPercent {
+				fieldVerdict.Best, fieldVerdict.BestPercent = summary.Provider, percent
+			}
+			if percent < fieldVerdict.WorstPercent {
+				fieldVerdict.Worst, fieldVerdict.WorstPercent = summary.Provider, percent
+			}
+		}
+		if fieldVerdict != nil {
+			fieldVerdict.SpreadPercent = fieldVerdict.BestPercent - fieldVerdict.WorstPercent
+			verdict.Fields = append(verdict.Fields, *fieldVerdict)
+		}
+	}
+	return verdict
+}
+
+// printCoverageVerdict prints the per-field winners, then the whole verdict as one JSON line for log collectors
+func printCoverageVerdict() {
+	verdict := coverageVerdict()
+
+	fmt.Printf("=== Metadata Coverage Verdict (%d tokens checked) ===\n", verdict.TokensChecked)
+	fmt.Printf("%-12s %-10s %8s %-10s %8s %8s\n", "FIELD", "BEST", "%", "WORST", "%", "SPREAD")
+	for _, field := range verdict.Fields {
+		fmt.Printf("%-12s %-10s %7.1f%% %-10s %7.1f%% %7.1fpt\n", field.Field, field.Best, field.BestPercent, field.Worst, field.WorstPercent, field.SpreadPercent)
+	}
+	fmt.Println("Note: Jupiter is only asked about Solana tokens")
+
+	if data, err := json.Marshal(verdict); err == nil {
+		fmt.Printf("[METADATA] Final verdict: %s\n", data)
+	}
+	fmt.Println()
 }
 
-func checkTokenMetadata(token TokenToCheck, config *Config) {
+func checkTokenMetadata(token TokenToCheck, config *Config, rec Recorder) {
 	chainName := getChainNameForPulse(token.ChainID)
 
 	// Check Mobula
-	mobulaResult := checkMobulaMetadata(token, config.MobulaAPIKey)
-	updateStats("mobula", mobulaResult)
-
-	// Record Prometheus metrics for Mobula
-	RecordMetadataCoverage("mobula", chainName, "logo", mobulaResult.HasLogo, config.MonitorRegion)
-	RecordMetadataCoverage("mobula", chainName, "description", mobulaResult.HasDescription, config.MonitorRegion)
-	RecordMetadataCoverage("mobula", chainName, "twitter", mobulaResult.HasTwitter, config.MonitorRegion)
-	RecordMetadataCoverage("mobula", chainName, "website", mobulaResult.HasWebsite, config.MonitorRegion)
-	RecordMetadataLatency("mobula", chainName, mobulaResult.ResponseTimeMs, config.MonitorRegion)
+	checkMobula := chainEnabled("mobula", chainName)
+	var mobulaResult MetadataFields
+	if checkMobula {
+		mobulaResult = withMetadataSlot("mobula", func() MetadataFields {
+			return checkMobulaMetadata(token, config.MobulaAPIKey)
+		})
+		updateStats("mobula", mobulaResult)
+
+		// Record Prometheus metrics for Mobula
+		rec.RecordMetadataCoverage("mobula", chainName, "logo", mobulaResult.HasLogo, config.MonitorRegion)
+		rec.RecordMetadataCoverage("mobula", chainName, "description", mobulaResult.HasDescription, config.MonitorRegion)
+		rec.RecordMetadataCoverage("mobula", chainName, "twitter", mobulaResult.HasTwitter, config.MonitorRegion)
+		rec.RecordMetadataCoverage("mobula", chainName, "website", mobulaResult.HasWebsite, config.MonitorRegion)
+		rec.RecordMetadataLatency("mobula", chainName, mobulaResult.ResponseTimeMs, config.MonitorRegion)
+		rec.RecordMetadataCheckResult("mobula", chainName, metadataCheckResult(mobulaResult.Error), config.MonitorRegion)
+		if mobulaResult.Error == "" {
+			rec.RecordMonitorSuccess("mobula", "metadata", config.MonitorRegion)
+		}
+	}
 
 	// Check Codex
-	codexResult := checkCodexMetadata(token, config.DefinedSessionCookie)
-	updateStats("codex", codexResult)
-
-	// Record Prometheus metrics for Codex
-	RecordMetadataCoverage("codex", chainName, "logo", codexResult.HasLogo, config.MonitorRegion)
-	RecordMetadataCoverage("codex", chainName, "description", codexResult.HasDescription, config.MonitorRegion)
-	RecordMetadataCoverage("codex", chainName, "twitter", codexResult.HasTwitter, config.MonitorRegion)
-	RecordMetadataCoverage("codex", chainName, "website", codexResult.HasWebsite, config.MonitorRegion)
-	RecordMetadataLatency("codex", chainName, codexResult.ResponseTimeMs, config.MonitorRegion)
+	checkCodex := chainEnabled("codex", chainName)
+	var codexResult MetadataFields
+	if checkCodex {
+		codexResult = withMetadataSlot("codex", func() MetadataFields {
+			return checkCodexMetadata(token, config.DefinedSessionCookie)
+		})
+		updateStats("codex", codexResult)
+
+		// Record Prometheus metrics for Codex
+		rec.RecordMetadataCoverage("codex", chainName, "logo", codexResult.HasLogo, config.MonitorRegion)
+		rec.RecordMetadataCoverage("codex", chainName, "description", codexResult.HasDescription, config.MonitorRegion)
+		rec.RecordMetadataCoverage("codex", chainName, "twitter", codexResult.HasTwitter, config.MonitorRegion)
+		rec.RecordMetadataCoverage("codex", chainName, "website", codexResult.HasWebsite, config.MonitorRegion)
+		rec.RecordMetadataLatency("codex", chainName, codexResult.ResponseTimeMs, config.MonitorRegion)
+		rec.RecordMetadataCheckResult("codex", chainName, metadataCheckResult(codexResult.Error), config.MonitorRegion)
+	}
 
 	// Check Jupiter (Solana only - scraping frontend)
+	checkJupiter := isSolanaChainID(token.ChainID) && chainEnabled("jupiter", chainName)
 	var jupiterResult MetadataFields
-	if token.ChainID == "solana" || token.ChainID == "solana:solana" {
-		jupiterResult = checkJupiterMetadata(token)
+	if checkJupiter {
+		jupiterResult = withMetadataSlot("jupiter", func() MetadataFields {
+			return checkJupiterMetadata(token)
+		})
 		updateStats("jupiter", jupiterResult)
 
 		// Record Prometheus metrics for Jupiter
-		RecordMetadataCoverage("jupiter", chainName, "logo", jupiterResult.HasLogo, config.MonitorRegion)
-		RecordMetadataCoverage("jupiter", chainName, "description", jupiterResult.HasDescription, config.MonitorRegion)
-		RecordMetadataCoverage("jupiter", chainName, "twitter", jupiterResult.HasTwitter, config.MonitorRegion)
-		RecordMetadataCoverage("jupiter", chainName, "website", jupiterResult.HasWebsite, config.MonitorRegion)
-		RecordMetadataLatency("jupiter", chainName, jupiterResult.ResponseTimeMs, config.MonitorRegion)
+		rec.RecordMetadataCoverage("jupiter", chainName, "logo", jupiterResult.HasLogo, config.MonitorRegion)
+		rec.RecordMetadataCoverage("jupiter", chainName, "description", jupiterResult.HasDescription, config.MonitorRegion)
+		rec.RecordMetadataCoverage("jupiter", chainName, "twitter", jupiterResult.HasTwitter, config.MonitorRegion)
+		rec.RecordMetadataCoverage("jupiter", chainName, "website", jupiterResult.HasWebsite, config.MonitorRegion)
+		rec.RecordMetadataLatency("jupiter", chainName, jupiterResult.ResponseTimeMs, config.MonitorRegion)
+		rec.RecordMetadataCheckResult("jupiter", chainName, metadataCheckResult(jupiterResult.Error), config.MonitorRegion)
 	}
 
-	// Single condensed log line
+	// Single condensed log line, "-" for providers not asked
 	boolToIcon := func(b bool) string {
 		if b {
 			return "✓"
 		}
 		return "✗"
 	}
+	icons := func(checked bool, result MetadataFields) string {
+		if !checked {
+			return "-"
+		}
+		return boolToIcon(result.HasLogo) + boolToIcon(result.HasDescription) + boolToIcon(result.HasTwitter)
+	}
 
 	jupiterLogo := "-"
-	if token.ChainID == "solana" || token.ChainID == "solana:solana" {
+	if checkJupiter {
 		jupiterLogo = boolToIcon(jupiterResult.HasLogo)
 	}
 
-	fmt.Printf("[META] %s/%s | M:%s%s%s | C:%s%s%s | J:%s\n",
-		token.Symbol, chainName,
-		boolToIcon(mobulaResult.HasLogo), boolToIcon(mobulaResult.HasDescription), boolToIcon(mobulaResult.HasTwitter),
-		boolToIcon(codexResult.HasLogo), boolToIcon(codexResult.HasDescription), boolToIcon(codexResult.HasTwitter),
-		jupiterLogo)
+	fmt.Printf("[META] %s/%s | M:%s | C:%s | J:%s\n",
+		token.Symbol, chainName, icons(checkMobula, mobulaResult), icons(checkCodex, codexResult), jupiterLogo)
 
 	// Print stats every 50 checks (reduced from 10)
 	coverageStats.mu.Lock()
@@ -675,8 +855,69 @@ func checkTokenMetadata(token TokenToCheck, config *Config) {
 	}
 }
 
-// QueueTokenForMetadataCheck adds a token to the check queue
+// initMetadataQueue creates the token queue. It must run before Pulse starts queueing tokens.
+func initMetadataQueue(size int) {
+	tokenQueue = make(chan TokenToCheck, size)
+}
+
+// metadataProviders are the providers checked for every token, each with its own request limit
+var metadataProviders = []string{"mobula", "codex", "jupiter"}
+
+// metadataSlots bounds the concurrent requests to each provider, see METADATA_CONCURRENCY
+var metadataSlots map[string]chan struct{}
+
+func isMetadataProvider(provider string) bool {
+	for _, name := range metadataProviders {
+		if name == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// initMetadataLimits sizes each provider's request slots; providers without a limit get one.
+// It must run before the metadata coverage monitor starts.
+func initMetadataLimits(limits map[string]int) {
+	metadataSlots = make(map[string]chan struct{}, len(metadataProviders))
+	for _, provider := range metadataProviders {
+		metadataSlots[provider] = make(chan struct{}, max(limits[provider], 1))
+	}
+}
+
+// withMetadataSlot runs a provider's metadata check once one of its request slots is free,
+// so however many tokens are checked at once, no provider sees more than its limit
+func withMetadataSlot(provider string, check func() MetadataFields) MetadataFields {
+	slots := metadataSlots[provider]
+	slots <- struct{}{}
+	defer func() { <-slots }()
+	return check()
+}
+
+// metadataWorkerCount is how many tokens are checked at once: enough to fill the largest
+// provider limit, since each token asks every provider in turn
+func metadataWorkerCount(limits map[string]int) int {
+	workers := 1
+	for _, provider := range metadataProviders {
+		workers = max(workers, limits[provider])
+	}
+	return workers
+}
+
+// metadataIndexDelay is how long a new token gets to be indexed before its metadata is checked
+const metadataIndexDelay = 2 * time.Second
+
+// QueueTokenForMetadataCheck adds a token to the check queue.
+// It is a no-op, with a single warning, when the metadata coverage monitor is not running.
 func QueueTokenForMetadataCheck(token TokenToCheck) {
+	if !metadataConsumerRunning.Load() {
+		metadataNoConsumerOnce.Do(func() {
+			fmt.Println("[METADATA] Metadata coverage monitor not running, new tokens are not checked")
+		})
+		return
+	}
+
+	token.ChainID = canonicalChainID(token.ChainID)
+
 	select {
 	case tokenQueue <- token:
 		// Token queued successfully
@@ -687,7 +928,7 @@ func QueueTokenForMetadataCheck(token TokenToCheck) {
 }
 
 // runMetadataCoverageMonitor starts the metadata coverage monitoring
-func runMetadataCoverageMonitor(config *Config, stopChan <-chan struct{}) {
+func runMetadataCoverageMonitor(config *Config, rec Recorder, stopChan <-chan struct{}) {
 	fmt.Println("Starting Metadata Coverage Monitor...")
 	fmt.Println("   Comparing metadata coverage: Mobula vs Codex vs Jupiter")
 	fmt.Println("   Fields tracked: Logo, Name, Symbol, Description, Twitter, Website, Telegram")
@@ -695,25 +936,63 @@ func runMetadataCoverageMonitor(config *Config, stopChan <-chan struct{}) {
 	fmt.Println("   Waiting for new tokens from Pulse stream...")
 	fmt.Println()
 
+	// Tokens come from Mobula Pulse, which does not run without an API key
+	if config.MobulaAPIKey == "" {
+		rec.RecordProviderStatus("mobula", "metadata", skipReasonMissingMobulaAPIKey, config.MonitorRegion)
+		fmt.Println("MOBULA_API_KEY not set in .env file. Skipping Metadata Coverage monitor.")
+		return
+	}
+	rec.RecordProviderStatus("mobula", "metadata", "", config.MonitorRegion)
+
+	defer metadataConsumerRunning.Store(false)
+
 	// Stats printer ticker - print every 5 minutes
 	statsTicker := time.NewTicker(5 * time.Minute)
 	defer statsTicker.Stop()
 
+	// Workers check tokens side by side; withMetadataSlot keeps each provider under its limit
+	var workers sync.WaitGroup
+	for i := 0; i < metadataWorkerCount(config.MetadataConcurrency); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			runMetadataWorker(config, rec, stopChan)
+		}()
+	}
+
 	for {
 		select {
 		case <-stopChan:
-			fmt.Println("Metadata Coverage monitor stopped")
+			workers.Wait()
+
+			// Finish the tokens already discovered so the final stats include them
+			drained, dropped := drainQueue(tokenQueue, queueDrainTimeout, func(token TokenToCheck) {
+				time.Sleep(time.Until(token.DetectedAt.Add(metadataIndexDelay)))
+				checkTokenMetadata(token, config, rec)
+			})
+			fmt.Printf("Metadata Coverage monitor stopped (drained %d queued tokens, dropped %d)\n", drained, dropped)
 			printCoverageStats() // Print final stats
+			printCoverageVerdict()
 			return
 
-		case token := <-tokenQueue:
-			// Small delay to let the token get indexed
-			time.Sleep(2 * time.Second)
-			checkTokenMetadata(token, config)
-
 		case <-statsTicker.C:
 			printCoverageStats()
 		}
 	}
 }
 
+// runMetadataWorker checks queued tokens one at a time until stopChan closes
+func runMetadataWorker(config *Config, rec Recorder, stopChan <-chan struct{}) {
+	for {
+		select {
+		case <-stopChan:
+			return
+
+		case token := <-tokenQueue:
+			// Small delay to let the token get indexed, counting the time it spent queued
+			time.Sleep(time.Until(token.DetectedAt.Add(metadataIndexDelay)))
+			checkTokenMetadata(token, config, rec)
+		}
+	}
+}
+
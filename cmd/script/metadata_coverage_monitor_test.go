@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// A token Pulse reports on "solana:solana" reaches each provider in the form it expects,
+// and every metadata metric carries the canonical "solana" label
+func TestPulseSolanaTokenLabelsConsistently(t *testing.T) {
+	const address = "So11111111111111111111111111111111111111112"
+
+	var mu sync.Mutex
+	seen := make(map[string]string)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mobula/api/2/token/details", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen["mobula"] = r.URL.Query().Get("blockchain")
+		mu.Unlock()
+		fmt.Fprint(w, `{"data":{}}`)
+	})
+	mux.HandleFunc("/defined", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":{"createApiTokens":[{"token":%q}]}}`, testJWT(time.Now().Add(24*time.Hour)))
+	})
+	mux.HandleFunc("/codex", func(w http.ResponseWriter, r *http.Request) {
+		var body CodexGraphQLRequest
+		decoder := json.NewDecoder(r.Body)
+		decoder.UseNumber()
+		decoder.Decode(&body)
+		mu.Lock()
+		seen["codex"] = fmt.Sprint(body.Variables["networkId"])
+		mu.Unlock()
+		fmt.Fprint(w, `{"data":{"token":{}}}`)
+	})
+	mux.HandleFunc("/jupiter/tokens/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen["jupiter"] = strings.TrimPrefix(r.URL.Path, "/jupiter/tokens/")
+		mu.Unlock()
+		fmt.Fprint(w, `<html></html>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	withFreshTokenCache(t)
+	withProviderURL(t, &providerURLs.MobulaREST, server.URL+"/mobula")
+	withProviderURL(t, &providerURLs.DefinedAPI, server.URL+"/defined")
+	withProviderURL(t, &providerURLs.CodexGraphQL, server.URL+"/codex")
+	withProviderURL(t, &providerURLs.JupiterTokens, server.URL+"/jupiter/tokens")
+	savedSlots := metadataSlots
+	initMetadataLimits(nil)
+	t.Cleanup(func() { metadataSlots = savedSlots })
+
+	rec := &fakeRecorder{}
+	checkTokenMetadata(TokenToCheck{Address: address, ChainID: "solana:solana", Symbol: "SOL"}, &Config{MonitorRegion: "eu"}, rec)
+
+	wantSeen := map[string]string{"mobula": "solana", "codex": "1399811149", "jupiter": address}
+	for provider, want := range wantSeen {
+		if got := seen[provider]; got != want {
+			t.Errorf("%s was asked for %q, want %q", provider, got, want)
+		}
+	}
+
+	for _, method := range []string{"RecordMetadataCoverage", "RecordMetadataLatency", "RecordMetadataCheckResult"} {
+		providers := make(map[string]bool)
+		for _, call := range rec.calls(method) {
+			fields := strings.Fields(call)
+			providers[fields[0]] = true
+			if fields[1] != "solana" {
+				t.Errorf("%s(%s): chain label %q, want solana", method, call, fields[1])
+			}
+		}
+		for _, provider := range []string{"mobula", "codex", "jupiter"} {
+			if !providers[provider] {
+				t.Errorf("%s: no call for %s", method, provider)
+			}
+		}
+	}
+}
+
+// METADATA_CONCURRENCY is what bounds the requests in flight: the monitor checks enough tokens
+// at once to reach a provider's limit, and withMetadataSlot keeps it from going over
+func TestMetadataConcurrencyCapsInFlightRequests(t *testing.T) {
+	const tokens = 6
+
+	var mu sync.Mutex
+	inFlight, peak, served := 0, 0, 0
+	done := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mobula/api/2/token/details", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, `{"data":{}}`)
+
+		mu.Lock()
+		inFlight--
+		if served++; served == tokens {
+			close(done)
+		}
+		mu.Unlock()
+	})
+	mux.HandleFunc("/defined", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":{"createApiTokens":[{"token":%q}]}}`, testJWT(time.Now().Add(24*time.Hour)))
+	})
+	mux.HandleFunc("/codex", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"token":{}}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	withFreshTokenCache(t)
+	withProviderURL(t, &providerURLs.MobulaREST, server.URL+"/mobula")
+	withProviderURL(t, &providerURLs.DefinedAPI, server.URL+"/defined")
+	withProviderURL(t, &providerURLs.CodexGraphQL, server.URL+"/codex")
+	savedSlots, savedQueue := metadataSlots, tokenQueue
+	t.Cleanup(func() { metadataSlots, tokenQueue = savedSlots, savedQueue })
+
+	limits := map[string]int{"mobula": 2, "codex": 3}
+	initMetadataLimits(limits)
+	initMetadataQueue(tokens)
+	for i := 0; i < tokens; i++ {
+		// Detected long enough ago to be checked straight away
+		tokenQueue <- TokenToCheck{Address: fmt.Sprintf("0x%040d", i), ChainID: "evm:1", DetectedAt: time.Now().Add(-time.Minute)}
+	}
+
+	stopChan := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runMetadataCoverageMonitor(&Config{MobulaAPIKey: "key", MetadataConcurrency: limits, MonitorRegion: "eu"}, &fakeRecorder{}, stopChan)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		mu.Lock()
+		defer mu.Unlock()
+		t.Fatalf("only %d of %d tokens checked", served, tokens)
+	}
+	close(stopChan)
+	wg.Wait()
+
+	if peak != limits["mobula"] {
+		t.Errorf("peak mobula requests in flight = %d, want the limit of %d", peak, limits["mobula"])
+	}
+}
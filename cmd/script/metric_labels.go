@@ -0,0 +1,72 @@
+package main
+
+import "time"
+
+// ============================================================================
+// Optional Metric Labels
+// METRIC_LABELS: labels that multiply series counts on large deployments can
+// be turned off, exporting a constant in their place
+// ============================================================================
+
+// metricLabelCollapsed is exported in place of a disabled label's value
+const metricLabelCollapsed = "all"
+
+// optionalMetricLabels are the labels METRIC_LABELS may name
+var optionalMetricLabels = []string{"pool", "confirmation", "trade_type"}
+
+// enabledMetricLabels is set once at startup by initMetricLabels, before the monitors start
+var enabledMetricLabels map[string]bool
+
+func initMetricLabels(labels []string) {
+	enabledMetricLabels = make(map[string]bool, len(labels))
+	for _, label := range labels {
+		enabledMetricLabels[label] = true
+	}
+}
+
+func metricLabelEnabled(label string) bool {
+	return enabledMetricLabels[label]
+}
+
+// metricLabel returns value, or metricLabelCollapsed if label is disabled
+func metricLabel(label string, value string) string {
+	if !metricLabelEnabled(label) {
+		return metricLabelCollapsed
+	}
+	return value
+}
+
+// collapsePoolLiveness merges the pools of each provider, chain and region when the pool label
+// is disabled: the merged entry is active only if every pool is, and silent as long as the
+// quietest pool, so a dead pool still shows
+func collapsePoolLiveness(snapshot []PoolLiveness) []PoolLiveness {
+	if metricLabelEnabled("pool") {
+		return snapshot
+	}
+
+	type key struct{ provider, chain, region string }
+	merged := make(map[key]*PoolLiveness)
+	var order []key
+	for _, pool := range snapshot {
+		k := key{pool.Provider, pool.Chain, pool.Region}
+		m, ok := merged[k]
+		if !ok {
+			m = &PoolLiveness{Provider: pool.Provider, Chain: pool.Chain, Pool: metricLabelCollapsed, Region: pool.Region, Active: true}
+			merged[k] = m
+			order = append(order, k)
+		}
+		m.Active = m.Active && pool.Active
+		m.Silence = max(m.Silence, pool.Silence)
+	}
+
+	collapsed := make([]PoolLiveness, 0, len(order))
+	for _, k := range order {
+		collapsed = append(collapsed, *merged[k])
+	}
+	return collapsed
+}
+
+// exportedPoolLiveness is the pool liveness as exported to Prometheus and OTel
+func exportedPoolLiveness(now time.Time) []PoolLiveness {
+	return collapsePoolLiveness(poolLivenessSnapshot(now))
+}
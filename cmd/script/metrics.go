@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -17,16 +20,21 @@ var (
 	restAPILatency     *prometheus.HistogramVec
 	restAPIErrors      *prometheus.CounterVec
 	restAPIStatusCodes *prometheus.CounterVec
+	restDataFreshness  *prometheus.GaugeVec
+	restDataComplete   *prometheus.GaugeVec
+	clockAnomalies     *prometheus.CounterVec
 
 	// Quote API latency metrics
 	quoteAPILatency     *prometheus.HistogramVec
 	quoteAPIErrors      *prometheus.CounterVec
 	quoteAPIStatusCodes *prometheus.CounterVec
+	quotePriceImpact    *prometheus.GaugeVec
 
 	// Metadata coverage metrics
 	metadataCoverageTotal   *prometheus.CounterVec
 	metadataCoverageSuccess *prometheus.CounterVec
 	metadataAPILatency      *prometheus.HistogramVec
+	metadataCheckResults    *prometheus.CounterVec
 
 	// Head lag metrics
 	headLagBlocks      *prometheus.GaugeVec
@@ -34,15 +42,126 @@ var (
 	blockchainHead     *prometheus.GaugeVec
 	aggregatorHead     *prometheus.GaugeVec
 	headLagErrors      *prometheus.CounterVec
+	headLagEWMA        *prometheus.GaugeVec
+	headLagByTradeType *prometheus.HistogramVec
+	eventLag           *prometheus.HistogramVec
+	headLagDuration    *prometheus.HistogramVec
+	headLagSummary     *prometheus.SummaryVec
+	transportLag       *prometheus.GaugeVec
+	tradesObserved     *prometheus.CounterVec
+
+	// Clock skew metrics (on-chain time ahead of receipt time)
+	clockSkewEvents *prometheus.CounterVec
+	clockSkewMs     *prometheus.GaugeVec
+
+	// Provider-reported on-chain time against the node's block timestamp, see BlockTimestampCheck
+	providerTimestampSkew   *prometheus.GaugeVec
+	providerTimestampChecks *prometheus.CounterVec
+
+	// WebSocket throughput metrics
+	messagesReceived     *prometheus.CounterVec
+	websocketErrors      *prometheus.CounterVec
+	websocketWriteErrors *prometheus.CounterVec
+	backoffSeconds       *prometheus.CounterVec
+	subscriptionErrors   *prometheus.CounterVec
+	timeToFirstEvent     *prometheus.GaugeVec
+
+	// Per-pool liveness since the feed connected, see poolLivenessCollector
+	poolActive = prometheus.NewDesc(
+		"pool_active",
+		"1 if the subscribed pool streamed at least one trade since the feed connected, 0 otherwise",
+		[]string{"aggregator", "chain", "pool", "region"}, nil,
+	)
+	poolSecondsSinceLastMessage = prometheus.NewDesc(
+		"pool_seconds_since_last_message",
+		"Seconds since the subscribed pool's last trade, or since it was subscribed if it has not streamed one",
+		[]string{"aggregator", "chain", "pool", "region"}, nil,
+	)
+
+	// WebSocket head lag and REST freshness side by side, see surfaceAttributionCollector
+	surfaceLag = prometheus.NewDesc(
+		"surface_lag_seconds",
+		"Lag per provider, chain and access surface: websocket is the p50 head lag over STATS_WINDOW, rest the data freshness of the latest REST poll within it",
+		[]string{"aggregator", "chain", "surface", "region"}, nil,
+	)
+
+	// Samples behind each /stats series, see windowSamplesCollector
+	statsWindowSamples = prometheus.NewDesc(
+		"stats_window_samples",
+		"Head lag samples over STATS_WINDOW per provider and chain, outliers excluded",
+		[]string{"aggregator", "chain", "region"}, nil,
+	)
+	statsWindowSufficient = prometheus.NewDesc(
+		"stats_window_sufficient",
+		"1 if the provider has at least STATS_MIN_SAMPLES samples over STATS_WINDOW on the chain, 0 otherwise; gate percentile alerts on it",
+		[]string{"aggregator", "chain", "region"}, nil,
+	)
+
+	// Each provider against BASELINE_PROVIDER, see baselineDeltaCollector
+	baselineLagDelta = prometheus.NewDesc(
+		"baseline_lag_delta_milliseconds",
+		"Provider p50 or p95 head lag over STATS_WINDOW minus the baseline provider's on the same chain; positive is slower than the baseline",
+		[]string{"aggregator", "chain", "quantile", "baseline", "region"}, nil,
+	)
+
+	// Defined.fi JWT metrics
+	definedTokenGenerations prometheus.Counter
+	definedTokenCacheHits   prometheus.Counter
+	definedTokenRateLimited prometheus.Counter
+	definedTokenExpiry      prometheus.GaugeFunc
+	authFailures            *prometheus.CounterVec
+	sessionRefreshAttempts  *prometheus.CounterVec
+	definedJWTAge           = prometheus.NewDesc(
+		"defined_jwt_age_seconds",
+		"Seconds the cached Defined.fi JWT has been in use, by the source of its session cookie (env or scraped)",
+		[]string{"cookie_source"}, nil,
+	)
+
+	// Monitor status metrics
+	providerEnabled    *prometheus.GaugeVec
+	providerSkipReason *prometheus.GaugeVec
+
+	// Sink metrics
+	sinkDroppedTotal *prometheus.CounterVec
+
+	// Sliding-window summary metrics
+	latencyOutliersTotal     *prometheus.CounterVec
+	poolComparisonEligible   *prometheus.GaugeVec
+	comparisonExcludedTrades *prometheus.CounterVec
+	tradesBelowMinVolume     *prometheus.CounterVec
+
+	// Startup pool validation
+	poolResolved *prometheus.GaugeVec
+
+	// WebSocket frames we could not parse
+	parseFailuresTotal *prometheus.CounterVec
+
+	// Trades skipped because the provider sent no on-chain timestamp
+	missingTimestampTotal *prometheus.CounterVec
+
+	// Last time each running monitor received data, as used by /readyz
+	monitorLastSuccess *prometheus.GaugeVec
+
+	// CEX reference feed
+	cexReferenceLatency *prometheus.HistogramVec
+
+	// First-to-index race metrics
+	firstToIndexTotal    *prometheus.CounterVec
+	txPartiallySeenTotal *prometheus.CounterVec
+	providerRelativeLag  *prometheus.HistogramVec
+
+	// Launchpad discovery race metrics
+	launchpadFirstToDiscover *prometheus.CounterVec
+	launchpadDiscoveryDelta  *prometheus.HistogramVec
 )
 
 func init() {
 	poolDiscoveryLatency = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "pool_discovery_latency_milliseconds",
-			Help: "Time from pool creation on-chain to first trade detection (pool discovery latency)",
+			Help: "Time from pool creation on-chain to first trade detection (pool discovery latency), by launchpad (\"other\" outside LAUNCHPADS)",
 		},
-		[]string{"aggregator", "chain", "region"},
+		[]string{"aggregator", "chain", "launchpad", "region"},
 	)
 	prometheus.MustRegister(poolDiscoveryLatency)
 
@@ -86,6 +205,35 @@ func init() {
 	)
 	prometheus.MustRegister(restAPIStatusCodes)
 
+	// Data quality of REST endpoints that return time series (Codex getBars)
+	restDataFreshness = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rest_data_freshness_seconds",
+			Help: "Age of the newest data point returned by a REST endpoint, in seconds",
+		},
+		[]string{"aggregator", "endpoint", "chain", "region"},
+	)
+	prometheus.MustRegister(restDataFreshness)
+
+	restDataComplete = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "rest_data_completeness_ratio",
+			Help: "Data points returned by a REST endpoint over data points expected for the requested window, in [0, 1]",
+		},
+		[]string{"aggregator", "endpoint", "chain", "region"},
+	)
+	prometheus.MustRegister(restDataComplete)
+
+	// Freshness kept out of rest_data_freshness_seconds because the data was newer than our clock
+	clockAnomalies = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "clock_anomaly_total",
+			Help: "Total number of REST data points timestamped ahead of the local clock, left out of rest_data_freshness_seconds",
+		},
+		[]string{"aggregator", "endpoint", "chain", "region"},
+	)
+	prometheus.MustRegister(clockAnomalies)
+
 	// Quote API latency histogram
 	quoteAPILatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -117,6 +265,16 @@ func init() {
 	)
 	prometheus.MustRegister(quoteAPIStatusCodes)
 
+	// Routing quality under size, only with QUOTE_IMPACT_SIZES
+	quotePriceImpact = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "quote_price_impact_percent",
+			Help: "Latest price impact of a quote at size_usd, in percent of output per dollar lost against the smallest QUOTE_IMPACT_SIZES size",
+		},
+		[]string{"provider", "chain", "size_usd", "region"},
+	)
+	prometheus.MustRegister(quotePriceImpact)
+
 	// Metadata coverage - total checks per provider/chain/field
 	metadataCoverageTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -148,13 +306,22 @@ func init() {
 	)
 	prometheus.MustRegister(metadataAPILatency)
 
+	metadataCheckResults = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "metadata_check_result_total",
+			Help: "Total number of metadata checks by outcome: success, not_found, unsupported_chain, request_error or parse_error",
+		},
+		[]string{"provider", "chain", "result", "region"},
+	)
+	prometheus.MustRegister(metadataCheckResults)
+
 	// Head lag - milliseconds behind (raw value)
 	headLagBlocks = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "head_lag_milliseconds",
 			Help: "Indexation latency in milliseconds (time between on-chain event and WebSocket receipt)",
 		},
-		[]string{"aggregator", "chain", "region"},
+		[]string{"aggregator", "chain", "confirmation", "region"},
 	)
 	prometheus.MustRegister(headLagBlocks)
 
@@ -164,10 +331,98 @@ func init() {
 			Name: "head_lag_seconds",
 			Help: "Indexation latency in seconds (time between on-chain event and WebSocket receipt)",
 		},
-		[]string{"aggregator", "chain", "region"},
+		[]string{"aggregator", "chain", "confirmation", "region"},
 	)
 	prometheus.MustRegister(headLagSeconds)
 
+	// Head lag quantiles over every sample, unlike the gauges that only keep the last trade.
+	// Quantiles are computed in-process, so unlike histogram buckets they cannot be summed across regions.
+	headLagSummary = prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:       "head_lag_summary_milliseconds",
+			Help:       "Indexation latency in milliseconds, p50/p90/p99 over the last 5 minutes of trades",
+			Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+			MaxAge:     headLagSummaryMaxAge,
+			AgeBuckets: 5,
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(headLagSummary)
+
+	// Head lag by trade direction, opt-in through TRADE_TYPE_METRICS to keep cardinality down
+	headLagByTradeType = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "head_lag_by_trade_type_milliseconds",
+			Help:    "Indexation latency in milliseconds by trade direction (buy or sell), for providers that report it",
+			Buckets: []float64{100, 250, 500, 1000, 2000, 5000, 10000, 30000},
+		},
+		[]string{"aggregator", "chain", "trade_type", "region"},
+	)
+	prometheus.MustRegister(headLagByTradeType)
+
+	// Lag per on-chain event type, for the types listed in CODEX_EVENT_TYPES
+	eventLag = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "event_lag_milliseconds",
+			Help:    "Indexation latency in milliseconds by on-chain event type (Swap, Mint, Burn...), for providers that stream several",
+			Buckets: []float64{100, 250, 500, 1000, 2000, 5000, 10000, 30000},
+		},
+		[]string{"aggregator", "chain", "event_type", "region"},
+	)
+	prometheus.MustRegister(eventLag)
+
+	// Head lag at full precision, opt-in through PRECISE_LAG_METRICS: fine buckets from 10ms to ~20s
+	// tell apart providers a few tens of milliseconds apart, which the ms histograms blur
+	headLagDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "head_lag_duration_seconds",
+			Help:    "Indexation latency in seconds at sub-millisecond precision (time between on-chain event and WebSocket receipt)",
+			Buckets: prometheus.ExponentialBuckets(0.01, 1.5, 20),
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(headLagDuration)
+
+	// Head lag - exponentially weighted moving average, steadier than the raw gauges
+	headLagEWMA = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "head_lag_ewma_milliseconds",
+			Help: "Exponentially weighted moving average of the indexation latency in milliseconds (alpha set by EWMA_ALPHA)",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(headLagEWMA)
+
+	// Receipt time minus the provider's own processing time, for providers that send one
+	transportLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "transport_lag_milliseconds",
+			Help: "Time between the provider processing an event and WebSocket receipt, in milliseconds (network path only)",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(transportLag)
+
+	// Trades each feed delivered, to spot a silent provider and weigh comparisons by sample size
+	tradesObserved = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "trades_observed_total",
+			Help: "Total number of trades observed per aggregator and chain",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(tradesObserved)
+
+	// Stream warmup: from subscribing to the first trade on each chain, once per connection
+	timeToFirstEvent = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "time_to_first_event_milliseconds",
+			Help: "Time from subscribing on the feed's current connection to its first trade on the chain, in milliseconds",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(timeToFirstEvent)
+
 	// Blockchain head block number (source of truth)
 	blockchainHead = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -197,15 +452,333 @@ func init() {
 		[]string{"aggregator", "chain", "error_type", "region"},
 	)
 	prometheus.MustRegister(headLagErrors)
+
+	// Raw WebSocket messages per feed, trades or not
+	messagesReceived = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "messages_received_total",
+			Help: "Total number of WebSocket messages received per aggregator feed",
+		},
+		[]string{"aggregator", "region"},
+	)
+	prometheus.MustRegister(messagesReceived)
+
+	// WebSocket read errors, split into timeout, closed and protocol
+	websocketErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "websocket_errors_total",
+			Help: "Total number of WebSocket read errors per aggregator feed and error type",
+		},
+		[]string{"aggregator", "error_type", "region"},
+	)
+	prometheus.MustRegister(websocketErrors)
+
+	// WebSocket write failures, each closing the connection for a reconnect
+	websocketWriteErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "websocket_write_errors_total",
+			Help: "Total number of failed WebSocket writes per aggregator feed and operation (init, subscribe, ping, pong), each triggering a reconnect",
+		},
+		[]string{"aggregator", "op", "region"},
+	)
+	prometheus.MustRegister(websocketWriteErrors)
+
+	// A flapping feed records nothing while it waits to reconnect, so count that time
+	backoffSeconds = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "backoff_seconds_total",
+			Help: "Total seconds a WebSocket feed spent waiting in reconnect backoff",
+		},
+		[]string{"provider", "region"},
+	)
+	prometheus.MustRegister(backoffSeconds)
+
+	// Per-pool subscription failures, to find stale or decommissioned pool addresses
+	subscriptionErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "subscription_errors_total",
+			Help: "Total number of failed or rejected WebSocket subscriptions per pool",
+		},
+		[]string{"aggregator", "chain", "pool", "region"},
+	)
+	prometheus.MustRegister(subscriptionErrors)
+	prometheus.MustRegister(poolLivenessCollector{})
+
+	// Events timestamped after we received them, kept out of the latency metrics
+	clockSkewEvents = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "clock_skew_events_total",
+			Help: "Total number of events whose on-chain time was ahead of receipt time (negative lag)",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(clockSkewEvents)
+
+	clockSkewMs = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "clock_skew_milliseconds",
+			Help: "Signed lag of the latest event with negative lag, in milliseconds (always <= 0)",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(clockSkewMs)
+
+	providerTimestampSkew = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "provider_timestamp_skew_milliseconds",
+			Help: "Provider-reported on-chain time minus the node's block timestamp, for the latest sampled event",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(providerTimestampSkew)
+
+	providerTimestampChecks = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "provider_timestamp_checks_total",
+			Help: "Total number of sampled events checked against the node's block timestamp, by result (match, mismatch or rpc_error)",
+		},
+		[]string{"aggregator", "chain", "result", "region"},
+	)
+	prometheus.MustRegister(providerTimestampChecks)
+
+	// Defined.fi JWT generation, to see whether the cache works and how often token creation hits 429
+	definedTokenGenerations = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "defined_token_generations_total",
+			Help: "Total number of Defined.fi JWT tokens generated",
+		},
+	)
+	prometheus.MustRegister(definedTokenGenerations)
+
+	definedTokenCacheHits = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "defined_token_cache_hits_total",
+			Help: "Total number of Defined.fi JWT requests served from the cache",
+		},
+	)
+	prometheus.MustRegister(definedTokenCacheHits)
+
+	definedTokenRateLimited = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "defined_token_rate_limited_total",
+			Help: "Total number of Defined.fi JWT generations rejected with HTTP 429",
+		},
+	)
+	prometheus.MustRegister(definedTokenRateLimited)
+
+	// Evaluated on scrape so it counts down between refreshes
+	definedTokenExpiry = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "defined_token_expiry_seconds",
+			Help: "Seconds until the cached Defined.fi JWT expires (0 if none is cached)",
+		},
+		definedTokenSecondsUntilExpiry,
+	)
+	prometheus.MustRegister(definedTokenExpiry)
+	prometheus.MustRegister(definedJWTAgeCollector{})
+
+	// Rejected credentials, telling a degrading Defined.fi session apart from generic connection churn
+	authFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_failures_total",
+			Help: "Total number of requests or connections rejected for authentication, each invalidating the cached JWT",
+		},
+		[]string{"provider", "monitor"},
+	)
+	prometheus.MustRegister(authFailures)
+
+	sessionRefreshAttempts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "session_refresh_attempts_total",
+			Help: "Total number of Defined.fi session refreshes after an authentication failure (JWT regenerated from the session cookie, or the cookie re-scraped), by result (success or error)",
+		},
+		[]string{"result"},
+	)
+	prometheus.MustRegister(sessionRefreshAttempts)
+
+	// Whether each monitor runs, so a provider silently disabled by a missing key can be alerted on
+	providerEnabled = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "provider_enabled",
+			Help: "1 if the provider's monitor is running, 0 if it was skipped",
+		},
+		[]string{"provider", "monitor", "region"},
+	)
+	prometheus.MustRegister(providerEnabled)
+
+	providerSkipReason = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "provider_skip_reason",
+			Help: "Info metric set to 1 with the reason a provider's monitor was skipped",
+		},
+		[]string{"provider", "monitor", "reason", "region"},
+	)
+	prometheus.MustRegister(providerSkipReason)
+
+	// Measurements dropped by a sink because its buffer was full
+	sinkDroppedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "sink_dropped_total",
+			Help: "Total number of measurements dropped by a sink because its buffer was full",
+		},
+		[]string{"sink"},
+	)
+	prometheus.MustRegister(sinkDroppedTotal)
+
+	// Samples flagged as outliers and left out of the printed summaries
+	latencyOutliersTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "latency_outliers_total",
+			Help: "Total number of head lag samples flagged as outliers by the sliding-window aggregator",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(latencyOutliersTotal)
+
+	// Pools every provider on their chain subscribes to, the only ones the summaries compare
+	poolComparisonEligible = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pool_comparison_eligible",
+			Help: "1 if every provider covering the pool's chain subscribes to the pool, 0 if it is left out of cross-provider stats",
+		},
+		[]string{"chain", "pool", "region"},
+	)
+	prometheus.MustRegister(poolComparisonEligible)
+
+	comparisonExcludedTrades = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "comparison_excluded_trades_total",
+			Help: "Total number of trades left out of the sliding-window summary because their pool is not comparable across providers",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(comparisonExcludedTrades)
+
+	// Dust trades left out of the head lag metrics, see MIN_TRADE_VOLUME_USD
+	tradesBelowMinVolume = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "trades_below_min_volume_total",
+			Help: "Total number of trades below MIN_TRADE_VOLUME_USD, left out of the head lag metrics",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(tradesBelowMinVolume)
+
+	poolResolved = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pool_resolved",
+			Help: "1 if the provider knew the pool when validated at startup, 0 if the address did not resolve",
+		},
+		[]string{"aggregator", "chain", "pool", "region"},
+	)
+	prometheus.MustRegister(poolResolved)
+
+	// A provider changing its message shape shows up here instead of as a quiet feed
+	parseFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "parse_failures_total",
+			Help: "Total number of WebSocket frames dropped because they could not be parsed",
+		},
+		[]string{"provider", "region"},
+	)
+	prometheus.MustRegister(parseFailuresTotal)
+
+	// A provider that stops filling in timestamps shows up here instead of as a feed without trades
+	missingTimestampTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "missing_timestamp_total",
+			Help: "Total number of trades and new tokens skipped because their on-chain timestamp was missing or zero",
+		},
+		[]string{"provider", "region"},
+	)
+	prometheus.MustRegister(missingTimestampTotal)
+
+	monitorLastSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "monitor_last_success_timestamp_seconds",
+			Help: "Unix time a running monitor last received data: a WebSocket message or a successful REST, quote or metadata call",
+		},
+		[]string{"provider", "monitor", "region"},
+	)
+	prometheus.MustRegister(monitorLastSuccess)
+
+	// Kept apart from head lag: a CEX trade has no indexation step, so this is a baseline, not a competitor
+	cexReferenceLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "cex_reference_latency_milliseconds",
+			Help:    "Reference only: time between a CEX trade and its WebSocket receipt, not comparable to DEX indexation lag",
+			Buckets: []float64{5, 10, 25, 50, 100, 200, 500, 1000, 2000},
+		},
+		[]string{"exchange", "symbol", "region"},
+	)
+	prometheus.MustRegister(cexReferenceLatency)
+
+	// Transactions streamed by several head lag feeds, credited to the first one
+	firstToIndexTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "first_to_index_total",
+			Help: "Total number of transactions this aggregator streamed before every other provider that reported them",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(firstToIndexTotal)
+
+	txPartiallySeenTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "tx_partially_seen_total",
+			Help: "Total number of transactions only some of the providers subscribed to the pool streamed within FIRST_TO_INDEX_WINDOW, by the providers that did",
+		},
+		[]string{"seen_by", "chain", "region"},
+	)
+	prometheus.MustRegister(txPartiallySeenTotal)
+
+	// Symmetric buckets: negative is ahead of the median provider, positive behind it
+	providerRelativeLag = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "provider_relative_lag_milliseconds",
+			Help:    "For transactions streamed by several providers, this provider's lag minus the median provider lag, in milliseconds (negative is faster than the median)",
+			Buckets: []float64{-5000, -2000, -1000, -500, -250, -100, -50, 0, 50, 100, 250, 500, 1000, 2000, 5000},
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(providerRelativeLag)
+
+	// Which provider surfaces a new launchpad token first, and how far behind the others are
+	launchpadFirstToDiscover = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "launchpad_first_to_discover_total",
+			Help: "Total number of launchpad tokens reported by more than one provider where this provider was first",
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(launchpadFirstToDiscover)
+
+	launchpadDiscoveryDelta = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "launchpad_discovery_delta_milliseconds",
+			Help:    "How long after the first provider this provider reported a launchpad token, in milliseconds",
+			Buckets: []float64{50, 100, 250, 500, 1000, 2000, 5000, 10000, 30000, 60000},
+		},
+		[]string{"aggregator", "chain", "region"},
+	)
+	prometheus.MustRegister(launchpadDiscoveryDelta)
 }
 
-func RecordPoolDiscoveryLatency(aggregator string, chain string, latencyMs float64, region string) {
-	// Filter out invalid values: negative or > 2 minutes (120000ms)
-	if latencyMs < 0 || latencyMs > 120000 {
+// RecordPoolDiscoveryLatency records how long after its creation a new pool was discovered.
+// launchpad must come from launchpadLabel, which bounds it to the LAUNCHPADS allowlist.
+func RecordPoolDiscoveryLatency(aggregator string, chain string, launchpad string, latencyMs float64, region string) {
+	// Negative lag is clock skew, not latency
+	if latencyMs < 0 {
+		RecordClockSkew(aggregator, chain, latencyMs, region)
 		return
 	}
 
-	poolDiscoveryLatency.WithLabelValues(aggregator, chain, region).Set(latencyMs)
+	// Filter out invalid values: > 2 minutes (120000ms)
+	if latencyMs > 120000 {
+		return
+	}
+
+	poolDiscoveryLatency.WithLabelValues(aggregator, chain, launchpad, region).Set(latencyMs)
 }
 
 // RecordPoolDiscoveryError records an error when fetching pool discovery data
@@ -227,6 +800,18 @@ func RecordRESTError(aggregator string, endpoint string, chain string, errorType
 	restAPIErrors.WithLabelValues(aggregator, endpoint, chain, errorType, region).Inc()
 }
 
+// RecordRESTDataQuality records how current (age of the newest bar) and complete (bars returned
+// over bars expected) a REST endpoint's data is
+func RecordRESTDataQuality(aggregator string, endpoint string, chain string, freshnessSeconds float64, completeness float64, region string) {
+	restDataFreshness.WithLabelValues(aggregator, endpoint, chain, region).Set(freshnessSeconds)
+	restDataComplete.WithLabelValues(aggregator, endpoint, chain, region).Set(completeness)
+}
+
+// RecordClockAnomaly records REST data timestamped ahead of the local clock
+func RecordClockAnomaly(aggregator string, endpoint string, chain string, region string) {
+	clockAnomalies.WithLabelValues(aggregator, endpoint, chain, region).Inc()
+}
+
 // RecordQuoteAPILatency records the latency of a Quote API call
 func RecordQuoteAPILatency(provider string, chain string, latencyMs float64, statusCode int, region string) {
 	// Record latency in histogram
@@ -241,6 +826,11 @@ func RecordQuoteAPIError(provider string, chain string, errorType string, region
 	quoteAPIErrors.WithLabelValues(provider, chain, errorType, region).Inc()
 }
 
+// RecordQuotePriceImpact records a provider's price impact at one notional size
+func RecordQuotePriceImpact(provider string, chain string, sizeUSD string, impactPercent float64, region string) {
+	quotePriceImpact.WithLabelValues(provider, chain, sizeUSD, region).Set(impactPercent)
+}
+
 // RecordMetadataCoverage records metadata coverage for a specific field
 func RecordMetadataCoverage(provider string, chain string, field string, present bool, region string) {
 	metadataCoverageTotal.WithLabelValues(provider, chain, field, region).Inc()
@@ -254,10 +844,65 @@ func RecordMetadataLatency(provider string, chain string, latencyMs float64, reg
 	metadataAPILatency.WithLabelValues(provider, chain, region).Observe(latencyMs)
 }
 
-// RecordHeadLag records the head lag for an aggregator on a specific chain
-func RecordHeadLag(aggregator string, chain string, lagBlocks int64, lagSeconds float64, region string) {
-	headLagBlocks.WithLabelValues(aggregator, chain, region).Set(float64(lagBlocks))
-	headLagSeconds.WithLabelValues(aggregator, chain, region).Set(lagSeconds)
+// RecordMetadataCheckResult records the outcome of a metadata check
+func RecordMetadataCheckResult(provider string, chain string, result string, region string) {
+	metadataCheckResults.WithLabelValues(provider, chain, result, region).Inc()
+}
+
+// RecordHeadLag records the head lag for an aggregator on a specific chain.
+// confirmation is empty for providers that only stream one kind of event.
+func RecordHeadLag(aggregator string, chain string, confirmation string, lagBlocks int64, lagSeconds float64, region string) {
+	confirmation = metricLabel("confirmation", confirmation)
+	headLagBlocks.WithLabelValues(aggregator, chain, confirmation, region).Set(float64(lagBlocks))
+	headLagSeconds.WithLabelValues(aggregator, chain, confirmation, region).Set(lagSeconds)
+}
+
+// headLagSummaryMaxAge is the sliding window head_lag_summary_milliseconds quantiles cover
+const headLagSummaryMaxAge = 5 * time.Minute
+
+// RecordHeadLagSample adds a trade's head lag to the quantile summary
+func RecordHeadLagSample(aggregator string, chain string, lagMs float64, region string) {
+	headLagSummary.WithLabelValues(aggregator, chain, region).Observe(lagMs)
+}
+
+// RecordHeadLagDuration records the head lag in seconds, without truncation to milliseconds
+func RecordHeadLagDuration(aggregator string, chain string, lagSeconds float64, region string) {
+	headLagDuration.WithLabelValues(aggregator, chain, region).Observe(lagSeconds)
+}
+
+// RecordHeadLagByTradeType records the head lag of a buy or a sell
+func RecordHeadLagByTradeType(aggregator string, chain string, tradeType string, lagMs float64, region string) {
+	headLagByTradeType.WithLabelValues(aggregator, chain, metricLabel("trade_type", tradeType), region).Observe(lagMs)
+}
+
+// RecordEventLag records the lag of an on-chain event of any recorded type
+func RecordEventLag(aggregator string, chain string, eventType string, lagMs float64, region string) {
+	eventLag.WithLabelValues(aggregator, chain, eventType, region).Observe(lagMs)
+}
+
+// RecordHeadLagEWMA records the smoothed head lag for an aggregator on a specific chain
+func RecordHeadLagEWMA(aggregator string, chain string, lagMs float64, region string) {
+	headLagEWMA.WithLabelValues(aggregator, chain, region).Set(lagMs)
+}
+
+// RecordTransportLag records the network part of an aggregator's head lag on a specific chain
+func RecordTransportLag(aggregator string, chain string, lagMs float64, region string) {
+	transportLag.WithLabelValues(aggregator, chain, region).Set(lagMs)
+}
+
+// RecordTimeToFirstEvent records how long a feed's new connection took to stream its first trade on a chain
+func RecordTimeToFirstEvent(aggregator string, chain string, waitMs float64, region string) {
+	timeToFirstEvent.WithLabelValues(aggregator, chain, region).Set(waitMs)
+}
+
+// ClearTimeToFirstEvent drops the previous connection's value until the new one streams on the chain
+func ClearTimeToFirstEvent(aggregator string, chain string, region string) {
+	timeToFirstEvent.DeleteLabelValues(aggregator, chain, region)
+}
+
+// RecordTradeObserved counts a trade delivered by an aggregator's feed
+func RecordTradeObserved(aggregator string, chain string, region string) {
+	tradesObserved.WithLabelValues(aggregator, chain, region).Inc()
 }
 
 // RecordBlockchainHead records the current blockchain head block number
@@ -280,7 +925,216 @@ func RecordCodexBlockNumber(chain string, blockNumber int64, region string) {
 	aggregatorHead.WithLabelValues("codex", chain, region).Set(float64(blockNumber))
 }
 
-func StartMetricsServer(addr string) error {
+// RecordMessageReceived counts a WebSocket message read from an aggregator's feed
+func RecordMessageReceived(aggregator string, region string) {
+	messagesReceived.WithLabelValues(aggregator, region).Inc()
+}
+
+// RecordWebSocketError records a WebSocket read error
+func RecordWebSocketError(aggregator string, errorType string, region string) {
+	websocketErrors.WithLabelValues(aggregator, errorType, region).Inc()
+}
+
+// RecordWebSocketWriteError records a failed WebSocket write
+func RecordWebSocketWriteError(aggregator string, op string, region string) {
+	websocketWriteErrors.WithLabelValues(aggregator, op, region).Inc()
+}
+
+// RecordBackoff records time a feed spent in reconnect backoff
+func RecordBackoff(provider string, seconds float64, region string) {
+	backoffSeconds.WithLabelValues(provider, region).Add(seconds)
+}
+
+// RecordSubscriptionError records a failed or rejected subscription to a pool's feed
+func RecordSubscriptionError(aggregator string, chain string, pool string, region string) {
+	subscriptionErrors.WithLabelValues(aggregator, chain, metricLabel("pool", pool), region).Inc()
+}
+
+// RecordClockSkew records an event whose on-chain time was ahead of receipt time
+func RecordClockSkew(aggregator string, chain string, lagMs float64, region string) {
+	clockSkewEvents.WithLabelValues(aggregator, chain, region).Inc()
+	clockSkewMs.WithLabelValues(aggregator, chain, region).Set(lagMs)
+}
+
+// definedJWTAgeCollector exports defined_jwt_age_seconds, evaluated on scrape like
+// defined_token_expiry_seconds. Its label changes with the cookie, hence no GaugeFunc.
+type definedJWTAgeCollector struct{}
+
+func (definedJWTAgeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- definedJWTAge
+}
+
+func (definedJWTAgeCollector) Collect(ch chan<- prometheus.Metric) {
+	age, cookieSource, ok := definedTokenAge()
+	if !ok {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(definedJWTAge, prometheus.GaugeValue, age.Seconds(), cookieSource)
+}
+
+// RecordDefinedTokenGeneration records a newly generated Defined.fi JWT
+func RecordDefinedTokenGeneration() {
+	definedTokenGenerations.Inc()
+}
+
+// RecordDefinedTokenCacheHit records a Defined.fi JWT served from the cache
+func RecordDefinedTokenCacheHit() {
+	definedTokenCacheHits.Inc()
+}
+
+// RecordDefinedTokenRateLimited records a Defined.fi JWT generation rejected with 429
+func RecordDefinedTokenRateLimited() {
+	definedTokenRateLimited.Inc()
+}
+
+// RecordAuthFailure records a request or connection of a provider's monitor rejected for authentication
+func RecordAuthFailure(provider string, monitor string) {
+	authFailures.WithLabelValues(provider, monitor).Inc()
+}
+
+// RecordSessionRefreshAttempt records a Defined.fi session refresh after an auth failure and whether it succeeded
+func RecordSessionRefreshAttempt(success bool) {
+	result := "success"
+	if !success {
+		result = "error"
+	}
+	sessionRefreshAttempts.WithLabelValues(result).Inc()
+}
+
+// RecordProviderStatus records whether a provider's monitor runs; skipReason is empty when it does
+func RecordProviderStatus(provider string, monitor string, skipReason string, region string) {
+	if skipReason == "" {
+		providerEnabled.WithLabelValues(provider, monitor, region).Set(1)
+		return
+	}
+	providerEnabled.WithLabelValues(provider, monitor, region).Set(0)
+	providerSkipReason.WithLabelValues(provider, monitor, skipReason, region).Set(1)
+}
+
+// RecordProviderTimestampSkew records how far a sampled event's reported on-chain time is from its block's
+// timestamp, and whether that is beyond the tolerance
+func RecordProviderTimestampSkew(aggregator string, chain string, skewMs float64, mismatch bool, region string) {
+	providerTimestampSkew.WithLabelValues(aggregator, chain, region).Set(skewMs)
+	result := "match"
+	if mismatch {
+		result = "mismatch"
+	}
+	providerTimestampChecks.WithLabelValues(aggregator, chain, result, region).Inc()
+}
+
+// RecordProviderTimestampCheckError records a sampled event whose block could not be fetched
+func RecordProviderTimestampCheckError(aggregator string, chain string, region string) {
+	providerTimestampChecks.WithLabelValues(aggregator, chain, "rpc_error", region).Inc()
+}
+
+// RecordSinkDropped records a measurement dropped by a sink
+func RecordSinkDropped(sink string) {
+	sinkDroppedTotal.WithLabelValues(sink).Inc()
+}
+
+// RecordLatencyOutlier records a head lag sample flagged as an outlier
+func RecordLatencyOutlier(aggregator string, chain string, region string) {
+	latencyOutliersTotal.WithLabelValues(aggregator, chain, region).Inc()
+}
+
+// RecordPoolComparisonEligible records whether a pool is compared across providers.
+// Like pool_resolved, it describes a single pool and is not exported without the pool label.
+func RecordPoolComparisonEligible(chain string, pool string, eligible bool, region string) {
+	if !metricLabelEnabled("pool") {
+		return
+	}
+	value := 0.0
+	if eligible {
+		value = 1
+	}
+	poolComparisonEligible.WithLabelValues(chain, pool, region).Set(value)
+}
+
+// RecordPoolResolved records whether a provider resolved a monitored pool address
+func RecordPoolResolved(aggregator string, chain string, pool string, resolved bool, region string) {
+	if !metricLabelEnabled("pool") {
+		return
+	}
+	value := 0.0
+	if resolved {
+		value = 1
+	}
+	poolResolved.WithLabelValues(aggregator, chain, pool, region).Set(value)
+}
+
+// RecordParseFailure records a WebSocket frame that could not be parsed
+func RecordParseFailure(provider string, region string) {
+	parseFailuresTotal.WithLabelValues(provider, region).Inc()
+}
+
+// RecordMissingTimestamp records a trade skipped for lack of an on-chain timestamp
+func RecordMissingTimestamp(provider string, region string) {
+	missingTimestampTotal.WithLabelValues(provider, region).Inc()
+}
+
+// RecordMonitorSuccess records the time a running monitor last received data
+func RecordMonitorSuccess(provider string, monitor string, at time.Time, region string) {
+	monitorLastSuccess.WithLabelValues(provider, monitor, region).Set(float64(at.UnixNano()) / 1e9)
+}
+
+// RecordCEXReferenceLatency records the trade-to-receipt latency of a CEX reference trade
+func RecordCEXReferenceLatency(exchange string, symbol string, latencyMs float64, region string) {
+	cexReferenceLatency.WithLabelValues(exchange, symbol, region).Observe(latencyMs)
+}
+
+// RecordFirstToIndex records a transaction this aggregator streamed before the other providers
+func RecordFirstToIndex(aggregator string, chain string, region string) {
+	firstToIndexTotal.WithLabelValues(aggregator, chain, region).Inc()
+}
+
+// RecordTxPartiallySeen records a transaction only the providers in seenBy ("codex+mobula") streamed
+func RecordTxPartiallySeen(seenBy string, chain string, region string) {
+	txPartiallySeenTotal.WithLabelValues(seenBy, chain, region).Inc()
+}
+
+// RecordProviderRelativeLag records a provider's lag on a transaction relative to the median provider lag
+func RecordProviderRelativeLag(aggregator string, chain string, relativeLagMs float64, region string) {
+	providerRelativeLag.WithLabelValues(aggregator, chain, region).Observe(relativeLagMs)
+}
+
+// RecordComparisonExcluded records a trade left out of the summary because its pool is not comparable
+func RecordComparisonExcluded(aggregator string, chain string, region string) {
+	comparisonExcludedTrades.WithLabelValues(aggregator, chain, region).Inc()
+}
+
+// RecordTradeBelowMinVolume records a trade left out of the head lag metrics for its low USD volume
+func RecordTradeBelowMinVolume(aggregator string, chain string, region string) {
+	tradesBelowMinVolume.WithLabelValues(aggregator, chain, region).Inc()
+}
+
+// RecordLaunchpadFirstToDiscover records a launchpad token this aggregator reported before every other provider
+func RecordLaunchpadFirstToDiscover(aggregator string, chain string, region string) {
+	launchpadFirstToDiscover.WithLabelValues(aggregator, chain, region).Inc()
+}
+
+// RecordLaunchpadDiscoveryDelta records how long after the first provider this aggregator reported a launchpad token
+func RecordLaunchpadDiscoveryDelta(aggregator string, chain string, deltaMs float64, region string) {
+	launchpadDiscoveryDelta.WithLabelValues(aggregator, chain, region).Observe(deltaMs)
+}
+
+// StartMetricsServer serves /metrics and /healthz on addr until stopChan closes, along with
+// the handlers main registered on the default mux (/stats, /readyz)
+func StartMetricsServer(addr string, stopChan <-chan struct{}) error {
 	http.Handle("/metrics", promhttp.Handler())
-	return http.ListenAndServe(addr, nil)
+	http.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+
+	server := &http.Server{Addr: addr}
+	go func() {
+		<-stopChan
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
@@ -1,18 +1,16 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/gorilla/websocket"
 )
 
-const (
-	mobulaPulseWSURL = "wss://pulse-v2-api.mobula.io"
-)
-
 // Chains to monitor for new pools - focus on chains with active launchpads
 var pulseChains = []string{
 	"solana:solana", // Solana (Pump.fun, Meteora, BAGS)
@@ -68,21 +66,21 @@ type PulseV2Token struct {
 	CreatedAt string `json:"createdAt"` // ISO 8601 timestamp
 }
 
-func connectMobulaPulseWebSocket(apiKey string) (*websocket.Conn, error) {
+func connectMobulaPulseWebSocket(ctx context.Context, apiKey string) (*wsConn, error) {
 	// Add API key to request headers
 	headers := make(map[string][]string)
 	headers["Authorization"] = []string{apiKey}
 
 	dialer := websocket.Dialer{}
-	conn, _, err := dialer.Dial(mobulaPulseWSURL, headers)
+	conn, _, err := dialer.DialContext(ctx, providerURLs.MobulaPulseWS, headers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Pulse WebSocket: %w", err)
 	}
 
-	return conn, nil
+	return newWSConn(conn), nil
 }
 
-func subscribeToPulse(conn *websocket.Conn, apiKey string) error {
+func subscribeToPulse(conn *wsConn, apiKey string) error {
 	subscribeMsg := PulseSubscribeMessage{
 		Type:          "pulse-v2",
 		Authorization: apiKey,
@@ -102,66 +100,104 @@ func subscribeToPulse(conn *websocket.Conn, apiKey string) error {
 		},
 	}
 
-	if err := conn.WriteJSON(subscribeMsg); err != nil {
+	if err := writeWebSocket(conn, wsWriteSubscribe, subscribeMsg); err != nil {
 		return fmt.Errorf("failed to subscribe to Pulse: %w", err)
 	}
 
 	return nil
 }
 
-// Launchpad sources to filter - must match Codex launchpads for fair comparison
-// Source names from Mobula Pulse V2 API
-var launchpadSources = map[string]bool{
-	"pumpfun":      true, // Pump.fun (Solana)
-	"pump.fun":     true,
-	"meteora":      true, // Meteora (Solana)
-	"meteora-dbc":  true, // Meteora DBC (Solana)
-	"meteoradbc":   true,
-	"fourmeme":     true, // Four.meme (BNB)
-	"four.meme":    true,
-	"flap":         true, // Flap (BNB) - similar to Four.meme
-	"zora":         true, // Zora (Base)
-	"baseapp":      true, // Baseapp (Base)
-	"bags":         true, // BAGS (Solana)
-	"moonshot":     true, // Moonshot
-	"raydium_cpmm": true, // Raydium launchpad pools
+// Launchpad sources to filter - must match Codex launchpads for fair comparison.
+// Source names from Mobula Pulse V2 API; LAUNCHPADS overrides the list.
+var defaultLaunchpads = []string{
+	"pumpfun",      // Pump.fun (Solana)
+	"meteora",      // Meteora (Solana)
+	"meteora-dbc",  // Meteora DBC (Solana)
+	"fourmeme",     // Four.meme (BNB)
+	"flap",         // Flap (BNB) - similar to Four.meme
+	"zora",         // Zora (Base)
+	"baseapp",      // Baseapp (Base)
+	"bags",         // BAGS (Solana)
+	"moonshot",     // Moonshot
+	"raydium_cpmm", // Raydium launchpad pools
+}
+
+// launchpadAliases maps other spellings of a source to its name in the launchpad list
+var launchpadAliases = map[string]string{
+	"pump.fun":   "pumpfun",
+	"meteoradbc": "meteora-dbc",
+	"four.meme":  "fourmeme",
+}
+
+// launchpadOther labels pools from sources outside the launchpad list
+const launchpadOther = "other"
+
+// launchpads is the launchpad allowlist, set once at startup by initLaunchpads
+var launchpads map[string]bool
+
+func initLaunchpads(names []string) {
+	launchpads = make(map[string]bool, len(names))
+	for _, name := range names {
+		launchpads[name] = true
+	}
+}
+
+// canonicalLaunchpad resolves a source's aliases so each launchpad has one name
+func canonicalLaunchpad(source string) string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	if canonical, ok := launchpadAliases[source]; ok {
+		return canonical
+	}
+	return source
 }
 
 func isLaunchpadSource(source string) bool {
-	return launchpadSources[source]
+	return launchpads[canonicalLaunchpad(source)]
+}
+
+// launchpadLabel is the launchpad metric label of a source: its name if allowlisted, launchpadOther
+// otherwise, so the label never grows past LAUNCHPADS
+func launchpadLabel(source string) string {
+	if !isLaunchpadSource(source) {
+		return launchpadOther
+	}
+	return canonicalLaunchpad(source)
 }
 
+// getChainNameForPulse returns the metric label for a Pulse chain ID
 func getChainNameForPulse(chainID string) string {
-	switch chainID {
-	case "solana:solana":
-		return "solana"
-	case "evm:56":
-		return "bnb"
-	case "evm:8453":
-		return "base"
-	case "evm:143":
-		return "monad"
-	default:
-		return chainID
+	if chain, ok := lookupChainID(chainID); ok {
+		return chain.ChainName
 	}
+	return chainID
 }
 
-func handlePulseV2Messages(conn *websocket.Conn, config *Config) {
-	messageCount := 0
+func handlePulseV2Messages(ctx context.Context, conn *wsConn, config *Config, rec Recorder) {
+	// Unblock the read below on shutdown instead of waiting for the next message
+	defer context.AfterFunc(ctx, func() { conn.Close() })()
+
+	readDeadline := config.ReadDeadlines["mobula-pulse"]
 	for {
+		setReadDeadline(conn.Conn, readDeadline)
 		_, messageBytes, err := conn.ReadMessage()
 		if err != nil {
-			log.Printf("[MOBULA-PULSE] WebSocket read error: %v", err)
+			if ctx.Err() != nil {
+				return
+			}
+			errorType := classifyWebSocketError(err)
+			rec.RecordWebSocketError("mobula-pulse", errorType, config.MonitorRegion)
+			log.Printf("[MOBULA-PULSE] WebSocket read error (%s): %v", errorType, err)
 			return
 		}
 
 		receiveTime := time.Now().UTC()
-		messageCount++
+		rec.RecordMessageReceived("mobula-pulse", config.MonitorRegion)
+		rec.RecordMonitorSuccess("mobula", "pulse", config.MonitorRegion)
 
 		// Try to parse as generic message first to get the type
 		var genericMsg map[string]interface{}
 		if err := json.Unmarshal(messageBytes, &genericMsg); err != nil {
-			fmt.Printf("[MOBULA-PULSE DEBUG] Failed to parse message: %s\n", string(messageBytes[:100]))
+			recordParseFailure(rec, "mobula-pulse", messageBytes, err, config.MonitorRegion)
 			continue
 		}
 
@@ -175,7 +211,7 @@ func handlePulseV2Messages(conn *websocket.Conn, config *Config) {
 		case "new-token":
 			var tokenMsg PulseV2NewTokenMessage
 			if err := json.Unmarshal(messageBytes, &tokenMsg); err != nil {
-				log.Printf("[MOBULA-PULSE] Failed to parse new-token message: %v", err)
+				recordParseFailure(rec, "mobula-pulse", messageBytes, err, config.MonitorRegion)
 				continue
 			}
 
@@ -189,21 +225,17 @@ func handlePulseV2Messages(conn *websocket.Conn, config *Config) {
 				source = token.Source
 			}
 
-			// Filter: only process launchpad sources for fair comparison with Codex
-			if !isLaunchpadSource(source) {
-				// Skip non-launchpad tokens (DEX pools like Uniswap, Raydium, etc.)
+			// Parse the created_at timestamp (ISO 8601 format)
+			if token.CreatedAt == "" {
+				rec.RecordMissingTimestamp("mobula-pulse", config.MonitorRegion)
 				continue
 			}
-
-			// Parse the created_at timestamp (ISO 8601 format)
-			var createdAt time.Time
-			var err error
-
-			if token.CreatedAt != "" {
-				createdAt, err = time.Parse(time.RFC3339, token.CreatedAt)
+			createdAt, err := time.Parse(time.RFC3339, token.CreatedAt)
+			if err != nil {
+				continue
 			}
-
-			if err != nil || createdAt.IsZero() {
+			if createdAt.IsZero() {
+				rec.RecordMissingTimestamp("mobula-pulse", config.MonitorRegion)
 				continue
 			}
 
@@ -212,9 +244,11 @@ func handlePulseV2Messages(conn *websocket.Conn, config *Config) {
 
 			// Determine chain name from chainId
 			chainName := getChainNameForPulse(token.ChainID)
-			if chainName == token.ChainID {
-				// If not found in our mapping, use it as-is
-				chainName = token.ChainID
+
+			// Filter: only process launchpad sources for fair comparison with Codex
+			if !isLaunchpadSource(source) {
+				// Skip non-launchpad tokens (DEX pools like Uniswap, Raydium, etc.)
+				continue
 			}
 
 			timestamp := receiveTime.Format("2006-01-02 15:04:05")
@@ -227,8 +261,11 @@ func handlePulseV2Messages(conn *websocket.Conn, config *Config) {
 			fmt.Printf("   Discovery lag: %dms\n", discoveryLagMs)
 			fmt.Printf("   Launchpad: %s\n\n", source)
 
-			// Record pool discovery latency metric
-			RecordPoolDiscoveryLatency("mobula-pulse", chainName, float64(discoveryLagMs), config.MonitorRegion)
+			// Record pool discovery latency metric, and the same token as a mobula-launchpad discovery
+			// to race other providers' launchpad feeds
+			rec.RecordPoolDiscoveryLatency("mobula-pulse", chainName, launchpadLabel(source), float64(discoveryLagMs), config.MonitorRegion)
+			rec.RecordPoolDiscoveryLatency("mobula-launchpad", chainName, launchpadLabel(source), float64(discoveryLagMs), config.MonitorRegion)
+			rec.RecordLaunchpadDiscovery("mobula-launchpad", chainName, token.Address, receiveTime, config.MonitorRegion)
 
 			// Queue token for metadata coverage check
 			QueueTokenForMetadataCheck(TokenToCheck{
@@ -256,17 +293,22 @@ func handlePulseV2Messages(conn *websocket.Conn, config *Config) {
 	}
 }
 
-func runMobulaPulseMonitor(config *Config, stopChan <-chan struct{}) {
+func runMobulaPulseMonitor(config *Config, rec Recorder, stopChan <-chan struct{}) {
 	fmt.Println("Starting Mobula Pulse V2 monitor...")
 	fmt.Printf("   Monitoring %d chains for LAUNCHPAD TOKENS ONLY\n", len(pulseChains))
-	fmt.Printf("   Launchpads: Pump.fun, Meteora, Four.meme, Zora, Baseapp, BAGS, Moonshot\n")
-	fmt.Printf("   Measuring discovery latency (on-chain creation → Mobula indexation)\n")
+	fmt.Printf("   Launchpads: Pump.fun, Meteora, Four.meme, Zora, Baseapp, BAGS, Moonshot (recorded as mobula-pulse and mobula-launchpad)\n")
+	fmt.Print(decorated("   Measuring discovery latency (on-chain creation → Mobula indexation)\n"))
 	fmt.Println()
 
 	if config.MobulaAPIKey == "" {
+		rec.RecordProviderStatus("mobula", "pulse", skipReasonMissingMobulaAPIKey, config.MonitorRegion)
 		fmt.Println("MOBULA_API_KEY not set in .env file. Skipping Mobula Pulse monitor.")
 		return
 	}
+	rec.RecordProviderStatus("mobula", "pulse", "", config.MonitorRegion)
+
+	ctx, cancel := stopContext(stopChan)
+	defer cancel()
 
 	reconnectDelay := 5 * time.Second
 	maxReconnectDelay := 60 * time.Second
@@ -277,10 +319,15 @@ func runMobulaPulseMonitor(config *Config, stopChan <-chan struct{}) {
 			fmt.Println("Mobula Pulse monitor stopped")
 			return
 		default:
-			conn, err := connectMobulaPulseWebSocket(config.MobulaAPIKey)
+			conn, err := connectMobulaPulseWebSocket(ctx, config.MobulaAPIKey)
 			if err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
 				log.Printf("[MOBULA-PULSE] Failed to connect: %v. Retrying in %v...", err, reconnectDelay)
-				time.Sleep(reconnectDelay)
+				if !backoffOrStop(rec, "mobula-pulse", stopChan, reconnectDelay, config.MonitorRegion) {
+					continue
+				}
 				reconnectDelay = reconnectDelay * 2
 				if reconnectDelay > maxReconnectDelay {
 					reconnectDelay = maxReconnectDelay
@@ -291,9 +338,12 @@ func runMobulaPulseMonitor(config *Config, stopChan <-chan struct{}) {
 			fmt.Println("   Connected to Mobula Pulse WebSocket")
 
 			if err := subscribeToPulse(conn, config.MobulaAPIKey); err != nil {
+				recordWebSocketWriteError(rec, "mobula-pulse", err, config.MonitorRegion)
 				log.Printf("[MOBULA-PULSE] Failed to subscribe: %v. Retrying in %v...", err, reconnectDelay)
 				conn.Close()
-				time.Sleep(reconnectDelay)
+				if !backoffOrStop(rec, "mobula-pulse", stopChan, reconnectDelay, config.MonitorRegion) {
+					continue
+				}
 				reconnectDelay = reconnectDelay * 2
 				if reconnectDelay > maxReconnectDelay {
 					reconnectDelay = maxReconnectDelay
@@ -310,16 +360,29 @@ func runMobulaPulseMonitor(config *Config, stopChan <-chan struct{}) {
 			fmt.Println("   Waiting for new pools to be created...")
 			fmt.Println()
 
-			// Reset reconnect delay on successful connection
-			reconnectDelay = 5 * time.Second
-
 			// This will block until connection error or stopChan
-			handlePulseV2Messages(conn, config)
+			connectedAt := time.Now()
+			handlePulseV2Messages(ctx, conn, config, rec)
 			conn.Close()
+			if ctx.Err() != nil {
+				continue
+			}
+
+			// Reset reconnect delay only after a connection that stayed up, so one that
+			// subscribes and then drops straight away still backs off
+			if time.Since(connectedAt) >= maxReconnectDelay {
+				reconnectDelay = 5 * time.Second
+			}
 
 			// Connection died, log and reconnect
 			log.Printf("[MOBULA-PULSE] Connection lost. Reconnecting in %v...", reconnectDelay)
-			time.Sleep(reconnectDelay)
+			if !backoffOrStop(rec, "mobula-pulse", stopChan, reconnectDelay, config.MonitorRegion) {
+				continue
+			}
+			reconnectDelay = reconnectDelay * 2
+			if reconnectDelay > maxReconnectDelay {
+				reconnectDelay = maxReconnectDelay
+			}
 		}
 	}
 }
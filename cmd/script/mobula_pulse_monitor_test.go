@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// withLaunchpads sets the LAUNCHPADS allowlist for the test
+func withLaunchpads(t *testing.T, names []string) {
+	t.Helper()
+	saved := launchpads
+	initLaunchpads(names)
+	t.Cleanup(func() { launchpads = saved })
+}
+
+// servePulseFrames answers the Pulse WebSocket with frames, then closes the connection
+func servePulseFrames(t *testing.T, frames ...string) *wsConn {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, frame := range frames {
+			conn.WriteMessage(websocket.TextMessage, []byte(frame))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	raw, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	conn := newWSConn(raw)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// pulseNewToken is a Pulse new-token frame for a token created a second ago
+func pulseNewToken(address string, source string) string {
+	createdAt := time.Now().Add(-time.Second).UTC().Format(time.RFC3339)
+	return fmt.Sprintf(`{"type":"new-token","payload":{"token":{"token":{"address":%q,"chainId":"solana:solana","createdAt":%q},"source":%q}}}`,
+		address, createdAt, source)
+}
+
+// mobula-pulse keeps its launchpad-only meaning; mobula-launchpad records the same tokens beside it
+func TestPulseRecordsLaunchpadTokensOnly(t *testing.T) {
+	withLaunchpads(t, defaultLaunchpads)
+	conn := servePulseFrames(t,
+		pulseNewToken("PumpToken111", "pumpfun"),
+		pulseNewToken("DexPool111", "raydium"),
+	)
+
+	rec := &fakeRecorder{}
+	handlePulseV2Messages(context.Background(), conn, &Config{MonitorRegion: "eu"}, rec)
+
+	var aggregators []string
+	for _, call := range rec.calls("RecordPoolDiscoveryLatency") {
+		fields := strings.Fields(call)
+		aggregators = append(aggregators, fields[0]+" "+fields[1]+" "+fields[2])
+	}
+	want := []string{"mobula-pulse solana pumpfun", "mobula-launchpad solana pumpfun"}
+	if strings.Join(aggregators, "|") != strings.Join(want, "|") {
+		t.Errorf("RecordPoolDiscoveryLatency calls = %q, want %q", aggregators, want)
+	}
+}
@@ -6,41 +6,79 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
-const (
-	mobulaRESTBaseURL = "https://api.mobula.io"
-)
-
-// Chains for REST monitoring - aligned with all monitors
-var mobulaRESTChains = []struct {
-	blockchain   string
-	blockchainID string
-	chainName    string
-	poolAddress  string
-}{
-	{"Solana", "solana", "solana", "7qbRF6YsyGuLUVs6Y1q64bdVrfe4ZcUUz1JRdoVNUJnm"},      // SOL/USDC
-	{"Ethereum", "1", "ethereum", "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"},          // WETH/USDC Uniswap V3
-	{"Base", "8453", "base", "0x4c36388be6f416a29c8d8eee81c771ce6be14b18"},              // WETH/USDC Base
-	{"BSC", "56", "bnb", "0x58F876857a02D6762E0101bb5C46A8c1ED44Dc16"},                  // WBNB/BUSD PancakeSwap
-	{"Arbitrum", "42161", "arbitrum", "0xc6962004f452be9203591991d15f6b388e09e8d0"},     // WETH/USDC Arbitrum
+// mobulaRESTEndpoints are the probes MOBULA_REST_ENDPOINTS can select, by endpoint label
+var mobulaRESTEndpoints = map[string]func(config *Config, pool MonitoredPool) (float64, int, error){
+	"market_data": func(config *Config, pool MonitoredPool) (float64, int, error) {
+		return callMobulaMarketDataAPI(config.MobulaAPIKey, pool.AddressFor("mobula"), pool.BlockchainID, pool.ChainName, config.MobulaHistoryWindow, config.MobulaHistoryPeriod)
+	},
+	"pair": func(config *Config, pool MonitoredPool) (float64, int, error) {
+		return callMobulaPairAPI(config.MobulaAPIKey, pool.AddressFor("mobula"), pool.BlockchainID, pool.ChainName)
+	},
+	// The /api/2 endpoints current integrations call, probed alongside v1 so both stay comparable
+	"market_v2": func(config *Config, pool MonitoredPool) (float64, int, error) {
+		return callMobulaV2API(config.MobulaAPIKey, "/api/2/market/details", pool.AddressFor("mobula"), pool.BlockchainID, pool.ChainName)
+	},
+	"price_v2": func(config *Config, pool MonitoredPool) (float64, int, error) {
+		return callMobulaV2API(config.MobulaAPIKey, "/api/2/token/price", pool.AddressFor("mobula"), pool.BlockchainID, pool.ChainName)
+	},
 }
 
-type MobulaMarketDataResponse struct {
-	Data []struct {
-		Volume float64 `json:"volume"`
-		Open   float64 `json:"open"`
-		High   float64 `json:"high"`
-		Low    float64 `json:"low"`
-		Close  float64 `json:"close"`
-		Time   int64   `json:"time"`
-	} `json:"data"`
+// mobulaHistoryPeriods are the candle periods MOBULA_HISTORY_PERIOD accepts, by Mobula's name
+var mobulaHistoryPeriods = map[string]time.Duration{
+	"1s":    time.Second,
+	"5s":    5 * time.Second,
+	"15s":   15 * time.Second,
+	"1min":  time.Minute,
+	"5min":  5 * time.Minute,
+	"15min": 15 * time.Minute,
+	"1h":    time.Hour,
+	"4h":    4 * time.Hour,
+	"1d":    24 * time.Hour,
 }
 
 // callMobulaMarketDataAPI makes a REST call to Mobula's market history/pair endpoint
-func callMobulaMarketDataAPI(apiKey string, poolAddress string, blockchain string, chainName string) (float64, int, error) {
-	endpoint := fmt.Sprintf("%s/api/1/market/history/pair", mobulaRESTBaseURL)
+// for the last window of candles
+func callMobulaMarketDataAPI(apiKey string, poolAddress string, blockchain string, chainName string, window time.Duration, period string) (float64, int, error) {
+	to := time.Now().UnixMilli()
+	from := time.Now().Add(-window).UnixMilli()
+
+	q := url.Values{}
+	q.Add("address", poolAddress)
+	q.Add("blockchain", blockchain)
+	q.Add("period", period)
+	q.Add("from", fmt.Sprintf("%d", from))
+	q.Add("to", fmt.Sprintf("%d", to))
+	q.Add("amount", "5") // Just get 5 candles, we don't care about data
+
+	return callMobulaRESTAPI(apiKey, "/api/1/market/history/pair", q, chainName)
+}
+
+// callMobulaPairAPI makes a REST call to Mobula's market pair endpoint (current pair data)
+func callMobulaPairAPI(apiKey string, poolAddress string, blockchain string, chainName string) (float64, int, error) {
+	q := url.Values{}
+	q.Add("address", poolAddress)
+	q.Add("blockchain", blockchain)
+
+	return callMobulaRESTAPI(apiKey, "/api/1/market/pair", q, chainName)
+}
+
+// callMobulaV2API makes a REST call to a Mobula /api/2 endpoint keyed by pool address
+func callMobulaV2API(apiKey string, path string, poolAddress string, blockchain string, chainName string) (float64, int, error) {
+	q := url.Values{}
+	q.Add("address", poolAddress)
+	q.Add("blockchain", blockchain)
+
+	return callMobulaRESTAPI(apiKey, path, q, chainName)
+}
+
+// callMobulaRESTAPI makes a GET request to a Mobula REST path and measures its latency
+func callMobulaRESTAPI(apiKey string, path string, query url.Values, chainName string) (float64, int, error) {
+	endpoint := providerURLs.MobulaREST + path
 
 	// Create HTTP client with timeout
 	client := &http.Client{
@@ -52,20 +90,7 @@ func callMobulaMarketDataAPI(apiKey string, poolAddress string, blockchain strin
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to create request: %w", err)
 	}
-
-	// Add query parameters
-	// Get last 1 hour of data with 1 minute candles
-	to := time.Now().UnixMilli()
-	from := time.Now().Add(-1 * time.Hour).UnixMilli()
-
-	q := req.URL.Query()
-	q.Add("address", poolAddress)
-	q.Add("blockchain", blockchain)
-	q.Add("period", "1min")
-	q.Add("from", fmt.Sprintf("%d", from))
-	q.Add("to", fmt.Sprintf("%d", to))
-	q.Add("amount", "5") // Just get 5 candles, we don't care about data
-	req.URL.RawQuery = q.Encode()
+	req.URL.RawQuery = query.Encode()
 
 	// Add headers
 	req.Header.Set("Authorization", apiKey)
@@ -84,104 +109,140 @@ func callMobulaMarketDataAPI(apiKey string, poolAddress string, blockchain strin
 	// Read response body for debugging
 	body, _ := io.ReadAll(resp.Body)
 
-	// Try to parse response
-	var marketData MobulaMarketDataResponse
-	if err := json.Unmarshal(body, &marketData); err != nil {
-		// Not a critical error, we still measured latency
-		log.Printf("[MOBULA-REST][%s] Response parse warning: %v (status: %d)", chainName, err, resp.StatusCode)
+	// Not a critical error, we still measured latency
+	if !json.Valid(body) {
+		log.Printf("[MOBULA-REST][%s] Response parse warning: %s returned invalid JSON (status: %d)", chainName, path, resp.StatusCode)
 	}
 
 	return latencyMs, resp.StatusCode, nil
 }
 
 // monitorMobulaREST continuously monitors Mobula REST API latency
-func monitorMobulaREST(config *Config, stopChan <-chan struct{}) {
+func monitorMobulaREST(config *Config, rec Recorder, stopChan <-chan struct{}) {
 	fmt.Println("Starting Mobula REST API monitor...")
-	fmt.Printf("   Monitoring %d chains with 20s interval\n", len(mobulaRESTChains))
-	fmt.Printf("   Endpoint: /api/1/market/history/pair\n")
+	fmt.Printf("   Monitoring %d pools with %v interval\n", len(monitoredPools()), currentRESTInterval())
+	fmt.Printf("   Endpoints: %s\n", strings.Join(config.MobulaRESTEndpoints, ", "))
+	logSkippedChains("[MOBULA-REST]", "mobula", ChainInfo.SupportsMobula)
 	fmt.Println()
 
 	if config.MobulaAPIKey == "" {
+		rec.RecordProviderStatus("mobula", "rest", skipReasonMissingMobulaAPIKey, config.MonitorRegion)
 		fmt.Println("MOBULA_API_KEY not set in .env file. Skipping Mobula REST monitor.")
 		return
 	}
+	rec.RecordProviderStatus("mobula", "rest", "", config.MonitorRegion)
 
-	// Create ticker for 20 second intervals
-	ticker := time.NewTicker(20 * time.Second)
+	ticker := time.NewTicker(currentRESTInterval())
 	defer ticker.Stop()
 
+	warmup := newRESTWarmup(config)
+
 	// Run once immediately
-	performMobulaRESTChecks(config)
+	if warmup.due(time.Now()) {
+		warmUpMobulaREST(config)
+	}
+	performMobulaRESTChecks(config, rec, stopChan)
 
-	// Then run every 20 seconds
+	// Then run on every tick, picking up interval changes from SIGHUP reloads
 	for {
 		select {
 		case <-stopChan:
 			fmt.Println("Mobula REST monitor stopped")
 			return
 		case <-ticker.C:
-			performMobulaRESTChecks(config)
+			if warmup.due(time.Now()) {
+				warmUpMobulaREST(config)
+			}
+			performMobulaRESTChecks(config, rec, stopChan)
+			ticker.Reset(currentRESTInterval())
 		}
 	}
 }
 
 // performMobulaRESTChecks performs REST API calls to all chains
-func performMobulaRESTChecks(config *Config) {
+func performMobulaRESTChecks(config *Config, rec Recorder, stopChan <-chan struct{}) {
 	timestamp := time.Now().UTC().Format("2006-01-02 15:04:05")
 
-	for _, chain := range mobulaRESTChains {
-		latencyMs, statusCode, err := callMobulaMarketDataAPI(
-			config.MobulaAPIKey,
-			chain.poolAddress,
-			chain.blockchainID,
-			chain.chainName,
-		)
-
-		if err != nil {
-			// Record error
-			errorType := "request_error"
-			if statusCode >= 500 {
-				errorType = "server_error"
-			} else if statusCode >= 400 {
-				errorType = "client_error"
-			} else if statusCode == 0 {
-				errorType = "timeout_error"
-			}
+	for _, pool := range monitoredPools() {
+		if !pool.SupportsMobula() || !chainEnabled("mobula", pool.ChainName) {
+			continue
+		}
 
-			RecordRESTError("mobula", "market_data", chain.chainName, errorType, config.MonitorRegion)
+		for _, endpoint := range config.MobulaRESTEndpoints {
+			checkMobulaEndpoint(config, rec, stopChan, endpoint, pool, timestamp)
+		}
+	}
+}
 
-			fmt.Printf("[MOBULA-REST][%s][%s] ERROR | Latency: %.0fms | Status: %d | Error: %v\n",
-				timestamp,
-				chain.chainName,
-				latencyMs,
-				statusCode,
-				err,
-			)
-			continue
+// warmUpMobulaREST sends one discarded request per endpoint, for the first pool Mobula covers
+func warmUpMobulaREST(config *Config) {
+	warmUpEndpoints("[MOBULA-REST]", config.MobulaRESTEndpoints, func(endpoint string) bool {
+		for _, pool := range monitoredPools() {
+			if pool.SupportsMobula() && chainEnabled("mobula", pool.ChainName) {
+				mobulaRESTEndpoints[endpoint](config, pool)
+				return true
+			}
 		}
+		return false
+	})
+}
 
-		// Record successful latency measurement
-		RecordRESTLatency("mobula", "market_data", chain.chainName, latencyMs, statusCode, config.MonitorRegion)
+// checkMobulaEndpoint probes one Mobula REST endpoint for a pool and records the outcome
+func checkMobulaEndpoint(config *Config, rec Recorder, stopChan <-chan struct{}, endpoint string, pool MonitoredPool, timestamp string) {
+	latencyMs, statusCode, err := withRESTRetry(stopChan, config.RESTRetries, func() (float64, int, error) {
+		return mobulaRESTEndpoints[endpoint](config, pool)
+	})
 
-		// Log the result
-		statusEmoji := "✓"
-		if statusCode >= 400 {
-			statusEmoji = "✗"
-		} else if statusCode >= 300 {
-			statusEmoji = "⚠"
+	if err != nil {
+		// Record error
+		errorType := "request_error"
+		if statusCode >= 500 {
+			errorType = "server_error"
+		} else if statusCode >= 400 {
+			errorType = "client_error"
+		} else if statusCode == 0 {
+			errorType = "timeout_error"
 		}
 
-		fmt.Printf("[MOBULA-REST][%s][%s] %s | Latency: %.0fms | Status: %d\n",
+		rec.RecordRESTError("mobula", endpoint, pool.ChainName, errorType, config.MonitorRegion)
+
+		fmt.Printf("[MOBULA-REST][%s][%s][%s] ERROR | Latency: %.0fms | Status: %d | Error: %v\n",
 			timestamp,
-			chain.chainName,
-			statusEmoji,
+			pool.ChainName,
+			endpoint,
 			latencyMs,
 			statusCode,
+			err,
 		)
+		return
+	}
+
+	// Record successful latency measurement
+	rec.RecordRESTLatency("mobula", endpoint, pool.ChainName, latencyMs, statusCode, config.MonitorRegion)
+	if statusCode < 400 {
+		// A rejected key or a rate limit still answers, but is no data for readiness
+		rec.RecordMonitorSuccess("mobula", "rest", config.MonitorRegion)
 	}
+
+	// Log the result
+	statusEmoji := "✓"
+	if statusCode >= 400 {
+		statusEmoji = "✗"
+	} else if statusCode >= 300 {
+		statusEmoji = "⚠"
+	}
+
+	fmt.Printf("[MOBULA-REST][%s][%s][%s] %s | Latency: %.0fms | Status: %d\n",
+		timestamp,
+		pool.ChainName,
+		endpoint,
+		statusEmoji,
+		latencyMs,
+		statusCode,
+	)
 }
 
 // runMobulaRESTMonitor is the entry point for the Mobula REST monitor
-func runMobulaRESTMonitor(config *Config, stopChan <-chan struct{}) {
-	monitorMobulaREST(config, stopChan)
+func runMobulaRESTMonitor(config *Config, rec Recorder, stopChan <-chan struct{}) {
+	monitorMobulaREST(config, rec, stopChan)
 }
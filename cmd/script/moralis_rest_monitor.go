@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 	"sync"
 	"time"
 )
@@ -25,60 +24,17 @@ type MoralisOHLCVResponse struct {
 	} `json:"result"`
 }
 
-type MoralisMonitorPool struct {
-	Name        string
-	Chain       string // Chain name for metrics
-	ChainID     string // Chain ID for Moralis API (hex for EVM, "solana" for Solana)
-	PairAddress string
-	IsEVM       bool
-}
-
-// Map WebSocket pool addresses to Moralis pairs
-var moralisPairMapping = map[string]MoralisMonitorPool{
-	// Ethereum
-	"0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640": {
-		Name:        "ETH/USDC Uniswap V3",
-		Chain:       "ethereum",
-		ChainID:     "0x1",
-		PairAddress: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
-		IsEVM:       true,
-	},
-	// Solana
-	"7qbRF6YsyGuLUVs6Y1q64bdVrfe4ZcUUz1JRdoVNUJnm": {
-		Name:        "SOL/USDC Raydium",
-		Chain:       "solana",
-		ChainID:     "solana",
-		PairAddress: "7qbRF6YsyGuLUVs6Y1q64bdVrfe4ZcUUz1JRdoVNUJnm",
-		IsEVM:       false,
-	},
-	// Base
-	"0x4c36388be6f416a29c8d8eee81c771ce6be14b18": {
-		Name:        "WETH/USDC Base",
-		Chain:       "base",
-		ChainID:     "0x2105",
-		PairAddress: "0x4c36388be6f416a29c8d8eee81c771ce6be14b18",
-		IsEVM:       true,
-	},
-	// BNB
-	"0x58f876857a02d6762e0101bb5c46a8c1ed44dc16": {
-		Name:        "WBNB/BUSD PancakeSwap",
-		Chain:       "bnb",
-		ChainID:     "0x38",
-		PairAddress: "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16",
-		IsEVM:       true,
-	},
-	// Arbitrum
-	"0xc6962004f452be9203591991d15f6b388e09e8d0": {
-		Name:        "WETH/USDC Arbitrum",
-		Chain:       "arbitrum",
-		ChainID:     "0xa4b1",
-		PairAddress: "0xc6962004f452be9203591991d15f6b388e09e8d0",
-		IsEVM:       true,
-	},
+// moralisPoolFor returns the registered pool for a WebSocket pair address, if Moralis covers its chain
+func moralisPoolFor(pairAddress string) (MonitoredPool, bool) {
+	pool, exists := lookupPool(pairAddress)
+	if !exists || !pool.SupportsMoralis() {
+		return MonitoredPool{}, false
+	}
+	return pool, true
 }
 
 var (
-	moralisCheckQueue = make(chan TradeCheckRequest, 1000)
+	moralisCheckQueue chan TradeCheckRequest // Sized by initMoralisQueue
 	moralisHttpClient = &http.Client{Timeout: 10 * time.Second}
 )
 
@@ -88,7 +44,12 @@ type TradeCheckRequest struct {
 	TransactionHash string
 }
 
-func runMoralisRESTMonitor(config *Config, stopChan <-chan struct{}, wg *sync.WaitGroup) {
+// initMoralisQueue creates the check queue. It must run before trades trigger Moralis checks.
+func initMoralisQueue(size int) {
+	moralisCheckQueue = make(chan TradeCheckRequest, size)
+}
+
+func runMoralisRESTMonitor(config *Config, rec Recorder, stopChan <-chan struct{}, wg *sync.WaitGroup) {
 	defer wg.Done()
 
 	fmt.Println("[HEAD-LAG][MORALIS-REST] Starting triggered REST monitor...")
@@ -98,10 +59,13 @@ func runMoralisRESTMonitor(config *Config, stopChan <-chan struct{}, wg *sync.Wa
 	for {
 		select {
 		case <-stopChan:
-			fmt.Println("[HEAD-LAG][MORALIS-REST] Monitor stopped")
+			drained, dropped := drainQueue(moralisCheckQueue, queueDrainTimeout, func(req TradeCheckRequest) {
+				checkMoralisForTrade(config, rec, req)
+			})
+			fmt.Printf("[HEAD-LAG][MORALIS-REST] Monitor stopped (drained %d queued checks, dropped %d)\n", drained, dropped)
 			return
 		case req := <-moralisCheckQueue:
-			checkMoralisForTrade(config, req)
+			checkMoralisForTrade(config, rec, req)
 		}
 	}
 }
@@ -110,10 +74,10 @@ func runMoralisRESTMonitor(config *Config, stopChan <-chan struct{}, wg *sync.Wa
 // It queues a check to see if Moralis has indexed it yet
 func TriggerMoralisCheck(pairAddress string, onChainTime time.Time, txHash string) {
 	// Normalize address
-	pairAddress = strings.ToLower(pairAddress)
+	pairAddress = normalizePoolAddress(pairAddress)
 
 	// Check if we monitor this pair
-	if _, exists := moralisPairMapping[pairAddress]; !exists {
+	if _, exists := moralisPoolFor(pairAddress); !exists {
 		return
 	}
 
@@ -128,8 +92,8 @@ func TriggerMoralisCheck(pairAddress string, onChainTime time.Time, txHash strin
 	}
 }
 
-func checkMoralisForTrade(config *Config, req TradeCheckRequest) {
-	pool, exists := moralisPairMapping[req.PairAddress]
+func checkMoralisForTrade(config *Config, rec Recorder, req TradeCheckRequest) {
+	pool, exists := moralisPoolFor(req.PairAddress)
 	if !exists {
 		return
 	}
@@ -140,7 +104,7 @@ func checkMoralisForTrade(config *Config, req TradeCheckRequest) {
 	return
 
 	// Build URL using correct Moralis Web3 Data API
-	url := fmt.Sprintf("https://deep-index.moralis.io/api/v2.2/pairs/%s/ohlcv", pool.PairAddress)
+	url := fmt.Sprintf("https://deep-index.moralis.io/api/v2.2/pairs/%s/ohlcv", pool.AddressFor("moralis"))
 
 	// Query from slightly before the on-chain trade to now
 	toDate := time.Now().UTC()
@@ -148,13 +112,13 @@ func checkMoralisForTrade(config *Config, req TradeCheckRequest) {
 
 	httpReq, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		RecordHeadLagError("moralis", pool.Chain, "request_creation_failed", config.MonitorRegion)
+		rec.RecordHeadLagError("moralis", pool.ChainName, "request_creation_failed", config.MonitorRegion)
 		return
 	}
 
 	q := httpReq.URL.Query()
-	if pool.IsEVM {
-		q.Add("chain", pool.ChainID)
+	if pool.Kind == ChainKindEVM {
+		q.Add("chain", pool.MoralisChain)
 	}
 	q.Add("to_date", fmt.Sprintf("%d", toDate.Unix()))
 	q.Add("from_date", fmt.Sprintf("%d", fromDate.Unix()))
@@ -169,32 +133,32 @@ func checkMoralisForTrade(config *Config, req TradeCheckRequest) {
 	checkTime := time.Now()
 	resp, err := moralisHttpClient.Do(httpReq)
 	if err != nil {
-		RecordHeadLagError("moralis", pool.Chain, "request_failed", config.MonitorRegion)
+		rec.RecordHeadLagError("moralis", pool.ChainName, "request_failed", config.MonitorRegion)
 		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		RecordHeadLagError("moralis", pool.Chain, fmt.Sprintf("http_%d", resp.StatusCode), config.MonitorRegion)
+		rec.RecordHeadLagError("moralis", pool.ChainName, fmt.Sprintf("http_%d", resp.StatusCode), config.MonitorRegion)
 		return
 	}
 
 	// Parse response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		RecordHeadLagError("moralis", pool.Chain, "read_body_failed", config.MonitorRegion)
+		rec.RecordHeadLagError("moralis", pool.ChainName, "read_body_failed", config.MonitorRegion)
 		return
 	}
 
 	var data MoralisOHLCVResponse
 	if err := json.Unmarshal(body, &data); err != nil {
-		RecordHeadLagError("moralis", pool.Chain, "json_parse_failed", config.MonitorRegion)
+		rec.RecordHeadLagError("moralis", pool.ChainName, "json_parse_failed", config.MonitorRegion)
 		return
 	}
 
 	if len(data.Result) == 0 {
 		// No data yet - trade not indexed
-		RecordHeadLagError("moralis", pool.Chain, "trade_not_found", config.MonitorRegion)
+		rec.RecordHeadLagError("moralis", pool.ChainName, "trade_not_found", config.MonitorRegion)
 		return
 	}
 
@@ -217,11 +181,11 @@ func checkMoralisForTrade(config *Config, req TradeCheckRequest) {
 			lagSeconds := float64(lagMs) / 1000.0
 
 			// Record metrics
-			RecordHeadLag("moralis", pool.Chain, lagMs, lagSeconds, config.MonitorRegion)
+			rec.RecordHeadLag("moralis", pool.ChainName, lagMs, lagSeconds, config.MonitorRegion)
 
 			// Log
 			fmt.Printf("[HEAD-LAG][MORALIS][%s][%s] Trade found! Lag: %.2fs | Tx: %s | Candle: %s\n",
-				checkTime.Format("15:04:05"), pool.Chain, lagSeconds, req.TransactionHash[:16], candle.Timestamp)
+				checkTime.Format("15:04:05"), pool.ChainName, lagSeconds, safeTruncate(req.TransactionHash, 16), candle.Timestamp)
 
 			found = true
 			break
@@ -230,6 +194,6 @@ func checkMoralisForTrade(config *Config, req TradeCheckRequest) {
 
 	if !found {
 		// Trade happened but not in any candle yet
-		RecordHeadLagError("moralis", pool.Chain, "trade_not_in_candles", config.MonitorRegion)
+		rec.RecordHeadLagError("moralis", pool.ChainName, "trade_not_in_candles", config.MonitorRegion)
 	}
 }
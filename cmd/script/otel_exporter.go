@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// ============================================================================
+// OpenTelemetry Export
+// Mirrors the key measurements as OTel instruments pushed over OTLP, alongside
+// the Prometheus registry
+// ============================================================================
+
+const otelServiceName = "aggregator-latency-benchmark"
+
+// OTelRecorder wraps the Prometheus Recorder and also records head lag, REST and quote
+// latency, request outcomes and data staleness as OTel instruments. Every other
+// measurement only reaches Prometheus.
+type OTelRecorder struct {
+	Recorder
+
+	provider *sdkmetric.MeterProvider
+
+	headLag           metric.Float64Histogram
+	restLatency       metric.Float64Histogram
+	quoteLatency      metric.Float64Histogram
+	restRequests      metric.Int64Counter
+	quoteRequests     metric.Int64Counter
+	restDataFreshness metric.Float64Gauge
+}
+
+// NewOTelRecorder starts an OTLP/gRPC exporter to endpoint, pushing every interval
+func NewOTelRecorder(next Recorder, endpoint string, interval time.Duration) (*OTelRecorder, error) {
+	exporter, err := otlpmetricgrpc.New(context.Background(), otlpmetricgrpc.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))),
+		sdkmetric.WithResource(resource.NewSchemaless(attribute.String("service.name", otelServiceName))),
+	)
+	meter := provider.Meter("mobula_latency_competitor")
+
+	r := &OTelRecorder{Recorder: next, provider: provider}
+	fail := func(err error) (*OTelRecorder, error) {
+		provider.Shutdown(context.Background())
+		return nil, fmt.Errorf("failed to create OTel instrument: %w", err)
+	}
+
+	if r.headLag, err = meter.Float64Histogram("head_lag",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Time from a trade's on-chain timestamp to its receipt from the aggregator")); err != nil {
+		return fail(err)
+	}
+	if r.restLatency, err = meter.Float64Histogram("rest_api_latency",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Latency of successful REST API calls")); err != nil {
+		return fail(err)
+	}
+	if r.quoteLatency, err = meter.Float64Histogram("quote_api_latency",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Latency of successful quote API calls")); err != nil {
+		return fail(err)
+	}
+	if r.restRequests, err = meter.Int64Counter("rest_api_requests",
+		metric.WithDescription("REST API calls by result (success or error)")); err != nil {
+		return fail(err)
+	}
+	if r.quoteRequests, err = meter.Int64Counter("quote_api_requests",
+		metric.WithDescription("Quote API calls by result (success or error)")); err != nil {
+		return fail(err)
+	}
+	if r.restDataFreshness, err = meter.Float64Gauge("rest_data_freshness",
+		metric.WithUnit("s"),
+		metric.WithDescription("Age of the newest bar returned by a REST endpoint")); err != nil {
+		return fail(err)
+	}
+
+	// Pool liveness is read on each export, as pool_seconds_since_last_message is on each scrape
+	poolActiveGauge, err := meter.Int64ObservableGauge("pool_active",
+		metric.WithDescription("1 if the subscribed pool streamed at least one trade since the feed connected"))
+	if err != nil {
+		return fail(err)
+	}
+	poolStaleness, err := meter.Float64ObservableGauge("pool_staleness",
+		metric.WithUnit("s"),
+		metric.WithDescription("Time since the subscribed pool's last trade, or since it was subscribed if none"))
+	if err != nil {
+		return fail(err)
+	}
+	if _, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, pool := range exportedPoolLiveness(time.Now()) {
+			attrs := metric.WithAttributes(
+				attribute.String("aggregator", pool.Provider),
+				attribute.String("chain", pool.Chain),
+				attribute.String("pool", pool.Pool),
+				attribute.String("region", pool.Region),
+			)
+			active := int64(0)
+			if pool.Active {
+				active = 1
+			}
+			o.ObserveInt64(poolActiveGauge, active, attrs)
+			o.ObserveFloat64(poolStaleness, pool.Silence.Seconds(), attrs)
+		}
+		return nil
+	}, poolActiveGauge, poolStaleness); err != nil {
+		return fail(err)
+	}
+
+	return r, nil
+}
+
+// Close flushes the last measurements and stops the exporter
+func (r *OTelRecorder) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.provider.Shutdown(ctx); err != nil {
+		fmt.Printf("OTel exporter shutdown error: %v\n", err)
+	}
+}
+
+// RecordTrade mirrors head lag like head_lag_summary_milliseconds: clock-skewed trades are left out
+func (r *OTelRecorder) RecordTrade(trade NormalizedTrade) {
+	r.Recorder.RecordTrade(trade)
+	if trade.LagMs < 0 {
+		return
+	}
+	r.headLag.Record(context.Background(), float64(trade.LagMs), metric.WithAttributes(
+		attribute.String("aggregator", trade.Provider),
+		attribute.String("chain", trade.Chain),
+		attribute.String("region", trade.Region),
+	))
+}
+
+func (r *OTelRecorder) RecordRESTLatency(aggregator string, endpoint string, chain string, latencyMs float64, statusCode int, region string) {
+	r.Recorder.RecordRESTLatency(aggregator, endpoint, chain, latencyMs, statusCode, region)
+	attrs := []attribute.KeyValue{
+		attribute.String("aggregator", aggregator),
+		attribute.String("endpoint", endpoint),
+		attribute.String("chain", chain),
+		attribute.String("region", region),
+	}
+	r.restLatency.Record(context.Background(), latencyMs, metric.WithAttributes(attrs...))
+	r.restRequests.Add(context.Background(), 1, metric.WithAttributes(append(attrs, attribute.String("result", "success"))...))
+}
+
+func (r *OTelRecorder) RecordRESTError(aggregator string, endpoint string, chain string, errorType string, region string) {
+	r.Recorder.RecordRESTError(aggregator, endpoint, chain, errorType, region)
+	r.restRequests.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("aggregator", aggregator),
+		attribute.String("endpoint", endpoint),
+		attribute.String("chain", chain),
+		attribute.String("region", region),
+		attribute.String("result", "error"),
+		attribute.String("error_type", errorType),
+	))
+}
+
+func (r *OTelRecorder) RecordRESTDataQuality(aggregator string, endpoint string, chain string, freshnessSeconds float64, completeness float64, region string) {
+	r.Recorder.RecordRESTDataQuality(aggregator, endpoint, chain, freshnessSeconds, completeness, region)
+	r.restDataFreshness.Record(context.Background(), freshnessSeconds, metric.WithAttributes(
+		attribute.String("aggregator", aggregator),
+		attribute.String("endpoint", endpoint),
+		attribute.String("chain", chain),
+		attribute.String("region", region),
+	))
+}
+
+func (r *OTelRecorder) RecordQuoteAPILatency(provider string, chain string, latencyMs float64, statusCode int, region string) {
+	r.Recorder.RecordQuoteAPILatency(provider, chain, latencyMs, statusCode, region)
+	attrs := []attribute.KeyValue{
+		attribute.String("provider", provider),
+		attribute.String("chain", chain),
+		attribute.String("region", region),
+	}
+	r.quoteLatency.Record(context.Background(), latencyMs, metric.WithAttributes(attrs...))
+	r.quoteRequests.Add(context.Background(), 1, metric.WithAttributes(append(attrs, attribute.String("result", "success"))...))
+}
+
+func (r *OTelRecorder) RecordQuoteAPIError(provider string, chain string, errorType string, region string) {
+	r.Recorder.RecordQuoteAPIError(provider, chain, errorType, region)
+	r.quoteRequests.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("provider", provider),
+		attribute.String("chain", chain),
+		attribute.String("region", region),
+		attribute.String("result", "error"),
+		attribute.String("error_type", errorType),
+	))
+}
@@ -0,0 +1,32 @@
+package main
+
+import "strings"
+
+// ============================================================================
+// Plain Output
+// PLAIN_OUTPUT: startup banners and printed tables fall back to ASCII, for
+// log aggregators and CI consoles that mangle box-drawing characters
+// ============================================================================
+
+// plainOutputReplacer maps each decorative character to ASCII; box characters map to a single
+// character so tables keep their alignment
+var plainOutputReplacer = strings.NewReplacer(
+	"╔", "+", "╗", "+", "╚", "+", "╝", "+", "╠", "+", "╣", "+",
+	"═", "=", "║", "|", "│", "|",
+	"→", "->",
+)
+
+// plainOutput is set once at startup by initPlainOutput, before the monitors start
+var plainOutput bool
+
+func initPlainOutput(plain bool) {
+	plainOutput = plain
+}
+
+// decorated returns s unchanged, or in ASCII under PLAIN_OUTPUT
+func decorated(s string) string {
+	if !plainOutput {
+		return s
+	}
+	return plainOutputReplacer.Replace(s)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ============================================================================
+// Pool List
+// POOLS_FILE / POOLS_JSON: extra pools to monitor as a JSON list, from a mounted
+// file or, where mounting files is awkward, straight from the environment
+// ============================================================================
+
+// poolListEntry is one pool of a POOLS_FILE or POOLS_JSON list:
+//
+//	[{"name": "WETH/USDC Uniswap V3", "chain": "ethereum", "address": "0x..."}]
+type poolListEntry struct {
+	Name    string `json:"name"` // Defaults to the address
+	Chain   string `json:"chain"`
+	Address string `json:"address"`
+}
+
+// loadPoolList reads the extra pools from POOLS_FILE, or from POOLS_JSON when no file is set.
+// Both use the same schema; an empty result means neither is set.
+func loadPoolList(poolsFile string, poolsJSON string) ([]MonitoredPool, error) {
+	if poolsFile != "" {
+		data, err := os.ReadFile(poolsFile)
+		if err != nil {
+			return nil, fmt.Errorf("invalid POOLS_FILE: %w", err)
+		}
+		return parsePoolList("POOLS_FILE", data)
+	}
+	if strings.TrimSpace(poolsJSON) != "" {
+		return parsePoolList("POOLS_JSON", []byte(poolsJSON))
+	}
+	return nil, nil
+}
+
+// parsePoolList parses a pool list against the chain registry; key names the setting in errors
+func parsePoolList(key string, data []byte) ([]MonitoredPool, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var entries []poolListEntry
+	if err := decoder.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("invalid %s: expected a JSON list of {\"name\", \"chain\", \"address\"}: %w", key, err)
+	}
+
+	pools := make([]MonitoredPool, 0, len(entries))
+	for i, entry := range entries {
+		address := strings.TrimSpace(entry.Address)
+		if address == "" {
+			return nil, fmt.Errorf("invalid %s entry %d: missing address", key, i)
+		}
+		chain, ok := lookupChain(strings.ToLower(strings.TrimSpace(entry.Chain)))
+		if !ok {
+			return nil, fmt.Errorf("invalid %s entry %d: unsupported chain %q", key, i, entry.Chain)
+		}
+		name := strings.TrimSpace(entry.Name)
+		if name == "" {
+			name = address
+		}
+		pools = append(pools, MonitoredPool{Name: name, Address: address, ChainInfo: chain})
+	}
+	return pools, nil
+}
+
+// registerPoolList adds the POOLS_FILE or POOLS_JSON pools to the registry. It must run before the
+// monitors start. Pools already monitored are skipped.
+func registerPoolList(pools []MonitoredPool) {
+	added := 0
+	for _, pool := range pools {
+		if registerPool(pool) {
+			added++
+		} else {
+			fmt.Printf("[POOLS][%s] %s already monitored\n", pool.ChainName, pool.Address)
+		}
+	}
+	if len(pools) > 0 {
+		fmt.Printf("[POOLS] Added %d of %d configured pools\n", added, len(pools))
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ============================================================================
+// Pool Liveness
+// Tracks which subscribed pools have streamed a trade since the feed connected,
+// telling a dead or wrong pool apart from a dead connection
+// ============================================================================
+
+// poolActivity is one pool subscribed on a feed's current connection
+type poolActivity struct {
+	chain      string
+	pool       string
+	region     string
+	since      time.Time // Subscription time on the current connection
+	lastSeenAt time.Time // Zero until the pool streams a trade
+}
+
+var poolLiveness = struct {
+	sync.Mutex
+	pools     map[string]map[string]*poolActivity // provider -> normalized pool address -> activity
+	streaming map[string]map[string]bool          // provider -> chains that streamed a trade on the current connection
+}{
+	pools:     make(map[string]map[string]*poolActivity),
+	streaming: make(map[string]map[string]bool),
+}
+
+// resetPoolLiveness replaces the pools tracked for a provider with those subscribed on its new connection
+func resetPoolLiveness(provider string, pools []MonitoredPool, region string, now time.Time) {
+	tracked := make(map[string]*poolActivity, len(pools))
+	for _, pool := range pools {
+		tracked[normalizePoolAddress(pool.Address)] = &poolActivity{
+			chain:  pool.ChainName,
+			pool:   pool.Address,
+			region: region,
+			since:  now,
+		}
+		ClearTimeToFirstEvent(provider, pool.ChainName, region)
+	}
+
+	poolLiveness.Lock()
+	defer poolLiveness.Unlock()
+	poolLiveness.pools[provider] = tracked
+	poolLiveness.streaming[provider] = make(map[string]bool)
+}
+
+// markPoolSeen records a trade on a pool; trades on pools the provider did not subscribe to are ignored.
+// On the connection's first trade on the pool's chain, it also records the time since subscribing.
+func markPoolSeen(provider string, pool string, at time.Time) {
+	if pool == "" {
+		return
+	}
+
+	poolLiveness.Lock()
+	defer poolLiveness.Unlock()
+	activity, ok := poolLiveness.pools[provider][normalizePoolAddress(pool)]
+	if !ok {
+		return
+	}
+	activity.lastSeenAt = at
+
+	if streaming := poolLiveness.streaming[provider]; !streaming[activity.chain] {
+		streaming[activity.chain] = true
+		RecordTimeToFirstEvent(provider, activity.chain, float64(at.Sub(activity.since).Milliseconds()), activity.region)
+	}
+}
+
+// PoolLiveness is a subscribed pool's state at one point in time
+type PoolLiveness struct {
+	Provider string
+	Chain    string
+	Pool     string
+	Region   string
+	Active   bool          // Streamed at least one trade since the feed connected
+	Silence  time.Duration // Since the last trade, or since the subscription if none
+}
+
+// poolLivenessSnapshot returns the liveness of every pool subscribed on the current connections
+func poolLivenessSnapshot(now time.Time) []PoolLiveness {
+	poolLiveness.Lock()
+	defer poolLiveness.Unlock()
+
+	var snapshot []PoolLiveness
+	for provider, pools := range poolLiveness.pools {
+		for _, activity := range pools {
+			last := activity.since
+			if !activity.lastSeenAt.IsZero() {
+				last = activity.lastSeenAt
+			}
+			snapshot = append(snapshot, PoolLiveness{
+				Provider: provider,
+				Chain:    activity.chain,
+				Pool:     activity.pool,
+				Region:   activity.region,
+				Active:   !activity.lastSeenAt.IsZero(),
+				Silence:  now.Sub(last),
+			})
+		}
+	}
+	return snapshot
+}
+
+// poolLivenessCollector exports pool_active and pool_seconds_since_last_message, evaluated on scrape
+type poolLivenessCollector struct{}
+
+func (poolLivenessCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolActive
+	ch <- poolSecondsSinceLastMessage
+}
+
+func (poolLivenessCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, pool := range exportedPoolLiveness(time.Now()) {
+		active := 0.0
+		if pool.Active {
+			active = 1
+		}
+		ch <- prometheus.MustNewConstMetric(poolActive, prometheus.GaugeValue, active, pool.Provider, pool.Chain, pool.Pool, pool.Region)
+		ch <- prometheus.MustNewConstMetric(poolSecondsSinceLastMessage, prometheus.GaugeValue, pool.Silence.Seconds(), pool.Provider, pool.Chain, pool.Pool, pool.Region)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ============================================================================
+// Pool Overrides
+// POOL_OVERRIDES: a provider can be subscribed to its own address for a
+// registry pool, when it indexes a different canonical pool or format for the
+// same pair; its trades are mapped back to the registry pool for comparison
+// ============================================================================
+
+// poolOverrideProviders are the providers POOL_OVERRIDES may name; GeckoTerminal keeps its own pool IDs
+var poolOverrideProviders = []string{"mobula", "codex", "moralis"}
+
+// PoolOverride subscribes Provider to Address in place of the registry pool Pool
+type PoolOverride struct {
+	Provider string
+	Pool     string
+	Address  string
+}
+
+// poolOverrides is set once at startup by initPoolOverrides, before the monitors start
+var poolOverrides = struct {
+	byPool    map[string]map[string]string // provider -> normalized registry address -> override address
+	byAddress map[string]map[string]string // provider -> normalized override address -> registry address
+}{
+	byPool:    make(map[string]map[string]string),
+	byAddress: make(map[string]map[string]string),
+}
+
+// initPoolOverrides resolves each override against the pool registry, so it must run after
+// resolveTokenPools. Overrides of pools that are not monitored are reported and ignored.
+func initPoolOverrides(overrides []PoolOverride) {
+	for _, override := range overrides {
+		pool, ok := lookupPool(override.Pool)
+		if !ok {
+			fmt.Printf("[POOLS][%s] Ignoring override of %s: not a monitored pool\n", override.Provider, override.Pool)
+			continue
+		}
+
+		if poolOverrides.byPool[override.Provider] == nil {
+			poolOverrides.byPool[override.Provider] = make(map[string]string)
+			poolOverrides.byAddress[override.Provider] = make(map[string]string)
+		}
+		poolOverrides.byPool[override.Provider][normalizePoolAddress(pool.Address)] = override.Address
+		poolOverrides.byAddress[override.Provider][normalizePoolAddress(override.Address)] = pool.Address
+		fmt.Printf("[POOLS][%s][%s] Subscribing to %s in place of %s (%s)\n", override.Provider, pool.ChainName, override.Address, pool.Address, pool.Name)
+	}
+}
+
+// AddressFor returns the address provider is subscribed to for the pool: its POOL_OVERRIDES entry, if any
+func (p MonitoredPool) AddressFor(provider string) string {
+	if address, ok := poolOverrides.byPool[provider][normalizePoolAddress(p.Address)]; ok {
+		return address
+	}
+	return p.Address
+}
+
+// canonicalPoolAddress maps an address a provider reported back to the registry pool it overrides,
+// returning the address unchanged if it is not an override
+func canonicalPoolAddress(provider string, address string) string {
+	if pool, ok := poolOverrides.byAddress[provider][normalizePoolAddress(address)]; ok {
+		return pool
+	}
+	return address
+}
+
+// parsePoolOverrides parses POOL_OVERRIDES ("codex:<registry pool>=<address>,mobula:<registry pool>=<address>").
+// Registry pools are matched like any pool lookup, case-insensitively.
+func parsePoolOverrides(raw string) ([]PoolOverride, error) {
+	var overrides []PoolOverride
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, address, ok := strings.Cut(entry, "=")
+		provider, pool, hasProvider := strings.Cut(key, ":")
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		pool, address = strings.TrimSpace(pool), strings.TrimSpace(address)
+		if !ok || !hasProvider || pool == "" || address == "" {
+			return nil, fmt.Errorf("invalid POOL_OVERRIDES entry %q: expected <provider>:<pool>=<address>", entry)
+		}
+		if !slices.Contains(poolOverrideProviders, provider) {
+			return nil, fmt.Errorf("invalid POOL_OVERRIDES entry %q: unknown provider %q (expected %s)", entry, provider, strings.Join(poolOverrideProviders, ", "))
+		}
+		overrides = append(overrides, PoolOverride{Provider: provider, Pool: pool, Address: address})
+	}
+	return overrides, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import "testing"
+
+// withPoolOverrides applies overrides for the test and restores the previous set afterwards
+func withPoolOverrides(t *testing.T, overrides []PoolOverride) {
+	t.Helper()
+	saved := poolOverrides
+	poolOverrides.byPool = make(map[string]map[string]string)
+	poolOverrides.byAddress = make(map[string]map[string]string)
+	t.Cleanup(func() { poolOverrides = saved })
+
+	initPoolOverrides(overrides)
+}
+
+func TestParsePoolOverrides(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    []PoolOverride
+		wantErr bool
+	}{
+		{raw: "", want: nil},
+		{
+			raw:  "codex:0x58f876857a02d6762e0101bb5c46a8c1ed44dc16=0xabc, Mobula:ETH/USDC=0xdef",
+			want: []PoolOverride{{"codex", "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16", "0xabc"}, {"mobula", "ETH/USDC", "0xdef"}},
+		},
+		{raw: "codex:0xpool", wantErr: true},
+		{raw: "0xpool=0xabc", wantErr: true},
+		{raw: "codex:=0xabc", wantErr: true},
+		{raw: "codex:0xpool=", wantErr: true},
+		{raw: "geckoterminal:0xpool=0xabc", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parsePoolOverrides(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePoolOverrides(%q) error = %v, want error %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("parsePoolOverrides(%q) = %v, want %v", tt.raw, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("parsePoolOverrides(%q)[%d] = %v, want %v", tt.raw, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestPoolOverridesMapBothWays(t *testing.T) {
+	const (
+		registry = "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16"
+		override = "0x1111111111111111111111111111111111111111"
+	)
+	withPoolOverrides(t, []PoolOverride{
+		{Provider: "codex", Pool: registry, Address: override},
+		{Provider: "mobula", Pool: "0x0000000000000000000000000000000000000bad", Address: override}, // Not monitored, ignored
+	})
+
+	pool, ok := lookupPool(registry)
+	if !ok {
+		t.Fatalf("registry pool %s not found", registry)
+	}
+
+	tests := []struct {
+		provider string
+		wantSub  string
+	}{
+		{"codex", override},
+		{"mobula", registry},
+		{"moralis", registry},
+	}
+	for _, tt := range tests {
+		if got := pool.AddressFor(tt.provider); got != tt.wantSub {
+			t.Errorf("AddressFor(%q) = %q, want %q", tt.provider, got, tt.wantSub)
+		}
+	}
+
+	if got := canonicalPoolAddress("codex", override); got != registry {
+		t.Errorf("canonicalPoolAddress(codex, override) = %q, want the registry pool %q", got, registry)
+	}
+	if got := canonicalPoolAddress("mobula", override); got != override {
+		t.Errorf("canonicalPoolAddress(mobula, override) = %q, want it unchanged", got)
+	}
+}
+
+func TestCanonicalPoolAddressIgnoresCasing(t *testing.T) {
+	const registry = "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16"
+	withPoolOverrides(t, []PoolOverride{
+		{Provider: "mobula", Pool: "0x58F876857a02D6762E0101bb5C46A8c1ED44Dc16", Address: "0xAAAABBBBCCCCDDDDEEEEFFFF0000111122223333"},
+	})
+
+	for _, reported := range []string{
+		"0xAAAABBBBCCCCDDDDEEEEFFFF0000111122223333",
+		"0xaaaabbbbccccddddeeeeffff0000111122223333",
+	} {
+		if got := canonicalPoolAddress("mobula", reported); got != registry {
+			t.Errorf("canonicalPoolAddress(mobula, %q) = %q, want %q", reported, got, registry)
+		}
+	}
+	pool, _ := lookupPool(registry)
+	if got := pool.AddressFor("mobula"); got != "0xAAAABBBBCCCCDDDDEEEEFFFF0000111122223333" {
+		t.Errorf("AddressFor(mobula) = %q, want the override as configured", got)
+	}
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Pool Registry
+// Chains and pools shared by every monitor, with each provider's identifiers
+// ============================================================================
+
+// ChainKind is the address family of a chain
+type ChainKind string
+
+const (
+	ChainKindEVM    ChainKind = "evm"
+	ChainKindSolana ChainKind = "solana"
+	ChainKindSui    ChainKind = "sui"
+	ChainKindOther  ChainKind = "other"
+)
+
+// ChainInfo holds how each provider identifies a chain.
+// An empty identifier means that provider does not cover the chain.
+type ChainInfo struct {
+	ChainName     string    // Normalized chain name for metrics
+	Kind          ChainKind // Address family, decides address normalization
+	Blockchain    string    // For Mobula WebSocket: "evm:1", "solana", etc.
+	BlockchainID  string    // For Mobula REST: "1", "solana", etc.
+	NetworkID     int       // For Codex: 1, 1399811149, etc.
+	MoralisChain  string    // For Moralis: "0x1", "solana", etc.
+	GoldRushChain string    // For GoldRush: "eth-mainnet", "base-mainnet", etc. (EVM chains only)
+}
+
+// MonitoredPool is a pool tracked by the head lag and REST monitors
+type MonitoredPool struct {
+	Name    string // Human readable name
+	Address string // Pool address
+	ChainInfo
+}
+
+var (
+	ethereumChain = ChainInfo{
+		ChainName:     "ethereum",
+		Kind:          ChainKindEVM,
+		Blockchain:    "evm:1",
+		BlockchainID:  "1",
+		NetworkID:     1,
+		MoralisChain:  "0x1",
+		GoldRushChain: "eth-mainnet",
+	}
+	solanaChain = ChainInfo{
+		ChainName:    "solana",
+		Kind:         ChainKindSolana,
+		Blockchain:   "solana",
+		BlockchainID: "solana",
+		NetworkID:    1399811149,
+		MoralisChain: "solana",
+	}
+	baseChain = ChainInfo{
+		ChainName:     "base",
+		Kind:          ChainKindEVM,
+		Blockchain:    "evm:8453",
+		BlockchainID:  "8453",
+		NetworkID:     8453,
+		MoralisChain:  "0x2105",
+		GoldRushChain: "base-mainnet",
+	}
+	bnbChain = ChainInfo{
+		ChainName:     "bnb",
+		Kind:          ChainKindEVM,
+		Blockchain:    "evm:56",
+		BlockchainID:  "56",
+		NetworkID:     56,
+		MoralisChain:  "0x38",
+		GoldRushChain: "bsc-mainnet",
+	}
+	arbitrumChain = ChainInfo{
+		ChainName:     "arbitrum",
+		Kind:          ChainKindEVM,
+		Blockchain:    "evm:42161",
+		BlockchainID:  "42161",
+		NetworkID:     42161,
+		MoralisChain:  "0xa4b1",
+		GoldRushChain: "arbitrum-mainnet",
+	}
+	polygonChain = ChainInfo{
+		ChainName:     "polygon",
+		Kind:          ChainKindEVM,
+		Blockchain:    "evm:137",
+		BlockchainID:  "137",
+		NetworkID:     137,
+		MoralisChain:  "0x89",
+		GoldRushChain: "matic-mainnet",
+	}
+	avalancheChain = ChainInfo{
+		ChainName:     "avalanche",
+		Kind:          ChainKindEVM,
+		Blockchain:    "evm:43114",
+		BlockchainID:  "43114",
+		NetworkID:     43114,
+		MoralisChain:  "0xa86a",
+		GoldRushChain: "avalanche-mainnet",
+	}
+	optimismChain = ChainInfo{
+		ChainName:     "optimism",
+		Kind:          ChainKindEVM,
+		Blockchain:    "evm:10",
+		BlockchainID:  "10",
+		NetworkID:     10,
+		MoralisChain:  "0xa",
+		GoldRushChain: "optimism-mainnet",
+	}
+	// Only Mobula and Codex are probed on Monad. No default pool: add one with TOKENS.
+	monadChain = ChainInfo{
+		ChainName:    "monad",
+		Kind:         ChainKindEVM,
+		Blockchain:   "evm:143",
+		BlockchainID: "143",
+		NetworkID:    143,
+	}
+	// Codex and Moralis do not index Sui
+	suiChain = ChainInfo{
+		ChainName:    "sui",
+		Kind:         ChainKindSui,
+		Blockchain:   "sui",
+		BlockchainID: "sui",
+	}
+)
+
+// Chains every monitor knows how to address
+var chainRegistry = []ChainInfo{
+	ethereumChain,
+	solanaChain,
+	baseChain,
+	bnbChain,
+	arbitrumChain,
+	polygonChain,
+	avalancheChain,
+	optimismChain,
+	monadChain,
+	suiChain,
+}
+
+// chainIDAliases maps other spellings of a chain ID to the Blockchain ID in chainRegistry.
+// Pulse reports Solana as "solana:solana" while every other Mobula API uses "solana".
+var chainIDAliases = map[string]string{
+	"solana:solana": "solana",
+}
+
+// poolsMu guards poolRegistry, which grows when TOKENS are resolved at startup or on SIGHUP
+var poolsMu sync.RWMutex
+
+// Pools to monitor - high activity pools for accurate lag measurement.
+// Read it through monitoredPools, pools resolved from TOKENS are added with registerPool.
+var poolRegistry = []MonitoredPool{
+	{
+		Name:      "ETH/USDC Uniswap V3",
+		Address:   "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+		ChainInfo: ethereumChain,
+	},
+	{
+		Name:      "SOL/USDC Raydium",
+		Address:   "7qbRF6YsyGuLUVs6Y1q64bdVrfe4ZcUUz1JRdoVNUJnm",
+		ChainInfo: solanaChain,
+	},
+	{
+		Name:      "WETH/USDC Base",
+		Address:   "0x4c36388be6f416a29c8d8eee81c771ce6be14b18",
+		ChainInfo: baseChain,
+	},
+	{
+		Name:      "WBNB/BUSD PancakeSwap",
+		Address:   "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16",
+		ChainInfo: bnbChain,
+	},
+	{
+		Name:      "WETH/USDC Arbitrum",
+		Address:   "0xc6962004f452be9203591991d15f6b388e09e8d0",
+		ChainInfo: arbitrumChain,
+	},
+	{
+		Name:      "WPOL/USDC.e Uniswap V3 Polygon",
+		Address:   "0xa374094527e1673a86de625aa59517c5de346d32",
+		ChainInfo: polygonChain,
+	},
+	{
+		Name:      "WAVAX/USDC Trader Joe",
+		Address:   "0xf4003f4efbe8691b60249e6afbd307abe7758adb",
+		ChainInfo: avalancheChain,
+	},
+	{
+		Name:      "WETH/USDC.e Uniswap V3 Optimism",
+		Address:   "0x85149247691df622eaf1a8bd0cafd40bc45154a9",
+		ChainInfo: optimismChain,
+	},
+	{
+		Name:      "USDC/SUI Cetus",
+		Address:   "0xb8d7d9e66a60c239e7a60110efcf8de6c705580ed924d0dde141f4a0e2c90105",
+		ChainInfo: suiChain,
+	},
+}
+
+// normalizePoolAddress is the canonical form pool addresses are stored and compared in: hex
+// addresses (EVM, Sui) lowercased, since providers mix checksummed and lowercase forms, and
+// base58 ones (Solana) untouched, since they are case-sensitive
+func normalizePoolAddress(address string) string {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+// SupportsMobula reports whether Mobula indexes the chain
+func (c ChainInfo) SupportsMobula() bool {
+	return c.Blockchain != ""
+}
+
+// SupportsCodex reports whether Codex indexes the chain
+func (c ChainInfo) SupportsCodex() bool {
+	return c.NetworkID != 0
+}
+
+// SupportsGoldRush reports whether the GoldRush monitor probes the chain
+func (c ChainInfo) SupportsGoldRush() bool {
+	return c.GoldRushChain != ""
+}
+
+// SupportsMoralis reports whether Moralis indexes the chain
+func (c ChainInfo) SupportsMoralis() bool {
+	return c.MoralisChain != ""
+}
+
+// logSkippedChains reports registry chains a provider does not cover or is disabled on by PROVIDER_CHAINS
+func logSkippedChains(prefix string, provider string, supported func(ChainInfo) bool) {
+	for _, chain := range chainRegistry {
+		if !supported(chain) {
+			fmt.Printf("%s Skipping %s: chain not supported by provider\n", prefix, chain.ChainName)
+		} else if !chainEnabled(provider, chain.ChainName) {
+			fmt.Printf("%s Skipping %s: disabled by PROVIDER_CHAINS\n", prefix, chain.ChainName)
+		}
+	}
+}
+
+// lookupChain finds a chain by its normalized name
+func lookupChain(chainName string) (ChainInfo, bool) {
+	for _, chain := range chainRegistry {
+		if chain.ChainName == chainName {
+			return chain, true
+		}
+	}
+	return ChainInfo{}, false
+}
+
+// canonicalChainID resolves aliases so every provider and metric sees one ID per chain
+func canonicalChainID(chainID string) string {
+	if canonical, ok := chainIDAliases[chainID]; ok {
+		return canonical
+	}
+	return chainID
+}
+
+// lookupChainID finds a chain by its Mobula chain ID ("evm:1", "solana", "solana:solana", ...)
+func lookupChainID(chainID string) (ChainInfo, bool) {
+	chainID = canonicalChainID(chainID)
+	for _, chain := range chainRegistry {
+		if chain.Blockchain == chainID {
+			return chain, true
+		}
+	}
+	return ChainInfo{}, false
+}
+
+// lookupNetworkID finds a chain by its Codex network ID
+func lookupNetworkID(networkID int) (ChainInfo, bool) {
+	for _, chain := range chainRegistry {
+		if chain.NetworkID == networkID {
+			return chain, true
+		}
+	}
+	return ChainInfo{}, false
+}
+
+// monitoredPools returns a snapshot of the pool registry
+func monitoredPools() []MonitoredPool {
+	poolsMu.RLock()
+	defer poolsMu.RUnlock()
+
+	pools := make([]MonitoredPool, len(poolRegistry))
+	copy(pools, poolRegistry)
+	return pools
+}
+
+// lookupPool finds a registered pool by address, in any casing for hex addresses (see normalizePoolAddress)
+func lookupPool(address string) (MonitoredPool, bool) {
+	poolsMu.RLock()
+	defer poolsMu.RUnlock()
+
+	return findPool(address)
+}
+
+// registerPool adds a pool unless it is already monitored, its address normalized
+func registerPool(pool MonitoredPool) bool {
+	pool.Address = normalizePoolAddress(pool.Address)
+
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	if _, exists := findPool(pool.Address); exists {
+		return false
+	}
+	poolRegistry = append(poolRegistry, pool)
+	return true
+}
+
+// findPool scans the registry. Callers must hold poolsMu.
+func findPool(address string) (MonitoredPool, bool) {
+	address = normalizePoolAddress(address)
+	for _, pool := range poolRegistry {
+		if normalizePoolAddress(pool.Address) == address {
+			return pool, true
+		}
+	}
+	return MonitoredPool{}, false
+}
@@ -0,0 +1,107 @@
+package main
+
+import "testing"
+
+func TestNormalizePoolAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    string
+	}{
+		{"checksummed EVM", "0x58F876857a02D6762E0101bb5C46A8c1ED44Dc16", "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16"},
+		{"lowercase EVM", "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16", "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16"},
+		{"uppercase prefix", "0X58F876857A02D6762E0101BB5C46A8C1ED44DC16", "0x58f876857a02d6762e0101bb5c46a8c1ed44dc16"},
+		{"Sui", "0xB8D7D9E66A60C239E7A60110EFCF8DE6C705580ED924D0DDE141F4A0E2C90105", "0xb8d7d9e66a60c239e7a60110efcf8de6c705580ed924d0dde141f4a0e2c90105"},
+		{"Solana base58", "7qbRF6YsyGuLUVs6Y1q64bdVrfe4ZcUUz1JRdoVNUJnm", "7qbRF6YsyGuLUVs6Y1q64bdVrfe4ZcUUz1JRdoVNUJnm"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizePoolAddress(tt.address); got != tt.want {
+			t.Errorf("%s: normalizePoolAddress(%q) = %q, want %q", tt.name, tt.address, got, tt.want)
+		}
+	}
+}
+
+// The same pool must match in the registry and the GeckoTerminal pool list whatever casing a provider reports
+func TestPoolMatchesAcrossCasing(t *testing.T) {
+	tests := []struct {
+		address string
+		wantOK  bool
+	}{
+		{"0x58F876857a02D6762E0101bb5C46A8c1ED44Dc16", true}, // Mobula, checksummed
+		{"0x58f876857a02d6762e0101bb5c46a8c1ed44dc16", true}, // Codex and Moralis, lowercase
+		{"0X58F876857A02D6762E0101BB5C46A8C1ED44DC16", true},
+		{"7qbRF6YsyGuLUVs6Y1q64bdVrfe4ZcUUz1JRdoVNUJnm", true},
+		{"7QBRF6YSYGULUVS6Y1Q64BDVRFE4ZCUUZ1JRDOVNUJNM", false}, // base58 is case-sensitive
+	}
+	for _, tt := range tests {
+		pool, ok := lookupPool(tt.address)
+		if ok != tt.wantOK {
+			t.Errorf("lookupPool(%q) found = %v, want %v", tt.address, ok, tt.wantOK)
+			continue
+		}
+		if ok && pool.Address != normalizePoolAddress(tt.address) {
+			t.Errorf("lookupPool(%q) = %q, want the normalized address", tt.address, pool.Address)
+		}
+	}
+
+	if got := geckoPoolForAddress("0x58F876857a02D6762E0101bb5C46A8c1ED44Dc16"); got == "" {
+		t.Error("geckoPoolForAddress: checksummed address not matched to its GeckoTerminal pool")
+	}
+}
+
+func TestRegisterPoolNormalizesAddress(t *testing.T) {
+	before := monitoredPools()
+	t.Cleanup(func() {
+		poolsMu.Lock()
+		poolRegistry = before
+		poolsMu.Unlock()
+	})
+
+	pool := MonitoredPool{Name: "TEST/USDC", Address: "0xABCDEF0000000000000000000000000000000001", ChainInfo: ethereumChain}
+	if !registerPool(pool) {
+		t.Fatal("registerPool: new pool not added")
+	}
+	if registerPool(MonitoredPool{Name: "TEST/USDC", Address: "0xabcdef0000000000000000000000000000000001", ChainInfo: ethereumChain}) {
+		t.Error("registerPool: same pool in another casing added twice")
+	}
+	registered, ok := lookupPool("0xAbCdEf0000000000000000000000000000000001")
+	if !ok || registered.Address != "0xabcdef0000000000000000000000000000000001" {
+		t.Errorf("lookupPool = %q (found %v), want the lowercase address", registered.Address, ok)
+	}
+}
+
+func TestSolanaChainIDAliases(t *testing.T) {
+	tests := []struct {
+		chainID     string
+		canonical   string
+		chainName   string
+		networkID   int
+		solana      bool
+		wantInChain bool
+	}{
+		{"solana", "solana", "solana", 1399811149, true, true},
+		{"solana:solana", "solana", "solana", 1399811149, true, true},
+		{"evm:1", "evm:1", "ethereum", 1, false, true},
+		{"evm:56", "evm:56", "bnb", 56, false, true},
+		{"evm:143", "evm:143", "monad", 143, false, true},
+		{"evm:999999", "evm:999999", "evm:999999", 0, false, false}, // Unknown chains keep their ID as the label
+	}
+	for _, tt := range tests {
+		if got := canonicalChainID(tt.chainID); got != tt.canonical {
+			t.Errorf("canonicalChainID(%q) = %q, want %q", tt.chainID, got, tt.canonical)
+		}
+		if _, ok := lookupChainID(tt.chainID); ok != tt.wantInChain {
+			t.Errorf("lookupChainID(%q) found = %v, want %v", tt.chainID, ok, tt.wantInChain)
+		}
+		if got := getChainNameForPulse(tt.chainID); got != tt.chainName {
+			t.Errorf("getChainNameForPulse(%q) = %q, want %q", tt.chainID, got, tt.chainName)
+		}
+		if got := getCodexNetworkID(tt.chainID); got != tt.networkID {
+			t.Errorf("getCodexNetworkID(%q) = %d, want %d", tt.chainID, got, tt.networkID)
+		}
+		if got := isSolanaChainID(tt.chainID); got != tt.solana {
+			t.Errorf("isSolanaChainID(%q) = %v, want %v", tt.chainID, got, tt.solana)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Pool Validation
+// Asks every provider at startup whether it knows each monitored pool, so a stale
+// address shows up as a warning instead of a feed that is merely quiet
+// ============================================================================
+
+// poolLookup asks one provider whether it knows a pool. It returns an error only when
+// the provider could not be asked; an unknown pool is resolved=false.
+type poolLookup func(client *http.Client, pool MonitoredPool) (resolved bool, err error)
+
+// validatePools looks up every monitored pool with each provider that subscribes to it,
+// sets pool_resolved and warns about pools a provider does not know. Providers are
+// queried in parallel; providers without credentials are skipped.
+func validatePools(config *Config) {
+	if !config.ValidatePools {
+		fmt.Println("Pool validation skipped (VALIDATE_POOLS=false)")
+		return
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	lookups := map[string]poolLookup{}
+
+	if config.MobulaAPIKey != "" {
+		lookups["mobula"] = func(client *http.Client, pool MonitoredPool) (bool, error) {
+			if !pool.SupportsMobula() {
+				return true, nil
+			}
+			return lookupMobulaPool(client, config.MobulaAPIKey, pool)
+		}
+	}
+	if config.DefinedSessionCookie != "" {
+		jwtToken, err := GetDefinedJWTToken(context.Background(), config.DefinedSessionCookie)
+		if err != nil {
+			fmt.Printf("[POOLS] Could not validate Codex pools: %v\n", err)
+		} else {
+			lookups["codex"] = func(client *http.Client, pool MonitoredPool) (bool, error) {
+				if !pool.SupportsCodex() {
+					return true, nil
+				}
+				return lookupCodexPool(client, jwtToken, pool)
+			}
+		}
+	}
+	lookups["geckoterminal"] = func(client *http.Client, pool MonitoredPool) (bool, error) {
+		if geckoPoolForAddress(pool.Address) == "" {
+			return true, nil
+		}
+		return lookupGeckoTerminalPool(client, pool)
+	}
+
+	pools := monitoredPools()
+	fmt.Printf("Validating %d pools with %d providers...\n", len(pools), len(lookups))
+
+	var wg sync.WaitGroup
+	for provider, lookup := range lookups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, pool := range pools {
+				resolved, err := lookup(client, pool)
+				if err != nil {
+					fmt.Printf("[POOLS][%s][%s] Could not check %s (%s): %v\n", provider, pool.ChainName, pool.Name, pool.Address, err)
+					continue
+				}
+				RecordPoolResolved(provider, pool.ChainName, pool.Address, resolved, config.MonitorRegion)
+				if !resolved {
+					fmt.Printf("[POOLS][%s][%s] WARNING: %s (%s) does not resolve, expect no trades from it\n", provider, pool.ChainName, pool.Name, pool.Address)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	fmt.Println()
+}
+
+// lookupMobulaPool checks that Mobula's market pair endpoint returns data for the pool
+func lookupMobulaPool(client *http.Client, apiKey string, pool MonitoredPool) (bool, error) {
+	q := url.Values{}
+	q.Add("address", pool.AddressFor("mobula"))
+	q.Add("blockchain", pool.BlockchainID)
+
+	req, err := http.NewRequest("GET", providerURLs.MobulaREST+"/api/1/market/pair?"+q.Encode(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", apiKey)
+
+	body, statusCode, err := doPoolLookup(client, req)
+	if err != nil {
+		return false, err
+	}
+	if statusCode != http.StatusOK {
+		return false, nil
+	}
+
+	var pairResp struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &pairResp); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return len(pairResp.Data) > 0 && string(pairResp.Data) != "null", nil
+}
+
+// lookupCodexPool checks that Codex pairMetadata returns the pool
+func lookupCodexPool(client *http.Client, jwtToken string, pool MonitoredPool) (bool, error) {
+	bodyBytes, err := json.Marshal(CodexGraphQLRequest{
+		Query: `
+			query PairMetadata($pairId: String!) {
+				pairMetadata(pairId: $pairId) {
+					id
+				}
+			}
+		`,
+		Variables: map[string]interface{}{
+			"pairId": fmt.Sprintf("%s:%d", pool.AddressFor("codex"), pool.NetworkID),
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", providerURLs.CodexGraphQL, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", jwtToken))
+	req.Header.Set("Content-Type", "application/json")
+
+	body, _, err := doPoolLookup(client, req)
+	if err != nil {
+		return false, err
+	}
+
+	var metadataResp struct {
+		Data struct {
+			PairMetadata *struct {
+				ID string `json:"id"`
+			} `json:"pairMetadata"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &metadataResp); err != nil {
+		return false, fmt.Errorf("failed to parse response: %w", err)
+	}
+	// An unknown pair comes back as a GraphQL error, an expired token as this one
+	if len(metadataResp.Errors) > 0 && metadataResp.Errors[0].Message == codexUnauthenticatedMessage {
+		return false, fmt.Errorf("%w: %s", errCodexAuth, metadataResp.Errors[0].Message)
+	}
+	return metadataResp.Data.PairMetadata != nil, nil
+}
+
+// lookupGeckoTerminalPool checks that GeckoTerminal's public pool endpoint knows the pool
+func lookupGeckoTerminalPool(client *http.Client, pool MonitoredPool) (bool, error) {
+	network := ""
+	for _, geckoPool := range geckoTerminalPools {
+		if normalizePoolAddress(geckoPool.Address) == normalizePoolAddress(pool.Address) {
+			network = geckoPool.Network
+		}
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/networks/%s/pools/%s", providerURLs.GeckoTerminalREST, network, pool.Address), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	_, statusCode, err := doPoolLookup(client, req)
+	if err != nil {
+		return false, err
+	}
+	return statusCode == http.StatusOK, nil
+}
+
+// doPoolLookup sends a lookup request and returns its body. Rate limits and server errors
+// say nothing about the pool, so they are returned as errors; other 4xx mean not found.
+func doPoolLookup(client *http.Client, req *http.Request) ([]byte, int, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return body, resp.StatusCode, nil
+}
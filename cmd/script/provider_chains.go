@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ============================================================================
+// Provider Chains
+// PROVIDER_CHAINS: which chains each provider is benchmarked on, consulted by
+// every monitor when it builds its subscription or poll set
+// ============================================================================
+
+// chainMatrixProviders are the providers PROVIDER_CHAINS may name, "*" standing for all of them
+var chainMatrixProviders = []string{"mobula", "codex", "geckoterminal", "goldrush", "syve", "jupiter", "openocean", "paraswap", "lifi", "kyberswap"}
+
+// ProviderChains enables providers per chain. A provider without rules runs on every chain
+// it covers; the "*" rules apply to every provider on top of its own.
+type ProviderChains struct {
+	only   map[string]map[string]bool // Provider -> the only chains it runs on
+	except map[string]map[string]bool // Provider -> chains it skips
+}
+
+// Enabled reports whether provider runs on chain
+func (m ProviderChains) Enabled(provider string, chain string) bool {
+	for _, key := range []string{provider, "*"} {
+		if only, ok := m.only[key]; ok && !only[chain] {
+			return false
+		}
+		if m.except[key][chain] {
+			return false
+		}
+	}
+	return true
+}
+
+// providerChains is set once at startup, before the monitors start
+var providerChains ProviderChains
+
+func initProviderChains(m ProviderChains) {
+	providerChains = m
+}
+
+// chainEnabled reports whether PROVIDER_CHAINS lets provider run on chain
+func chainEnabled(provider string, chain string) bool {
+	return providerChains.Enabled(provider, chain)
+}
+
+// parseProviderChains parses PROVIDER_CHAINS ("codex=solana,mobula=!bnb,*=!sui"). <provider>=<chain>
+// restricts the provider to the chains listed that way, <provider>=!<chain> excludes a chain.
+// Chains are checked against the chain registry.
+func parseProviderChains(raw string) (ProviderChains, error) {
+	m := ProviderChains{
+		only:   make(map[string]map[string]bool),
+		except: make(map[string]map[string]bool),
+	}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		provider, chain, ok := strings.Cut(entry, "=")
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		chain = strings.ToLower(strings.TrimSpace(chain))
+		excluded := strings.HasPrefix(chain, "!")
+		chain = strings.TrimPrefix(chain, "!")
+		if !ok || chain == "" {
+			return ProviderChains{}, fmt.Errorf("invalid PROVIDER_CHAINS entry %q: expected <provider>=<chain> or <provider>=!<chain>", entry)
+		}
+		if provider != "*" && !slices.Contains(chainMatrixProviders, provider) {
+			return ProviderChains{}, fmt.Errorf("invalid PROVIDER_CHAINS entry %q: unknown provider %q (expected *, %s)", entry, provider, strings.Join(chainMatrixProviders, ", "))
+		}
+		if _, known := lookupChain(chain); !known {
+			names := make([]string, 0, len(chainRegistry))
+			for _, c := range chainRegistry {
+				names = append(names, c.ChainName)
+			}
+			return ProviderChains{}, fmt.Errorf("invalid PROVIDER_CHAINS entry %q: unknown chain %q (expected %s)", entry, chain, strings.Join(names, ", "))
+		}
+
+		rules, other := m.only, m.except
+		if excluded {
+			rules, other = m.except, m.only
+		}
+		if other[provider] != nil {
+			return ProviderChains{}, fmt.Errorf("invalid PROVIDER_CHAINS entry %q: %s cannot both list and exclude chains", entry, provider)
+		}
+		if rules[provider] == nil {
+			rules[provider] = make(map[string]bool)
+		}
+		rules[provider][chain] = true
+	}
+	return m, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ============================================================================
+// Provider URLs
+// PROVIDER_URLS: the endpoint each provider is reached on, to benchmark a
+// staging environment or run against mock servers (see the e2e test)
+// ============================================================================
+
+// ProviderURLs holds every provider endpoint the monitors call. REST fields are the base the
+// monitors append their paths to, unless noted.
+type ProviderURLs struct {
+	MobulaWS          string
+	MobulaPulseWS     string
+	MobulaREST        string // Also serves token details and swap quoting under /api/2
+	CodexWS           string
+	CodexGraphQL      string // Full endpoint, for the REST monitor, metadata checks and pool validation
+	DefinedAPI        string // Issues the JWTs Codex authenticates with
+	GeckoTerminalWS   string
+	GeckoTerminalREST string
+	GoldRushREST      string
+	SyveDexTrades     string // Full endpoint
+	BinanceStream     string // Combined stream endpoint, the symbols go in the query
+	JupiterQuote      string // Full endpoint; free, 10 req/sec, Solana only
+	JupiterTokens     string // Token pages, the metadata monitor appends the mint
+	OpenOceanQuote    string
+	ParaSwapQuote     string // Full endpoint
+	KyberSwapQuote    string
+	LifiQuote         string // Full endpoint
+}
+
+// defaultProviderURLs are the production endpoints
+var defaultProviderURLs = ProviderURLs{
+	MobulaWS:          "wss://api.mobula.io",
+	MobulaPulseWS:     "wss://pulse-v2-api.mobula.io",
+	MobulaREST:        "https://api.mobula.io",
+	CodexWS:           "wss://graph.codex.io/graphql",
+	CodexGraphQL:      "https://graph.codex.io/graphql",
+	DefinedAPI:        "https://www.defined.fi/api",
+	GeckoTerminalWS:   "wss://cables.geckoterminal.com/cable",
+	GeckoTerminalREST: "https://api.geckoterminal.com/api/v2",
+	GoldRushREST:      "https://api.covalenthq.com",
+	SyveDexTrades:     "https://api.syve.ai/v1/filter-api/dex-trades",
+	BinanceStream:     "wss://stream.binance.com:9443/stream",
+	JupiterQuote:      "https://public.jupiterapi.com/quote",
+	JupiterTokens:     "https://jup.ag/tokens",
+	OpenOceanQuote:    "https://open-api.openocean.finance/v3",
+	ParaSwapQuote:     "https://apiv5.paraswap.io/prices",
+	KyberSwapQuote:    "https://aggregator-api.kyberswap.com",
+	LifiQuote:         "https://li.quest/v1/quote",
+}
+
+// providerURLField is one PROVIDER_URLS name and the field it sets
+type providerURLField struct {
+	name      string
+	target    *string
+	websocket bool // ws:// or wss://, http:// or https:// otherwise
+}
+
+func (u *ProviderURLs) fields() []providerURLField {
+	return []providerURLField{
+		{"mobula-ws", &u.MobulaWS, true},
+		{"mobula-pulse-ws", &u.MobulaPulseWS, true},
+		{"mobula-rest", &u.MobulaREST, false},
+		{"codex-ws", &u.CodexWS, true},
+		{"codex-graphql", &u.CodexGraphQL, false},
+		{"defined-api", &u.DefinedAPI, false},
+		{"geckoterminal-ws", &u.GeckoTerminalWS, true},
+		{"geckoterminal-rest", &u.GeckoTerminalREST, false},
+		{"goldrush-rest", &u.GoldRushREST, false},
+		{"syve-rest", &u.SyveDexTrades, false},
+		{"binance-ws", &u.BinanceStream, true},
+		{"jupiter-quote", &u.JupiterQuote, false},
+		{"jupiter-tokens", &u.JupiterTokens, false},
+		{"openocean-quote", &u.OpenOceanQuote, false},
+		{"paraswap-quote", &u.ParaSwapQuote, false},
+		{"kyberswap-quote", &u.KyberSwapQuote, false},
+		{"lifi-quote", &u.LifiQuote, false},
+	}
+}
+
+// overridden lists the names whose URL differs from the default, for the config summary
+func (u ProviderURLs) overridden() []string {
+	defaults := defaultProviderURLs
+	defaultFields := defaults.fields()
+
+	var names []string
+	for i, field := range u.fields() {
+		if *field.target != *defaultFields[i].target {
+			names = append(names, field.name)
+		}
+	}
+	return names
+}
+
+// providerURLs is set once at startup, before the monitors start
+var providerURLs = defaultProviderURLs
+
+func initProviderURLs(u ProviderURLs) {
+	providerURLs = u
+}
+
+// parseProviderURLs parses PROVIDER_URLS ("mobula-ws=wss://staging.example,codex-graphql=http://localhost:8080/graphql")
+// over the defaults. A trailing slash is dropped, the monitors add their own.
+func parseProviderURLs(raw string) (ProviderURLs, error) {
+	u := defaultProviderURLs
+	fields := u.fields()
+
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSuffix(strings.TrimSpace(value), "/")
+		if !ok || value == "" {
+			return ProviderURLs{}, fmt.Errorf("invalid PROVIDER_URLS entry %q: expected <name>=<url>", entry)
+		}
+
+		var field *providerURLField
+		names := make([]string, 0, len(fields))
+		for i := range fields {
+			names = append(names, fields[i].name)
+			if fields[i].name == name {
+				field = &fields[i]
+			}
+		}
+		if field == nil {
+			return ProviderURLs{}, fmt.Errorf("invalid PROVIDER_URLS entry %q: unknown endpoint %q (expected %s)", entry, name, strings.Join(names, ", "))
+		}
+
+		parsed, err := url.Parse(value)
+		if err != nil || parsed.Host == "" {
+			return ProviderURLs{}, fmt.Errorf("invalid PROVIDER_URLS entry %q: not an absolute URL", entry)
+		}
+		schemes := "http or https"
+		valid := parsed.Scheme == "http" || parsed.Scheme == "https"
+		if field.websocket {
+			schemes = "ws or wss"
+			valid = parsed.Scheme == "ws" || parsed.Scheme == "wss"
+		}
+		if !valid {
+			return ProviderURLs{}, fmt.Errorf("invalid PROVIDER_URLS entry %q: %s expects a %s URL", entry, name, schemes)
+		}
+
+		*field.target = value
+	}
+	return u, nil
+}
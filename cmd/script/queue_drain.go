@@ -0,0 +1,25 @@
+package main
+
+import "time"
+
+// queueDrainTimeout bounds how long a monitor keeps working through its queue after stop
+const queueDrainTimeout = 10 * time.Second
+
+// drainQueue processes the items still queued at shutdown until the queue is empty or
+// the timeout passes, and returns how many were processed and how many were left behind.
+// The deadline is checked between items, so one slow item can run past it.
+func drainQueue[T any](queue chan T, timeout time.Duration, process func(T)) (drained int, dropped int) {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		select {
+		case item := <-queue:
+			process(item)
+			drained++
+		default:
+			return drained, 0
+		}
+	}
+
+	return drained, len(queue)
+}
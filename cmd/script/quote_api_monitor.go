@@ -6,20 +6,10 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 )
 
-// Quote API endpoints
-const (
-	// Free APIs (no API key required)
-	jupiterPublicURL  = "https://public.jupiterapi.com/quote" // Free, 10 req/sec, Solana only
-	mobulaSwapURL     = "https://api.mobula.io/api/2/swap/quoting" // Solana only for now
-	openOceanQuoteURL = "https://open-api.openocean.finance/v3"
-	paraSwapQuoteURL  = "https://apiv5.paraswap.io/prices"
-	kyberSwapQuoteURL = "https://aggregator-api.kyberswap.com"
-	lifiQuoteURL      = "https://li.quest/v1/quote"
-)
-
 // Dummy wallet addresses for APIs that require fromAddress
 const dummyWalletAddressEVM = "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045"    // Vitalik's address (EVM)
 const dummyWalletAddressSolana = "HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH" // Random Solana wallet
@@ -99,6 +89,66 @@ var evmQuoteChains = []QuoteChainConfig{
 		Amount:         "100000000", // 100 USDC (6 decimals)
 		Decimals:       6,
 	},
+	{
+		Name:           "polygon",
+		ChainID:        "137",
+		OpenOceanChain: "137",
+		KyberChainKey:  "polygon",
+		TokenIn:        "0x3c499c542cEF5E3811e1192ce70d8cC03d5c3359", // Native USDC on Polygon
+		TokenOut:       "0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270", // WPOL (formerly WMATIC)
+		TokenInSymbol:  "USDC",
+		TokenOutSymbol: "WPOL",
+		Amount:         "100000000", // 100 USDC (6 decimals)
+		Decimals:       6,
+	},
+	{
+		Name:           "avalanche",
+		ChainID:        "43114",
+		OpenOceanChain: "43114",
+		KyberChainKey:  "avalanche",
+		TokenIn:        "0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E", // USDC on Avalanche
+		TokenOut:       "0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7", // WAVAX
+		TokenInSymbol:  "USDC",
+		TokenOutSymbol: "WAVAX",
+		Amount:         "100000000", // 100 USDC (6 decimals)
+		Decimals:       6,
+	},
+	{
+		Name:           "optimism",
+		ChainID:        "10",
+		OpenOceanChain: "10",
+		KyberChainKey:  "optimism",
+		TokenIn:        "0x0b2C639c533813f4Aa9D7837CAf62653d097Ff85", // USDC on Optimism
+		TokenOut:       "0x4200000000000000000000000000000000000006", // WETH on Optimism
+		TokenInSymbol:  "USDC",
+		TokenOutSymbol: "WETH",
+		Amount:         "100000000", // 100 USDC (6 decimals)
+		Decimals:       6,
+	},
+}
+
+// quoteSlippageParams converts a slippage in basis points to each provider's quote parameter.
+// ParaSwap prices and KyberSwap routes take no slippage: it only applies when building the swap.
+var quoteSlippageParams = map[string]func(bps int) (key string, value string){
+	"mobula":    func(bps int) (string, string) { return "slippage", formatSlippage(float64(bps) / 100) },   // Percent
+	"jupiter":   func(bps int) (string, string) { return "slippageBps", strconv.Itoa(bps) },                 // Basis points
+	"openocean": func(bps int) (string, string) { return "slippage", formatSlippage(float64(bps) / 100) },   // Percent
+	"lifi":      func(bps int) (string, string) { return "slippage", formatSlippage(float64(bps) / 10000) }, // Fraction
+}
+
+func formatSlippage(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// addQuoteSlippage sets provider's slippage parameter when QUOTE_SLIPPAGE_BPS configures one,
+// otherwise the provider applies its own default
+func addQuoteSlippage(params url.Values, provider string, slippageBps map[string]int) {
+	bps, ok := slippageBps[provider]
+	if !ok {
+		return
+	}
+	key, value := quoteSlippageParams[provider](bps)
+	params.Set(key, value)
 }
 
 // HTTP client with timeout
@@ -110,7 +160,7 @@ var quoteHTTPClient = &http.Client{
 // Mobula Swap Quoting API (Solana + Base + Arbitrum, requires API key)
 // ============================================================================
 
-func callMobulaSwapQuoteAPI(chainID string, chainName string, tokenIn string, tokenOut string, amount string, apiKey string) (float64, int, error) {
+func callMobulaSwapQuoteAPI(chainID string, chainName string, tokenIn string, tokenOut string, amount string, apiKey string, slippageBps map[string]int) (float64, int, error) {
 	// Use appropriate wallet address based on chain
 	walletAddress := dummyWalletAddressEVM
 	if chainName == "solana" {
@@ -123,9 +173,9 @@ func callMobulaSwapQuoteAPI(chainID string, chainName string, tokenIn string, to
 	params.Add("tokenOut", tokenOut)
 	params.Add("amount", amount)
 	params.Add("walletAddress", walletAddress)
-	params.Add("slippage", "1")
+	addQuoteSlippage(params, "mobula", slippageBps)
 
-	fullURL := fmt.Sprintf("%s?%s", mobulaSwapURL, params.Encode())
+	fullURL := providerURLs.MobulaREST + "/api/2/swap/quoting?" + params.Encode()
 
 	req, err := http.NewRequest("GET", fullURL, nil)
 	if err != nil {
@@ -164,14 +214,14 @@ func callMobulaSwapQuoteAPI(chainID string, chainName string, tokenIn string, to
 // Jupiter Public API (Solana only, FREE - 10 req/sec)
 // ============================================================================
 
-func callJupiterPublicQuoteAPI() (float64, int, error) {
+func callJupiterPublicQuoteAPI(slippageBps map[string]int) (float64, int, error) {
 	params := url.Values{}
 	params.Add("inputMint", solanaConfig.TokenIn)
 	params.Add("outputMint", solanaConfig.TokenOut)
 	params.Add("amount", solanaConfig.Amount)
-	params.Add("slippageBps", "50")
+	addQuoteSlippage(params, "jupiter", slippageBps)
 
-	fullURL := fmt.Sprintf("%s?%s", jupiterPublicURL, params.Encode())
+	fullURL := fmt.Sprintf("%s?%s", providerURLs.JupiterQuote, params.Encode())
 
 	req, err := http.NewRequest("GET", fullURL, nil)
 	if err != nil {
@@ -197,14 +247,15 @@ func callJupiterPublicQuoteAPI() (float64, int, error) {
 // OpenOcean API (Multi-chain, FREE)
 // ============================================================================
 
-func callOpenOceanQuoteAPI(chain QuoteChainConfig) (float64, int, error) {
-	endpoint := fmt.Sprintf("%s/%s/quote", openOceanQuoteURL, chain.OpenOceanChain)
+func callOpenOceanQuoteAPI(chain QuoteChainConfig, slippageBps map[string]int) (float64, int, error) {
+	endpoint := fmt.Sprintf("%s/%s/quote", providerURLs.OpenOceanQuote, chain.OpenOceanChain)
 
 	params := url.Values{}
 	params.Add("inTokenAddress", chain.TokenIn)
 	params.Add("outTokenAddress", chain.TokenOut)
 	params.Add("amount", chain.Amount)
 	params.Add("gasPrice", "5")
+	addQuoteSlippage(params, "openocean", slippageBps)
 
 	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
 
@@ -241,7 +292,7 @@ func callParaSwapQuoteAPI(chain QuoteChainConfig) (float64, int, error) {
 	params.Add("destDecimals", "18") // Native tokens are 18 decimals
 	params.Add("network", chain.ChainID)
 
-	fullURL := fmt.Sprintf("%s?%s", paraSwapQuoteURL, params.Encode())
+	fullURL := fmt.Sprintf("%s?%s", providerURLs.ParaSwapQuote, params.Encode())
 
 	req, err := http.NewRequest("GET", fullURL, nil)
 	if err != nil {
@@ -267,7 +318,7 @@ func callParaSwapQuoteAPI(chain QuoteChainConfig) (float64, int, error) {
 // Li.Fi API (Multi-chain, FREE)
 // ============================================================================
 
-func callLifiQuoteAPI(chain QuoteChainConfig) (float64, int, error) {
+func callLifiQuoteAPI(chain QuoteChainConfig, slippageBps map[string]int) (float64, int, error) {
 	params := url.Values{}
 	params.Add("fromChain", chain.ChainID)
 	params.Add("toChain", chain.ChainID) // Same chain swap
@@ -275,8 +326,9 @@ func callLifiQuoteAPI(chain QuoteChainConfig) (float64, int, error) {
 	params.Add("toToken", chain.TokenOut)
 	params.Add("fromAmount", chain.Amount)
 	params.Add("fromAddress", dummyWalletAddressEVM) // Required by Li.Fi
+	addQuoteSlippage(params, "lifi", slippageBps)
 
-	fullURL := fmt.Sprintf("%s?%s", lifiQuoteURL, params.Encode())
+	fullURL := fmt.Sprintf("%s?%s", providerURLs.LifiQuote, params.Encode())
 
 	req, err := http.NewRequest("GET", fullURL, nil)
 	if err != nil {
@@ -303,7 +355,7 @@ func callLifiQuoteAPI(chain QuoteChainConfig) (float64, int, error) {
 // ============================================================================
 
 func callKyberSwapQuoteAPI(chain QuoteChainConfig) (float64, int, error) {
-	endpoint := fmt.Sprintf("%s/%s/api/v1/routes", kyberSwapQuoteURL, chain.KyberChainKey)
+	endpoint := fmt.Sprintf("%s/%s/api/v1/routes", providerURLs.KyberSwapQuote, chain.KyberChainKey)
 
 	params := url.Values{}
 	params.Add("tokenIn", chain.TokenIn)
@@ -337,43 +389,46 @@ func callKyberSwapQuoteAPI(chain QuoteChainConfig) (float64, int, error) {
 // Main monitoring function
 // ============================================================================
 
-func performQuoteAPIChecks(config *Config) {
+func performQuoteAPIChecks(config *Config, rec Recorder) {
 	timestamp := time.Now().UTC().Format("2006-01-02 15:04:05")
 
 	fmt.Printf("\n[QUOTE-API][%s] === Starting quote API latency checks ===\n", timestamp)
 
+	// check calls one provider on one chain unless PROVIDER_CHAINS disables it, and records the outcome
+	check := func(provider string, chain string, call func() (float64, int, error)) {
+		if !chainEnabled(provider, chain) {
+			return
+		}
+		latencyMs, statusCode, err := call()
+		if err != nil || statusCode >= 400 {
+			rec.RecordQuoteAPIError(provider, chain, getErrorType(statusCode), config.MonitorRegion)
+		} else {
+			rec.RecordQuoteAPILatency(provider, chain, latencyMs, statusCode, config.MonitorRegion)
+			rec.RecordMonitorSuccess(provider, "quote", config.MonitorRegion)
+		}
+		fmt.Printf("[QUOTE-API][%s][%s][%s] %s | Latency: %.0fms | Status: %d\n",
+			timestamp, provider, chain, getStatusEmoji(statusCode), latencyMs, statusCode)
+	}
+
 	// ========== SOLANA QUOTES ==========
 
 	// Mobula (Solana)
-	latencyMs, statusCode, err := callMobulaSwapQuoteAPI(
-		"solana",
-		"solana",
-		solanaConfig.TokenIn,
-		solanaConfig.TokenOut,
-		"100", // 100 USDC
-		config.MobulaAPIKey,
-	)
-	if err != nil || statusCode >= 400 {
-		RecordQuoteAPIError("mobula", "solana", getErrorType(statusCode), config.MonitorRegion)
-		fmt.Printf("[QUOTE-API][%s][mobula][solana] %s | Latency: %.0fms | Status: %d\n",
-			timestamp, getStatusEmoji(statusCode), latencyMs, statusCode)
-	} else {
-		RecordQuoteAPILatency("mobula", "solana", latencyMs, statusCode, config.MonitorRegion)
-		fmt.Printf("[QUOTE-API][%s][mobula][solana] %s | Latency: %.0fms | Status: %d\n",
-			timestamp, getStatusEmoji(statusCode), latencyMs, statusCode)
-	}
+	check("mobula", "solana", func() (float64, int, error) {
+		return callMobulaSwapQuoteAPI(
+			"solana",
+			"solana",
+			solanaConfig.TokenIn,
+			solanaConfig.TokenOut,
+			"100", // 100 USDC
+			config.MobulaAPIKey,
+			config.QuoteSlippageBps,
+		)
+	})
 
 	// Jupiter (Solana only - FREE public API)
-	latencyMs, statusCode, err = callJupiterPublicQuoteAPI()
-	if err != nil || statusCode >= 400 {
-		RecordQuoteAPIError("jupiter", "solana", getErrorType(statusCode), config.MonitorRegion)
-		fmt.Printf("[QUOTE-API][%s][jupiter][solana] %s | Latency: %.0fms | Status: %d\n",
-			timestamp, getStatusEmoji(statusCode), latencyMs, statusCode)
-	} else {
-		RecordQuoteAPILatency("jupiter", "solana", latencyMs, statusCode, config.MonitorRegion)
-		fmt.Printf("[QUOTE-API][%s][jupiter][solana] %s | Latency: %.0fms | Status: %d\n",
-			timestamp, getStatusEmoji(statusCode), latencyMs, statusCode)
-	}
+	check("jupiter", "solana", func() (float64, int, error) {
+		return callJupiterPublicQuoteAPI(config.QuoteSlippageBps)
+	})
 
 	// ========== EVM QUOTES ==========
 
@@ -381,72 +436,38 @@ func performQuoteAPIChecks(config *Config) {
 	for _, chain := range evmQuoteChains {
 		// Mobula (Base + Arbitrum - chains where MobulaRouter is deployed)
 		if chain.Name == "base" || chain.Name == "arbitrum" {
-			latencyMs, statusCode, err := callMobulaSwapQuoteAPI(
-				"evm:"+chain.ChainID,
-				chain.Name,
-				chain.TokenIn,
-				chain.TokenOut,
-				"100", // 100 USDC
-				config.MobulaAPIKey,
-			)
-			if err != nil || statusCode >= 400 {
-				RecordQuoteAPIError("mobula", chain.Name, getErrorType(statusCode), config.MonitorRegion)
-				fmt.Printf("[QUOTE-API][%s][mobula][%s] %s | Latency: %.0fms | Status: %d\n",
-					timestamp, chain.Name, getStatusEmoji(statusCode), latencyMs, statusCode)
-			} else {
-				RecordQuoteAPILatency("mobula", chain.Name, latencyMs, statusCode, config.MonitorRegion)
-				fmt.Printf("[QUOTE-API][%s][mobula][%s] %s | Latency: %.0fms | Status: %d\n",
-					timestamp, chain.Name, getStatusEmoji(statusCode), latencyMs, statusCode)
-			}
+			check("mobula", chain.Name, func() (float64, int, error) {
+				return callMobulaSwapQuoteAPI(
+					"evm:"+chain.ChainID,
+					chain.Name,
+					chain.TokenIn,
+					chain.TokenOut,
+					"100", // 100 USDC
+					config.MobulaAPIKey,
+					config.QuoteSlippageBps,
+				)
+			})
 		}
 
 		// OpenOcean (FREE)
-		latencyMs, statusCode, err := callOpenOceanQuoteAPI(chain)
-		if err != nil || statusCode >= 400 {
-			RecordQuoteAPIError("openocean", chain.Name, getErrorType(statusCode), config.MonitorRegion)
-			fmt.Printf("[QUOTE-API][%s][openocean][%s] %s | Latency: %.0fms | Status: %d\n",
-				timestamp, chain.Name, getStatusEmoji(statusCode), latencyMs, statusCode)
-		} else {
-			RecordQuoteAPILatency("openocean", chain.Name, latencyMs, statusCode, config.MonitorRegion)
-			fmt.Printf("[QUOTE-API][%s][openocean][%s] %s | Latency: %.0fms | Status: %d\n",
-				timestamp, chain.Name, getStatusEmoji(statusCode), latencyMs, statusCode)
-		}
+		check("openocean", chain.Name, func() (float64, int, error) {
+			return callOpenOceanQuoteAPI(chain, config.QuoteSlippageBps)
+		})
 
 		// ParaSwap (FREE)
-		latencyMs, statusCode, err = callParaSwapQuoteAPI(chain)
-		if err != nil || statusCode >= 400 {
-			RecordQuoteAPIError("paraswap", chain.Name, getErrorType(statusCode), config.MonitorRegion)
-			fmt.Printf("[QUOTE-API][%s][paraswap][%s] %s | Latency: %.0fms | Status: %d\n",
-				timestamp, chain.Name, getStatusEmoji(statusCode), latencyMs, statusCode)
-		} else {
-			RecordQuoteAPILatency("paraswap", chain.Name, latencyMs, statusCode, config.MonitorRegion)
-			fmt.Printf("[QUOTE-API][%s][paraswap][%s] %s | Latency: %.0fms | Status: %d\n",
-				timestamp, chain.Name, getStatusEmoji(statusCode), latencyMs, statusCode)
-		}
+		check("paraswap", chain.Name, func() (float64, int, error) {
+			return callParaSwapQuoteAPI(chain)
+		})
 
 		// Li.Fi (FREE)
-		latencyMs, statusCode, err = callLifiQuoteAPI(chain)
-		if err != nil || statusCode >= 400 {
-			RecordQuoteAPIError("lifi", chain.Name, getErrorType(statusCode), config.MonitorRegion)
-			fmt.Printf("[QUOTE-API][%s][lifi][%s] %s | Latency: %.0fms | Status: %d\n",
-				timestamp, chain.Name, getStatusEmoji(statusCode), latencyMs, statusCode)
-		} else {
-			RecordQuoteAPILatency("lifi", chain.Name, latencyMs, statusCode, config.MonitorRegion)
-			fmt.Printf("[QUOTE-API][%s][lifi][%s] %s | Latency: %.0fms | Status: %d\n",
-				timestamp, chain.Name, getStatusEmoji(statusCode), latencyMs, statusCode)
-		}
+		check("lifi", chain.Name, func() (float64, int, error) {
+			return callLifiQuoteAPI(chain, config.QuoteSlippageBps)
+		})
 
 		// KyberSwap (FREE)
-		latencyMs, statusCode, err = callKyberSwapQuoteAPI(chain)
-		if err != nil || statusCode >= 400 {
-			RecordQuoteAPIError("kyberswap", chain.Name, getErrorType(statusCode), config.MonitorRegion)
-			fmt.Printf("[QUOTE-API][%s][kyberswap][%s] %s | Latency: %.0fms | Status: %d\n",
-				timestamp, chain.Name, getStatusEmoji(statusCode), latencyMs, statusCode)
-		} else {
-			RecordQuoteAPILatency("kyberswap", chain.Name, latencyMs, statusCode, config.MonitorRegion)
-			fmt.Printf("[QUOTE-API][%s][kyberswap][%s] %s | Latency: %.0fms | Status: %d\n",
-				timestamp, chain.Name, getStatusEmoji(statusCode), latencyMs, statusCode)
-		}
+		check("kyberswap", chain.Name, func() (float64, int, error) {
+			return callKyberSwapQuoteAPI(chain)
+		})
 	}
 
 	// Jupiter (Solana) - Requires API key, skip if not available
@@ -454,6 +475,11 @@ func performQuoteAPIChecks(config *Config) {
 	// latencyMs, statusCode, err := callJupiterQuoteAPI("")
 	// ...
 
+	// Price impact across sizes, only with QUOTE_IMPACT_SIZES
+	if len(config.QuoteImpactSizes) > 0 {
+		performQuoteImpactChecks(config, rec)
+	}
+
 	fmt.Printf("[QUOTE-API][%s] === Quote API checks completed ===\n\n", timestamp)
 }
 
@@ -478,31 +504,36 @@ func getStatusEmoji(statusCode int) string {
 }
 
 // runQuoteAPIMonitor starts the quote API latency monitoring
-func runQuoteAPIMonitor(config *Config, stopChan <-chan struct{}) {
+func runQuoteAPIMonitor(config *Config, rec Recorder, stopChan <-chan struct{}) {
+	// Quote APIs are called with or without a key, so none of them is ever skipped
+	for _, provider := range []string{"mobula", "jupiter", "openocean", "paraswap", "lifi", "kyberswap"} {
+		rec.RecordProviderStatus(provider, "quote", "", config.MonitorRegion)
+	}
+
 	fmt.Println("Starting Quote API Latency Monitor...")
 	fmt.Println("   Comparing: Mobula, Jupiter, OpenOcean, ParaSwap, Li.Fi, KyberSwap")
 	fmt.Println("   Mobula: Solana + Base + Arbitrum")
 	fmt.Println("   Jupiter: Solana")
-	fmt.Println("   Others: Ethereum, Base, BNB, Arbitrum")
-	fmt.Println("   Test: 100 USDC → Native token quote")
-	fmt.Println("   Interval: 30 seconds")
+	fmt.Println("   Others: Ethereum, Base, BNB, Arbitrum, Polygon, Avalanche, Optimism")
+	fmt.Println(decorated("   Test: 100 USDC → Native token quote"))
+	fmt.Printf("   Interval: %v\n", currentQuoteInterval())
 	fmt.Println()
 
-	// Create ticker for 30 second intervals
-	ticker := time.NewTicker(30 * time.Second)
+	ticker := time.NewTicker(currentQuoteInterval())
 	defer ticker.Stop()
 
 	// Run once immediately
-	performQuoteAPIChecks(config)
+	performQuoteAPIChecks(config, rec)
 
-	// Then run every 30 seconds
+	// Then run on every tick, picking up interval changes from SIGHUP reloads
 	for {
 		select {
 		case <-stopChan:
 			fmt.Println("Quote API monitor stopped")
 			return
 		case <-ticker.C:
-			performQuoteAPIChecks(config)
+			performQuoteAPIChecks(config, rec)
+			ticker.Reset(currentQuoteInterval())
 		}
 	}
 }
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// Quote Price Impact
+// QUOTE_IMPACT_SIZES: quotes every provider at several notional sizes and
+// records how much worse the rate gets as the size grows, per provider and chain
+// ============================================================================
+
+// quoteOutAmount returns the output amount a provider quotes for sizeUSD worth of the chain's input
+// token (USDC). Units differ between providers, smallest units or whole tokens, but are the same
+// across sizes for one provider, which is all the impact needs.
+type quoteOutAmount func(config *Config, chain QuoteChainConfig, sizeUSD float64) (float64, error)
+
+// quoteImpactProviders parses each provider's output amount from the same request as its latency check
+var quoteImpactProviders = map[string]quoteOutAmount{
+	"mobula":    mobulaQuoteOutAmount,
+	"jupiter":   jupiterQuoteOutAmount,
+	"openocean": openOceanQuoteOutAmount,
+	"paraswap":  paraSwapQuoteOutAmount,
+	"lifi":      lifiQuoteOutAmount,
+	"kyberswap": kyberSwapQuoteOutAmount,
+}
+
+// formatSizeUSD is the size_usd label of a notional size
+func formatSizeUSD(sizeUSD float64) string {
+	return strconv.FormatFloat(sizeUSD, 'f', -1, 64)
+}
+
+// quoteAmountUnits converts a USD size to the input token's smallest unit, the input being USDC
+func quoteAmountUnits(sizeUSD float64, decimals int) string {
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	units, _ := new(big.Float).Mul(big.NewFloat(sizeUSD), scale).Int(nil)
+	return units.String()
+}
+
+// parseQuoteAmount reads an amount sent as a decimal string, which may exceed an int64
+func parseQuoteAmount(raw string) (float64, error) {
+	amount, ok := new(big.Float).SetString(raw)
+	if !ok {
+		return 0, fmt.Errorf("invalid output amount %q", raw)
+	}
+	value, _ := amount.Float64()
+	if value <= 0 {
+		return 0, fmt.Errorf("no output amount")
+	}
+	return value, nil
+}
+
+// getQuoteJSON sends a quote request and decodes the JSON response into out
+func getQuoteJSON(fullURL string, apiKey string, out any) error {
+	req, err := http.NewRequest("GET", fullURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", apiKey)
+	}
+
+	resp, err := quoteHTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+func mobulaQuoteOutAmount(config *Config, chain QuoteChainConfig, sizeUSD float64) (float64, error) {
+	chainID, walletAddress := "evm:"+chain.ChainID, dummyWalletAddressEVM
+	if chain.Name == solanaConfig.Name {
+		chainID, walletAddress = "solana", dummyWalletAddressSolana
+	}
+
+	params := url.Values{}
+	params.Add("chainId", chainID)
+	params.Add("tokenIn", chain.TokenIn)
+	params.Add("tokenOut", chain.TokenOut)
+	params.Add("amount", formatSizeUSD(sizeUSD)) // Whole tokens, unlike the other providers
+	params.Add("walletAddress", walletAddress)
+	addQuoteSlippage(params, "mobula", config.QuoteSlippageBps)
+
+	var result struct {
+		Data struct {
+			AmountOutTokens json.Number `json:"amountOutTokens"`
+		} `json:"data"`
+	}
+	if err := getQuoteJSON(providerURLs.MobulaREST+"/api/2/swap/quoting?"+params.Encode(), config.MobulaAPIKey, &result); err != nil {
+		return 0, err
+	}
+	return parseQuoteAmount(result.Data.AmountOutTokens.String())
+}
+
+func jupiterQuoteOutAmount(config *Config, chain QuoteChainConfig, sizeUSD float64) (float64, error) {
+	params := url.Values{}
+	params.Add("inputMint", chain.TokenIn)
+	params.Add("outputMint", chain.TokenOut)
+	params.Add("amount", quoteAmountUnits(sizeUSD, chain.Decimals))
+	addQuoteSlippage(params, "jupiter", config.QuoteSlippageBps)
+
+	var result struct {
+		OutAmount string `json:"outAmount"`
+	}
+	if err := getQuoteJSON(providerURLs.JupiterQuote+"?"+params.Encode(), "", &result); err != nil {
+		return 0, err
+	}
+	return parseQuoteAmount(result.OutAmount)
+}
+
+func openOceanQuoteOutAmount(config *Config, chain QuoteChainConfig, sizeUSD float64) (float64, error) {
+	params := url.Values{}
+	params.Add("inTokenAddress", chain.TokenIn)
+	params.Add("outTokenAddress", chain.TokenOut)
+	params.Add("amount", quoteAmountUnits(sizeUSD, chain.Decimals))
+	params.Add("gasPrice", "5")
+	addQuoteSlippage(params, "openocean", config.QuoteSlippageBps)
+
+	var result struct {
+		Data struct {
+			OutAmount string `json:"outAmount"`
+		} `json:"data"`
+	}
+	endpoint := fmt.Sprintf("%s/%s/quote", providerURLs.OpenOceanQuote, chain.OpenOceanChain)
+	if err := getQuoteJSON(endpoint+"?"+params.Encode(), "", &result); err != nil {
+		return 0, err
+	}
+	return parseQuoteAmount(result.Data.OutAmount)
+}
+
+func paraSwapQuoteOutAmount(config *Config, chain QuoteChainConfig, sizeUSD float64) (float64, error) {
+	params := url.Values{}
+	params.Add("srcToken", chain.TokenIn)
+	params.Add("destToken", chain.TokenOut)
+	params.Add("amount", quoteAmountUnits(sizeUSD, chain.Decimals))
+	params.Add("srcDecimals", strconv.Itoa(chain.Decimals))
+	params.Add("destDecimals", "18")
+	params.Add("network", chain.ChainID)
+
+	var result struct {
+		PriceRoute struct {
+			DestAmount string `json:"destAmount"`
+		} `json:"priceRoute"`
+	}
+	if err := getQuoteJSON(providerURLs.ParaSwapQuote+"?"+params.Encode(), "", &result); err != nil {
+		return 0, err
+	}
+	return parseQuoteAmount(result.PriceRoute.DestAmount)
+}
+
+func lifiQuoteOutAmount(config *Config, chain QuoteChainConfig, sizeUSD float64) (float64, error) {
+	params := url.Values{}
+	params.Add("fromChain", chain.ChainID)
+	params.Add("toChain", chain.ChainID)
+	params.Add("fromToken", chain.TokenIn)
+	params.Add("toToken", chain.TokenOut)
+	params.Add("fromAmount", quoteAmountUnits(sizeUSD, chain.Decimals))
+	params.Add("fromAddress", dummyWalletAddressEVM)
+	addQuoteSlippage(params, "lifi", config.QuoteSlippageBps)
+
+	var result struct {
+		Estimate struct {
+			ToAmount string `json:"toAmount"`
+		} `json:"estimate"`
+	}
+	if err := getQuoteJSON(providerURLs.LifiQuote+"?"+params.Encode(), "", &result); err != nil {
+		return 0, err
+	}
+	return parseQuoteAmount(result.Estimate.ToAmount)
+}
+
+func kyberSwapQuoteOutAmount(config *Config, chain QuoteChainConfig, sizeUSD float64) (float64, error) {
+	params := url.Values{}
+	params.Add("tokenIn", chain.TokenIn)
+	params.Add("tokenOut", chain.TokenOut)
+	params.Add("amountIn", quoteAmountUnits(sizeUSD, chain.Decimals))
+
+	var result struct {
+		Data struct {
+			RouteSummary struct {
+				AmountOut string `json:"amountOut"`
+			} `json:"routeSummary"`
+		} `json:"data"`
+	}
+	endpoint := fmt.Sprintf("%s/%s/api/v1/routes", providerURLs.KyberSwapQuote, chain.KyberChainKey)
+	if err := getQuoteJSON(endpoint+"?"+params.Encode(), "", &result); err != nil {
+		return 0, err
+	}
+	return parseQuoteAmount(result.Data.RouteSummary.AmountOut)
+}
+
+// quoteImpactPair is one provider quoted on one chain
+type quoteImpactPair struct {
+	provider string
+	chain    QuoteChainConfig
+}
+
+// quoteImpactPairs lists the provider/chain pairs the latency checks quote, see performQuoteAPIChecks
+func quoteImpactPairs() []quoteImpactPair {
+	pairs := []quoteImpactPair{{"mobula", solanaConfig}, {"jupiter", solanaConfig}}
+	for _, chain := range evmQuoteChains {
+		if chain.Name == "base" || chain.Name == "arbitrum" {
+			pairs = append(pairs, quoteImpactPair{"mobula", chain})
+		}
+		for _, provider := range []string{"openocean", "paraswap", "lifi", "kyberswap"} {
+			pairs = append(pairs, quoteImpactPair{provider, chain})
+		}
+	}
+	return pairs
+}
+
+// performQuoteImpactChecks quotes each provider at every QUOTE_IMPACT_SIZES size, smallest first, and
+// records each larger size's price impact: how much less output per dollar it gets than the smallest.
+// A provider whose smallest quote fails is skipped on that chain for this cycle.
+func performQuoteImpactChecks(config *Config, rec Recorder) {
+	timestamp := time.Now().UTC().Format("2006-01-02 15:04:05")
+	sizes := config.QuoteImpactSizes
+
+	for _, pair := range quoteImpactPairs() {
+		if !chainEnabled(pair.provider, pair.chain.Name) {
+			continue
+		}
+		quote := quoteImpactProviders[pair.provider]
+
+		baseOut, err := quote(config, pair.chain, sizes[0])
+		if err != nil {
+			fmt.Printf("[QUOTE-IMPACT][%s][%s][%s] $%s base quote failed: %v\n",
+				timestamp, pair.provider, pair.chain.Name, formatSizeUSD(sizes[0]), err)
+			continue
+		}
+		baseRate := baseOut / sizes[0]
+
+		for _, size := range sizes[1:] {
+			out, err := quote(config, pair.chain, size)
+			if err != nil {
+				// Large sizes are the ones providers tend to reject, so this is expected now and then
+				fmt.Printf("[QUOTE-IMPACT][%s][%s][%s] $%s quote failed: %v\n",
+					timestamp, pair.provider, pair.chain.Name, formatSizeUSD(size), err)
+				continue
+			}
+			impactPercent := (1 - (out/size)/baseRate) * 100
+			rec.RecordQuotePriceImpact(pair.provider, pair.chain.Name, formatSizeUSD(size), impactPercent, config.MonitorRegion)
+			fmt.Printf("[QUOTE-IMPACT][%s][%s][%s] $%s | Impact vs $%s: %.3f%%\n",
+				timestamp, pair.provider, pair.chain.Name, formatSizeUSD(size), formatSizeUSD(sizes[0]), impactPercent)
+		}
+	}
+}
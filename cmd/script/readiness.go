@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Readiness
+// Serves /readyz from the last time each running monitor received data, so a
+// process whose feeds are open but silent does not count as ready
+// ============================================================================
+
+// monitorActivity is one monitor RecordProviderStatus reported as running
+type monitorActivity struct {
+	provider    string
+	monitor     string
+	since       time.Time // When the monitor started
+	lastSuccess time.Time // Zero until the monitor receives data
+}
+
+var monitorHealth = struct {
+	sync.Mutex
+	monitors map[string]*monitorActivity // provider/monitor -> activity
+}{
+	monitors: make(map[string]*monitorActivity),
+}
+
+func monitorKey(provider string, monitor string) string {
+	return provider + "/" + monitor
+}
+
+// registerMonitor starts tracking a running monitor; registering it again keeps its last success
+func registerMonitor(provider string, monitor string, now time.Time) {
+	monitorHealth.Lock()
+	defer monitorHealth.Unlock()
+	key := monitorKey(provider, monitor)
+	if _, ok := monitorHealth.monitors[key]; !ok {
+		monitorHealth.monitors[key] = &monitorActivity{provider: provider, monitor: monitor, since: now}
+	}
+}
+
+// markMonitorSuccess records that a monitor received data; monitors never registered are ignored
+func markMonitorSuccess(provider string, monitor string, at time.Time) {
+	monitorHealth.Lock()
+	defer monitorHealth.Unlock()
+	if activity, ok := monitorHealth.monitors[monitorKey(provider, monitor)]; ok {
+		activity.lastSuccess = at
+	}
+}
+
+// MonitorReadiness is a running monitor's state at one point in time
+type MonitorReadiness struct {
+	Provider    string
+	Monitor     string
+	LastSuccess time.Time // Zero if the monitor has not received data yet
+	Ready       bool      // Received data within READY_MAX_AGE
+}
+
+// monitorReadinessSnapshot returns every running monitor, sorted by provider then monitor
+func monitorReadinessSnapshot(now time.Time, maxAge time.Duration) []MonitorReadiness {
+	monitorHealth.Lock()
+	defer monitorHealth.Unlock()
+
+	snapshot := make([]MonitorReadiness, 0, len(monitorHealth.monitors))
+	for _, activity := range monitorHealth.monitors {
+		snapshot = append(snapshot, MonitorReadiness{
+			Provider:    activity.provider,
+			Monitor:     activity.monitor,
+			LastSuccess: activity.lastSuccess,
+			Ready:       !activity.lastSuccess.IsZero() && now.Sub(activity.lastSuccess) <= maxAge,
+		})
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Provider != snapshot[j].Provider {
+			return snapshot[i].Provider < snapshot[j].Provider
+		}
+		return snapshot[i].Monitor < snapshot[j].Monitor
+	})
+	return snapshot
+}
+
+// readinessHandler answers 200 once at least quorum (a share in (0, 1]) of the running monitors
+// received data within maxAge, 503 otherwise or while no monitor runs. The body lists every monitor.
+func readinessHandler(maxAge time.Duration, quorum float64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		monitors := monitorReadinessSnapshot(now, maxAge)
+
+		ready := 0
+		var lines []string
+		for _, m := range monitors {
+			last := "never"
+			if !m.LastSuccess.IsZero() {
+				last = now.Sub(m.LastSuccess).Round(time.Second).String() + " ago"
+			}
+			status := "stale"
+			if m.Ready {
+				status = "ok"
+				ready++
+			}
+			lines = append(lines, fmt.Sprintf("%s/%s: %s (last data %s)", m.Provider, m.Monitor, status, last))
+		}
+
+		if len(monitors) == 0 || float64(ready) < quorum*float64(len(monitors)) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		fmt.Fprintf(w, "%d/%d monitors received data within %v\n", ready, len(monitors), maxAge)
+		if len(lines) > 0 {
+			fmt.Fprintln(w, strings.Join(lines, "\n"))
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// A REST monitor only counts towards /readyz once a call returns data: an HTTP error status,
+// such as a rejected API key, must leave it not ready
+func TestRESTErrorStatusNotReady(t *testing.T) {
+	pool := MonitoredPool{Name: "ETH/USDC", Address: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", ChainInfo: ethereumChain}
+	monitors := []struct {
+		name  string
+		url   *string
+		check func(rec Recorder)
+	}{
+		{"mobula", &providerURLs.MobulaREST, func(rec Recorder) {
+			checkMobulaEndpoint(&Config{MobulaAPIKey: "key", MonitorRegion: "eu"}, rec, nil, "pair", pool, "00:00:00")
+		}},
+		{"goldrush", &providerURLs.GoldRushREST, func(rec Recorder) {
+			checkGoldRushEndpoint(&Config{GoldRushAPIKey: "key", MonitorRegion: "eu"}, rec, nil, "balances", pool, "00:00:00")
+		}},
+		{"codex", &providerURLs.CodexGraphQL, func(rec Recorder) {
+			checkCodexEndpoint(&Config{MonitorRegion: "eu"}, rec, nil, "graphql", "jwt", pool, "00:00:00")
+		}},
+	}
+	statuses := []struct {
+		status    int
+		wantReady bool
+	}{
+		{http.StatusOK, true},
+		{http.StatusUnauthorized, false},
+		{http.StatusForbidden, false},
+		{http.StatusTooManyRequests, false},
+		{http.StatusInternalServerError, false},
+	}
+	for _, monitor := range monitors {
+		for _, tt := range statuses {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"data":{}}`)
+			}))
+			withProviderURL(t, monitor.url, server.URL)
+
+			rec := &fakeRecorder{}
+			monitor.check(rec)
+			server.Close()
+
+			if ready := len(rec.calls("RecordMonitorSuccess")) > 0; ready != tt.wantReady {
+				t.Errorf("%s, HTTP %d: marked ready = %v, want %v", monitor.name, tt.status, ready, tt.wantReady)
+			}
+		}
+	}
+}
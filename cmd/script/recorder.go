@@ -0,0 +1,297 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Recorder is the sink for every measurement the monitors produce.
+// Handlers receive one instead of calling the package-level Record* functions
+// directly, so tests can swap in a fake and assert on the exact calls.
+type Recorder interface {
+	RecordPoolDiscoveryLatency(aggregator string, chain string, launchpad string, latencyMs float64, region string)
+	RecordPoolDiscoveryError(aggregator string, errorType string, region string)
+	RecordRESTLatency(aggregator string, endpoint string, chain string, latencyMs float64, statusCode int, region string)
+	RecordRESTError(aggregator string, endpoint string, chain string, errorType string, region string)
+	RecordQuoteAPILatency(provider string, chain string, latencyMs float64, statusCode int, region string)
+	RecordQuoteAPIError(provider string, chain string, errorType string, region string)
+	RecordQuotePriceImpact(provider string, chain string, sizeUSD string, impactPercent float64, region string)
+	RecordMetadataCoverage(provider string, chain string, field string, present bool, region string)
+	RecordMetadataLatency(provider string, chain string, latencyMs float64, region string)
+	RecordMetadataCheckResult(provider string, chain string, result string, region string)
+	RecordHeadLag(aggregator string, chain string, lagBlocks int64, lagSeconds float64, region string)
+	RecordBlockchainHead(chain string, blockNumber int64, region string)
+	RecordAggregatorHead(aggregator string, chain string, blockNumber int64, region string)
+	RecordHeadLagError(aggregator string, chain string, errorType string, region string)
+	RecordCodexBlockNumber(chain string, blockNumber int64, region string)
+	RecordEventLag(aggregator string, chain string, eventType string, lagMs float64, region string)
+	RecordTrade(trade NormalizedTrade)
+	RecordMessageReceived(aggregator string, region string)
+	RecordWebSocketError(aggregator string, errorType string, region string)
+	RecordWebSocketWriteError(aggregator string, op string, region string)
+	RecordBackoff(provider string, seconds float64, region string)
+	RecordProviderStatus(provider string, monitor string, skipReason string, region string)
+	RecordMonitorSuccess(provider string, monitor string, region string)
+	RecordLaunchpadDiscovery(aggregator string, chain string, tokenAddress string, seenAt time.Time, region string)
+	RecordTransportLag(aggregator string, chain string, lagMs float64, region string)
+	RecordSubscriptionError(aggregator string, chain string, pool string, region string)
+	RecordPoolsSubscribed(aggregator string, pools []MonitoredPool, region string)
+	RecordRESTDataQuality(aggregator string, endpoint string, chain string, freshnessSeconds float64, completeness float64, region string)
+	RecordClockAnomaly(aggregator string, endpoint string, chain string, region string)
+	RecordCEXReferenceLatency(exchange string, symbol string, latencyMs float64, region string)
+	RecordParseFailure(provider string, region string)
+	RecordMissingTimestamp(provider string, region string)
+	RecordTradeBelowMinVolume(aggregator string, chain string, region string)
+}
+
+// Reasons a monitor is skipped, reported by RecordProviderStatus
+const (
+	skipReasonMissingMobulaAPIKey  = "missing_mobula_api_key"
+	skipReasonMissingDefinedCookie = "missing_defined_session_cookie"
+	skipReasonMissingGoldRushKey   = "missing_goldrush_api_key"
+	skipReasonMissingSyveKey       = "missing_syve_api_key"
+	skipReasonNoSupportedChain     = "no_supported_chain"
+)
+
+// PrometheusRecorder is the default Recorder, backed by the global Prometheus vectors in metrics.go.
+// Trades recorded through it are also forwarded to every registered Sink.
+type PrometheusRecorder struct {
+	sinks []Sink
+
+	// Smoothed head lag per aggregator/chain/region, see RecordTrade
+	ewmaAlpha float64
+	ewmaMu    sync.Mutex
+	ewma      map[string]float64
+
+	// New launchpad tokens matched across providers, see RecordLaunchpadDiscovery
+	launchpads *launchpadRace
+
+	// Also record head lag by trade direction, see TRADE_TYPE_METRICS
+	tradeTypeMetrics bool
+
+	// Also record head lag at full precision, see PRECISE_LAG_METRICS
+	preciseLagMetrics bool
+}
+
+// NewPrometheusRecorder returns the Recorder used in production.
+// ewmaAlpha is the weight of each new trade in the smoothed head lag, in (0, 1].
+// tradeTypeMetrics enables head_lag_by_trade_type_milliseconds, preciseLagMetrics head_lag_duration_seconds.
+func NewPrometheusRecorder(ewmaAlpha float64, tradeTypeMetrics bool, preciseLagMetrics bool) *PrometheusRecorder {
+	return &PrometheusRecorder{
+		ewmaAlpha:         ewmaAlpha,
+		ewma:              make(map[string]float64),
+		launchpads:        newLaunchpadRace(),
+		tradeTypeMetrics:  tradeTypeMetrics,
+		preciseLagMetrics: preciseLagMetrics,
+	}
+}
+
+// AddSink registers a sink for measured trades. It must be called before the monitors start.
+func (r *PrometheusRecorder) AddSink(sink Sink) {
+	r.sinks = append(r.sinks, sink)
+}
+
+// Close flushes and closes all registered sinks
+func (r *PrometheusRecorder) Close() {
+	for _, sink := range r.sinks {
+		if err := sink.Close(); err != nil {
+			fmt.Printf("Sink close error: %v\n", err)
+		}
+	}
+}
+
+func (r *PrometheusRecorder) RecordPoolDiscoveryLatency(aggregator string, chain string, launchpad string, latencyMs float64, region string) {
+	RecordPoolDiscoveryLatency(aggregator, chain, launchpad, latencyMs, region)
+}
+
+func (r *PrometheusRecorder) RecordPoolDiscoveryError(aggregator string, errorType string, region string) {
+	RecordPoolDiscoveryError(aggregator, errorType, region)
+}
+
+func (r *PrometheusRecorder) RecordRESTLatency(aggregator string, endpoint string, chain string, latencyMs float64, statusCode int, region string) {
+	RecordRESTLatency(aggregator, endpoint, chain, latencyMs, statusCode, region)
+}
+
+func (r *PrometheusRecorder) RecordRESTError(aggregator string, endpoint string, chain string, errorType string, region string) {
+	RecordRESTError(aggregator, endpoint, chain, errorType, region)
+}
+
+func (r *PrometheusRecorder) RecordQuoteAPILatency(provider string, chain string, latencyMs float64, statusCode int, region string) {
+	RecordQuoteAPILatency(provider, chain, latencyMs, statusCode, region)
+}
+
+func (r *PrometheusRecorder) RecordQuotePriceImpact(provider string, chain string, sizeUSD string, impactPercent float64, region string) {
+	RecordQuotePriceImpact(provider, chain, sizeUSD, impactPercent, region)
+}
+
+func (r *PrometheusRecorder) RecordQuoteAPIError(provider string, chain string, errorType string, region string) {
+	RecordQuoteAPIError(provider, chain, errorType, region)
+}
+
+func (r *PrometheusRecorder) RecordMetadataCoverage(provider string, chain string, field string, present bool, region string) {
+	RecordMetadataCoverage(provider, chain, field, present, region)
+}
+
+func (r *PrometheusRecorder) RecordMetadataLatency(provider string, chain string, latencyMs float64, region string) {
+	RecordMetadataLatency(provider, chain, latencyMs, region)
+}
+
+func (r *PrometheusRecorder) RecordMetadataCheckResult(provider string, chain string, result string, region string) {
+	RecordMetadataCheckResult(provider, chain, result, region)
+}
+
+func (r *PrometheusRecorder) RecordHeadLag(aggregator string, chain string, lagBlocks int64, lagSeconds float64, region string) {
+	RecordHeadLag(aggregator, chain, "", lagBlocks, lagSeconds, region)
+}
+
+func (r *PrometheusRecorder) RecordBlockchainHead(chain string, blockNumber int64, region string) {
+	RecordBlockchainHead(chain, blockNumber, region)
+}
+
+func (r *PrometheusRecorder) RecordAggregatorHead(aggregator string, chain string, blockNumber int64, region string) {
+	RecordAggregatorHead(aggregator, chain, blockNumber, region)
+}
+
+func (r *PrometheusRecorder) RecordHeadLagError(aggregator string, chain string, errorType string, region string) {
+	RecordHeadLagError(aggregator, chain, errorType, region)
+}
+
+func (r *PrometheusRecorder) RecordCodexBlockNumber(chain string, blockNumber int64, region string) {
+	RecordCodexBlockNumber(chain, blockNumber, region)
+}
+
+// RecordTrade records the head lag of a single trade and publishes it to all sinks.
+// A negative lag is recorded as clock skew and kept out of the head lag metrics;
+// sinks still receive the trade as measured. A pool reported under a POOL_OVERRIDES
+// address is recorded as the registry pool it stands for.
+func (r *PrometheusRecorder) RecordTrade(trade NormalizedTrade) {
+	trade.Pool = canonicalPoolAddress(trade.Provider, trade.Pool)
+	RecordTradeObserved(trade.Provider, trade.Chain, trade.Region)
+	markPoolSeen(trade.Provider, trade.Pool, trade.ReceivedAt)
+	if trade.LagMs < 0 {
+		RecordClockSkew(trade.Provider, trade.Chain, float64(trade.LagMs), trade.Region)
+	} else {
+		RecordHeadLag(trade.Provider, trade.Chain, trade.Confirmation, trade.LagMs, trade.LagSeconds(), trade.Region)
+		RecordHeadLagSample(trade.Provider, trade.Chain, float64(trade.LagMs), trade.Region)
+		RecordHeadLagEWMA(trade.Provider, trade.Chain, r.updateEWMA(trade), trade.Region)
+		if r.preciseLagMetrics {
+			RecordHeadLagDuration(trade.Provider, trade.Chain, trade.LagSeconds(), trade.Region)
+		}
+		if r.tradeTypeMetrics && trade.TradeType != "" {
+			RecordHeadLagByTradeType(trade.Provider, trade.Chain, trade.TradeType, float64(trade.LagMs), trade.Region)
+		}
+	}
+	for _, sink := range r.sinks {
+		sink.Publish(trade)
+	}
+}
+
+// RecordEventLag records the lag of a Codex event by type; it is not a trade and reaches no sink
+func (r *PrometheusRecorder) RecordEventLag(aggregator string, chain string, eventType string, lagMs float64, region string) {
+	RecordEventLag(aggregator, chain, eventType, lagMs, region)
+}
+
+func (r *PrometheusRecorder) RecordMessageReceived(aggregator string, region string) {
+	RecordMessageReceived(aggregator, region)
+}
+
+func (r *PrometheusRecorder) RecordWebSocketError(aggregator string, errorType string, region string) {
+	RecordWebSocketError(aggregator, errorType, region)
+}
+
+func (r *PrometheusRecorder) RecordWebSocketWriteError(aggregator string, op string, region string) {
+	RecordWebSocketWriteError(aggregator, op, region)
+}
+
+func (r *PrometheusRecorder) RecordBackoff(provider string, seconds float64, region string) {
+	RecordBackoff(provider, seconds, region)
+}
+
+// RecordProviderStatus also starts tracking a monitor that runs for /readyz
+func (r *PrometheusRecorder) RecordProviderStatus(provider string, monitor string, skipReason string, region string) {
+	RecordProviderStatus(provider, monitor, skipReason, region)
+	if skipReason == "" {
+		registerMonitor(provider, monitor, time.Now())
+	}
+}
+
+// RecordMonitorSuccess marks a running monitor as having received data, for /readyz.
+// provider and monitor are the ones it reported to RecordProviderStatus.
+func (r *PrometheusRecorder) RecordMonitorSuccess(provider string, monitor string, region string) {
+	now := time.Now()
+	RecordMonitorSuccess(provider, monitor, now, region)
+	markMonitorSuccess(provider, monitor, now)
+}
+
+// RecordLaunchpadDiscovery matches a new launchpad token against the other launchpad feeds.
+// Once a second provider reports the token, the first one is credited with the discovery;
+// every later provider records how far behind the first it was.
+func (r *PrometheusRecorder) RecordLaunchpadDiscovery(aggregator string, chain string, tokenAddress string, seenAt time.Time, region string) {
+	result, ok := r.launchpads.observe(aggregator, chain, tokenAddress, seenAt)
+	if !ok {
+		return
+	}
+	if result.decided {
+		RecordLaunchpadFirstToDiscover(result.winner, chain, region)
+	}
+	RecordLaunchpadDiscoveryDelta(aggregator, chain, float64(result.behind.Milliseconds()), region)
+}
+
+func (r *PrometheusRecorder) RecordTransportLag(aggregator string, chain string, lagMs float64, region string) {
+	RecordTransportLag(aggregator, chain, lagMs, region)
+}
+
+func (r *PrometheusRecorder) RecordSubscriptionError(aggregator string, chain string, pool string, region string) {
+	RecordSubscriptionError(aggregator, chain, pool, region)
+}
+
+// RecordPoolsSubscribed starts tracking the liveness of the pools subscribed on a new connection,
+// dropping what the previous connection saw
+func (r *PrometheusRecorder) RecordPoolsSubscribed(aggregator string, pools []MonitoredPool, region string) {
+	resetPoolLiveness(aggregator, pools, region, time.Now())
+}
+
+func (r *PrometheusRecorder) RecordRESTDataQuality(aggregator string, endpoint string, chain string, freshnessSeconds float64, completeness float64, region string) {
+	RecordRESTDataQuality(aggregator, endpoint, chain, freshnessSeconds, completeness, region)
+	markRESTFreshness(aggregator, endpoint, chain, freshnessSeconds, time.Now())
+}
+
+func (r *PrometheusRecorder) RecordClockAnomaly(aggregator string, endpoint string, chain string, region string) {
+	RecordClockAnomaly(aggregator, endpoint, chain, region)
+}
+
+func (r *PrometheusRecorder) RecordCEXReferenceLatency(exchange string, symbol string, latencyMs float64, region string) {
+	RecordCEXReferenceLatency(exchange, symbol, latencyMs, region)
+}
+
+func (r *PrometheusRecorder) RecordParseFailure(provider string, region string) {
+	RecordParseFailure(provider, region)
+}
+
+func (r *PrometheusRecorder) RecordMissingTimestamp(provider string, region string) {
+	RecordMissingTimestamp(provider, region)
+}
+
+func (r *PrometheusRecorder) RecordTradeBelowMinVolume(aggregator string, chain string, region string) {
+	RecordTradeBelowMinVolume(aggregator, chain, region)
+}
+
+// updateEWMA folds a trade into its series' moving average and returns the new value.
+// The first trade of a series seeds the average.
+func (r *PrometheusRecorder) updateEWMA(trade NormalizedTrade) float64 {
+	r.ewmaMu.Lock()
+	defer r.ewmaMu.Unlock()
+
+	key := trade.Provider + ":" + trade.Chain + ":" + trade.Region
+	lagMs := float64(trade.LagMs)
+
+	value, ok := r.ewma[key]
+	if !ok {
+		value = lagMs
+	} else {
+		value = r.ewmaAlpha*lagMs + (1-r.ewmaAlpha)*value
+	}
+	r.ewma[key] = value
+	return value
+}
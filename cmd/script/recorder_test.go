@@ -0,0 +1,224 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRecorder logs every call as its space-separated arguments, by method, and keeps recorded trades whole
+type fakeRecorder struct {
+	mu     sync.Mutex
+	log    map[string][]string
+	trades []NormalizedTrade
+}
+
+func (f *fakeRecorder) record(method string, args ...interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.log == nil {
+		f.log = make(map[string][]string)
+	}
+	f.log[method] = append(f.log[method], strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+// calls returns the arguments of every call to method, in order
+func (f *fakeRecorder) calls(method string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.log[method]...)
+}
+
+func (f *fakeRecorder) recordedTrades() []NormalizedTrade {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]NormalizedTrade(nil), f.trades...)
+}
+
+func (f *fakeRecorder) RecordPoolDiscoveryLatency(aggregator string, chain string, launchpad string, latencyMs float64, region string) {
+	f.record("RecordPoolDiscoveryLatency", aggregator, chain, launchpad, latencyMs, region)
+}
+
+func (f *fakeRecorder) RecordPoolDiscoveryError(aggregator string, errorType string, region string) {
+	f.record("RecordPoolDiscoveryError", aggregator, errorType, region)
+}
+
+func (f *fakeRecorder) RecordRESTLatency(aggregator string, endpoint string, chain string, latencyMs float64, statusCode int, region string) {
+	f.record("RecordRESTLatency", aggregator, endpoint, chain, statusCode, region)
+}
+
+func (f *fakeRecorder) RecordRESTError(aggregator string, endpoint string, chain string, errorType string, region string) {
+	f.record("RecordRESTError", aggregator, endpoint, chain, errorType, region)
+}
+
+func (f *fakeRecorder) RecordQuoteAPILatency(provider string, chain string, latencyMs float64, statusCode int, region string) {
+	f.record("RecordQuoteAPILatency", provider, chain, statusCode, region)
+}
+
+func (f *fakeRecorder) RecordQuoteAPIError(provider string, chain string, errorType string, region string) {
+	f.record("RecordQuoteAPIError", provider, chain, errorType, region)
+}
+
+func (f *fakeRecorder) RecordQuotePriceImpact(provider string, chain string, sizeUSD string, impactPercent float64, region string) {
+	f.record("RecordQuotePriceImpact", provider, chain, sizeUSD, impactPercent, region)
+}
+
+func (f *fakeRecorder) RecordMetadataCoverage(provider string, chain string, field string, present bool, region string) {
+	f.record("RecordMetadataCoverage", provider, chain, field, present, region)
+}
+
+func (f *fakeRecorder) RecordMetadataLatency(provider string, chain string, latencyMs float64, region string) {
+	f.record("RecordMetadataLatency", provider, chain, region)
+}
+
+func (f *fakeRecorder) RecordMetadataCheckResult(provider string, chain string, result string, region string) {
+	f.record("RecordMetadataCheckResult", provider, chain, result, region)
+}
+
+func (f *fakeRecorder) RecordHeadLag(aggregator string, chain string, lagBlocks int64, lagSeconds float64, region string) {
+	f.record("RecordHeadLag", aggregator, chain, lagBlocks, lagSeconds, region)
+}
+
+func (f *fakeRecorder) RecordBlockchainHead(chain string, blockNumber int64, region string) {
+	f.record("RecordBlockchainHead", chain, blockNumber, region)
+}
+
+func (f *fakeRecorder) RecordAggregatorHead(aggregator string, chain string, blockNumber int64, region string) {
+	f.record("RecordAggregatorHead", aggregator, chain, blockNumber, region)
+}
+
+func (f *fakeRecorder) RecordHeadLagError(aggregator string, chain string, errorType string, region string) {
+	f.record("RecordHeadLagError", aggregator, chain, errorType, region)
+}
+
+func (f *fakeRecorder) RecordCodexBlockNumber(chain string, blockNumber int64, region string) {
+	f.record("RecordCodexBlockNumber", chain, blockNumber, region)
+}
+
+func (f *fakeRecorder) RecordEventLag(aggregator string, chain string, eventType string, lagMs float64, region string) {
+	f.record("RecordEventLag", aggregator, chain, eventType, lagMs, region)
+}
+
+func (f *fakeRecorder) RecordTrade(trade NormalizedTrade) {
+	f.record("RecordTrade", trade.Provider, trade.Chain, trade.Pool, trade.LagMs, trade.Region)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.trades = append(f.trades, trade)
+}
+
+func (f *fakeRecorder) RecordMessageReceived(aggregator string, region string) {
+	f.record("RecordMessageReceived", aggregator, region)
+}
+
+func (f *fakeRecorder) RecordWebSocketError(aggregator string, errorType string, region string) {
+	f.record("RecordWebSocketError", aggregator, errorType, region)
+}
+
+func (f *fakeRecorder) RecordWebSocketWriteError(aggregator string, op string, region string) {
+	f.record("RecordWebSocketWriteError", aggregator, op, region)
+}
+
+func (f *fakeRecorder) RecordBackoff(provider string, seconds float64, region string) {
+	f.record("RecordBackoff", provider, seconds, region)
+}
+
+func (f *fakeRecorder) RecordProviderStatus(provider string, monitor string, skipReason string, region string) {
+	f.record("RecordProviderStatus", provider, monitor, skipReason, region)
+}
+
+func (f *fakeRecorder) RecordMonitorSuccess(provider string, monitor string, region string) {
+	f.record("RecordMonitorSuccess", provider, monitor, region)
+}
+
+func (f *fakeRecorder) RecordLaunchpadDiscovery(aggregator string, chain string, tokenAddress string, seenAt time.Time, region string) {
+	f.record("RecordLaunchpadDiscovery", aggregator, chain, tokenAddress, region)
+}
+
+func (f *fakeRecorder) RecordTransportLag(aggregator string, chain string, lagMs float64, region string) {
+	f.record("RecordTransportLag", aggregator, chain, lagMs, region)
+}
+
+func (f *fakeRecorder) RecordSubscriptionError(aggregator string, chain string, pool string, region string) {
+	f.record("RecordSubscriptionError", aggregator, chain, pool, region)
+}
+
+func (f *fakeRecorder) RecordPoolsSubscribed(aggregator string, pools []MonitoredPool, region string) {
+	f.record("RecordPoolsSubscribed", aggregator, len(pools), region)
+}
+
+func (f *fakeRecorder) RecordRESTDataQuality(aggregator string, endpoint string, chain string, freshnessSeconds float64, completeness float64, region string) {
+	f.record("RecordRESTDataQuality", aggregator, endpoint, chain, completeness, region)
+}
+
+func (f *fakeRecorder) RecordClockAnomaly(aggregator string, endpoint string, chain string, region string) {
+	f.record("RecordClockAnomaly", aggregator, endpoint, chain, region)
+}
+
+func (f *fakeRecorder) RecordCEXReferenceLatency(exchange string, symbol string, latencyMs float64, region string) {
+	f.record("RecordCEXReferenceLatency", exchange, symbol, latencyMs, region)
+}
+
+func (f *fakeRecorder) RecordParseFailure(provider string, region string) {
+	f.record("RecordParseFailure", provider, region)
+}
+
+func (f *fakeRecorder) RecordMissingTimestamp(provider string, region string) {
+	f.record("RecordMissingTimestamp", provider, region)
+}
+
+func (f *fakeRecorder) RecordTradeBelowMinVolume(aggregator string, chain string, region string) {
+	f.record("RecordTradeBelowMinVolume", aggregator, chain, region)
+}
+
+var _ Recorder = (*fakeRecorder)(nil)
+
+// A handler given a fake recorder reports exactly what it measured, with no Prometheus registry involved
+func TestHandlerRecordsThroughInjectedRecorder(t *testing.T) {
+	config := &Config{MonitorRegion: "eu"}
+	onChain := time.UnixMilli(1700000000000)
+	receiveTime := onChain.Add(1500 * time.Millisecond)
+
+	tests := []struct {
+		name   string
+		frame  string
+		method string
+		want   []string
+	}{
+		{
+			name:   "swap",
+			frame:  `{"identifier":"{\"channel\":\"SwapChannel\",\"pool_id\":\"24\"}","message":{"type":"newSwap","data":{"block_timestamp":1700000000000,"tx_hash":"0xabc","ty":"b"}}}`,
+			method: "RecordTrade",
+			want:   []string{"geckoterminal bnb 0x58f876857a02d6762e0101bb5c46a8c1ed44dc16 1500 eu"},
+		},
+		{
+			name:   "swap without timestamp",
+			frame:  `{"identifier":"{\"channel\":\"SwapChannel\",\"pool_id\":\"24\"}","message":{"type":"newSwap","data":{"tx_hash":"0xabc"}}}`,
+			method: "RecordMissingTimestamp",
+			want:   []string{"geckoterminal eu"},
+		},
+		{
+			name:   "garbage",
+			frame:  `not json`,
+			method: "RecordParseFailure",
+			want:   []string{"geckoterminal eu"},
+		},
+	}
+	for _, tt := range tests {
+		rec := &fakeRecorder{}
+		handleGeckoMessage(config, rec, nil, []byte(tt.frame), receiveTime)
+
+		got := rec.calls(tt.method)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: %s calls = %q, want %q", tt.name, tt.method, got, tt.want)
+		}
+		if tt.method != "RecordTrade" && len(rec.recordedTrades()) != 0 {
+			t.Errorf("%s: recorded a trade, want none", tt.name)
+		}
+	}
+}
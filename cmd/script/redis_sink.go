@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ============================================================================
+// Redis Stream Sink
+// XADDs every measured trade to a capped Redis stream for real-time consumers
+// ============================================================================
+
+const redisSinkBufferSize = 1000
+
+type RedisSink struct {
+	client *redis.Client
+	key    string
+	maxLen int64
+
+	queue chan NormalizedTrade
+	done  chan struct{}
+	once  sync.Once
+}
+
+// NewRedisSink parses a redis:// or rediss:// URL and starts the background flusher.
+// The client connects lazily so a Redis outage never blocks startup.
+func NewRedisSink(redisURL string, streamKey string, maxLen int) (*RedisSink, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	options.DialTimeout = 5 * time.Second
+	options.ReadTimeout = 5 * time.Second
+	options.WriteTimeout = 5 * time.Second
+
+	sink := &RedisSink{
+		client: redis.NewClient(options),
+		key:    streamKey,
+		maxLen: int64(maxLen),
+		queue:  make(chan NormalizedTrade, redisSinkBufferSize),
+		done:   make(chan struct{}),
+	}
+
+	go sink.flushLoop()
+
+	return sink, nil
+}
+
+// Publish queues a trade for XADD, dropping it if the buffer is full
+func (s *RedisSink) Publish(trade NormalizedTrade) {
+	select {
+	case s.queue <- trade:
+	default:
+		RecordSinkDropped("redis")
+	}
+}
+
+// Close stops accepting trades, flushes what is buffered and closes the client
+func (s *RedisSink) Close() error {
+	s.once.Do(func() {
+		close(s.queue)
+	})
+	<-s.done
+
+	return s.client.Close()
+}
+
+// Probe PINGs the server
+func (s *RedisSink) Probe() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.client.Ping(ctx).Err()
+}
+
+func (s *RedisSink) flushLoop() {
+	defer close(s.done)
+
+	for trade := range s.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := s.client.XAdd(ctx, s.args(trade)).Err(); err != nil {
+			log.Printf("[REDIS-SINK] XADD failed: %v", err)
+		}
+		cancel()
+	}
+}
+
+// args builds the XADD of one trade, trimming the stream to about maxLen entries
+func (s *RedisSink) args(trade NormalizedTrade) *redis.XAddArgs {
+	return &redis.XAddArgs{
+		Stream: s.key,
+		MaxLen: s.maxLen,
+		Approx: true,
+		Values: []string{
+			"provider", trade.Provider,
+			"chain", trade.Chain,
+			"pool", trade.Pool,
+			"tx_hash", trade.TxHash,
+			"block_number", strconv.FormatInt(trade.BlockNumber, 10),
+			"on_chain_ms", strconv.FormatInt(trade.OnChainTime.UnixMilli(), 10),
+			"received_ms", strconv.FormatInt(trade.ReceivedAt.UnixMilli(), 10),
+			"lag_ms", strconv.FormatInt(trade.LagMs, 10),
+			"region", trade.Region,
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNewRedisSinkURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"redis://localhost:6379/0", false},
+		{"redis://:secret@localhost:6379/2", false},
+		{"rediss://localhost:6380", false},
+		{"http://localhost:6379", true},
+		{"redis://localhost:6379/db", true},
+	}
+	for _, tt := range tests {
+		sink, err := NewRedisSink(tt.url, "latency:measurements", 100)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewRedisSink(%q) error = %v, want error %v", tt.url, err, tt.wantErr)
+		}
+		if sink != nil {
+			sink.Close()
+		}
+	}
+}
+
+func TestRedisSinkPublishDropsWhenFull(t *testing.T) {
+	sink := &RedisSink{queue: make(chan NormalizedTrade, 1)}
+	dropped := sinkDroppedTotal.WithLabelValues("redis")
+	before := testutil.ToFloat64(dropped)
+
+	sink.Publish(NormalizedTrade{Provider: "mobula"})
+	sink.Publish(NormalizedTrade{Provider: "codex"})
+
+	if got := testutil.ToFloat64(dropped) - before; got != 1 {
+		t.Errorf("sink_dropped_total{sink=\"redis\"} grew by %v, want 1", got)
+	}
+	if got := (<-sink.queue).Provider; got != "mobula" {
+		t.Errorf("queued trade from %q, want the first one (mobula)", got)
+	}
+}
+
+func TestRedisSinkArgs(t *testing.T) {
+	sink := &RedisSink{key: "latency:measurements", maxLen: 1000}
+	onChain := time.UnixMilli(1700000000000)
+	args := sink.args(NormalizedTrade{
+		Provider:    "codex",
+		Chain:       "base",
+		Pool:        "0xpool",
+		TxHash:      "0xhash",
+		BlockNumber: 42,
+		OnChainTime: onChain,
+		ReceivedAt:  onChain.Add(1500 * time.Millisecond),
+		LagMs:       1500,
+		Region:      "eu",
+	})
+
+	if args.Stream != "latency:measurements" || args.MaxLen != 1000 || !args.Approx {
+		t.Errorf("XADD to %q MAXLEN %d (approx %v), want latency:measurements MAXLEN ~ 1000", args.Stream, args.MaxLen, args.Approx)
+	}
+	want := []string{
+		"provider", "codex",
+		"chain", "base",
+		"pool", "0xpool",
+		"tx_hash", "0xhash",
+		"block_number", "42",
+		"on_chain_ms", "1700000000000",
+		"received_ms", "1700000001500",
+		"lag_ms", "1500",
+		"region", "eu",
+	}
+	values, ok := args.Values.([]string)
+	if !ok || len(values) != len(want) {
+		t.Fatalf("XADD values = %v, want %v", args.Values, want)
+	}
+	for i := range want {
+		if values[i] != want[i] {
+			t.Errorf("XADD value %d = %q, want %q", i, values[i], want[i])
+		}
+	}
+}
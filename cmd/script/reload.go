@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Config Reload (SIGHUP)
+// Re-reads the env file and applies the settings that do not need a restart
+// ============================================================================
+
+// hotReloadKeys are applied to running monitors on SIGHUP; every other key needs a restart
+var hotReloadKeys = map[string]bool{
+	"REST_INTERVAL":   true,
+	"QUOTE_INTERVAL":  true,
+	"TOKENS":          true, // New tokens only, pools are never removed at runtime
+	"LOG_SAMPLE_RATE": true,
+	"DEBUG":           true,
+}
+
+var hotSettings struct {
+	sync.RWMutex
+	restInterval  time.Duration
+	quoteInterval time.Duration
+	logSampleRate int
+	debug         bool
+}
+
+// applyHotSettings publishes the hot-reloadable settings of config to the running monitors
+func applyHotSettings(config *Config) {
+	hotSettings.Lock()
+	defer hotSettings.Unlock()
+
+	hotSettings.restInterval = config.RESTInterval
+	hotSettings.quoteInterval = config.QuoteInterval
+	hotSettings.logSampleRate = config.LogSampleRate
+	hotSettings.debug = config.Debug
+}
+
+func currentRESTInterval() time.Duration {
+	hotSettings.RLock()
+	defer hotSettings.RUnlock()
+	return hotSettings.restInterval
+}
+
+func currentQuoteInterval() time.Duration {
+	hotSettings.RLock()
+	defer hotSettings.RUnlock()
+	return hotSettings.quoteInterval
+}
+
+func currentLogSampleRate() int {
+	hotSettings.RLock()
+	defer hotSettings.RUnlock()
+	return hotSettings.logSampleRate
+}
+
+func currentDebug() bool {
+	hotSettings.RLock()
+	defer hotSettings.RUnlock()
+	return hotSettings.debug
+}
+
+// reloadConfig re-reads the config and applies hot-reloadable settings, leaving connections intact.
+// It returns the config to compare the next reload against; on error the previous one is kept.
+func reloadConfig(envFile string, previous *Config) *Config {
+	fmt.Println("[RELOAD] SIGHUP received, reloading config...")
+
+	config, err := loadEnv(envFile)
+	if err != nil {
+		fmt.Printf("[RELOAD] Config not reloaded: %v\n", err)
+		return previous
+	}
+
+	applyHotSettings(config)
+	fmt.Printf("[RELOAD] REST interval: %v, quote interval: %v, log sample rate: 1/%d\n", config.RESTInterval, config.QuoteInterval, config.LogSampleRate)
+
+	// REST monitors pick up new pools on their next check, WebSocket monitors on their next reconnect
+	resolveTokenPools(config)
+	reportComparisonEligibility(config.MonitorRegion)
+
+	oldFields := previous.configFields()
+	for i, field := range config.configFields() {
+		if !hotReloadKeys[field.key] && *field.target != *oldFields[i].target {
+			fmt.Printf("[RELOAD] %s changed, restart required to apply it\n", field.key)
+		}
+	}
+
+	return config
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ============================================================================
+// replay
+// Feeds a CAPTURE_DIR file back through the head lag handlers, each frame at
+// its captured receive time, and prints the resulting head lag summary
+// ============================================================================
+
+// replayMaxFrameBytes bounds one capture line; Codex batches can run to a few hundred KB
+const replayMaxFrameBytes = 16 << 20
+
+// runReplay returns 0 once every frame of path was replayed, 1 if the file cannot be read
+func runReplay(envFile string, path string) int {
+	config, err := loadEnv(envFile)
+	if err != nil {
+		fmt.Printf("replay: %v\n", err)
+		return 1
+	}
+	applyHotSettings(config)
+
+	frames, err := readCapture(path)
+	if err != nil {
+		fmt.Printf("replay: %v\n", err)
+		return 1
+	}
+	if len(frames) == 0 {
+		fmt.Printf("replay: %s holds no frames\n", path)
+		return 1
+	}
+
+	// The window spans the whole capture, so no replayed trade expires before the summary
+	first, last := frames[0].ReceivedAt, frames[len(frames)-1].ReceivedAt
+	for _, frame := range frames {
+		if frame.ReceivedAt.Before(first) {
+			first = frame.ReceivedAt
+		}
+		if frame.ReceivedAt.After(last) {
+			last = frame.ReceivedAt
+		}
+	}
+	latencyWindow := NewLatencyWindow(last.Sub(first), config.OutlierPolicy, config.BaselineProvider, config.StatsMinSamples)
+
+	rec := NewPrometheusRecorder(config.EWMAAlpha, config.TradeTypeMetrics, config.PreciseLagMetrics)
+	rec.AddSink(latencyWindow)
+	defer rec.Close()
+
+	// Rebuild the subscription IDs the live feed would have used, so metadata frames are attributed
+	codex := newCodexFeed()
+	for i, pool := range monitoredPools() {
+		if !pool.SupportsCodex() {
+			continue
+		}
+		subID, metadataID := codexSubscriptionIDs(i)
+		codex.subscriptions[subID] = pool
+		if config.CodexMetadataUpdates {
+			codex.metadataSubscriptions[metadataID] = pool
+		}
+	}
+
+	skipped := make(map[string]int)
+	for _, frame := range frames {
+		message := []byte(frame.Frame)
+		switch frame.Provider {
+		case "mobula":
+			handleMobulaMessage(config, rec, message, frame.ReceivedAt)
+		case "codex":
+			codex.handleMessage(config, rec, message, frame.ReceivedAt)
+		case "geckoterminal":
+			handleGeckoMessage(config, rec, nil, message, frame.ReceivedAt)
+		default:
+			skipped[frame.Provider]++
+			continue
+		}
+		rec.RecordMessageReceived(frame.Provider, config.MonitorRegion)
+	}
+
+	fmt.Printf("Replayed %d frames from %s (%s to %s)\n", len(frames), path, first.Format(time.RFC3339), last.Format(time.RFC3339))
+	for provider, n := range skipped {
+		fmt.Printf("Skipped %d frames of unsupported provider %q\n", n, provider)
+	}
+	printWindowSummary(latencyWindow, latencyWindow.SnapshotAt(last))
+	return 0
+}
+
+// readCapture loads every frame of a capture file, in file order
+func readCapture(path string) ([]CapturedFrame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var frames []CapturedFrame
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), replayMaxFrameBytes)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var frame CapturedFrame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		frames = append(frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return frames, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Captured frames must come back byte for byte with their receive time, and replay to the
+// same metrics the live feed recorded
+func TestCaptureReplayRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if err := initFrameCapture(dir); err != nil {
+		t.Fatalf("initFrameCapture: %v", err)
+	}
+	t.Cleanup(closeFrameCapture)
+
+	receiveTime := time.UnixMilli(1700000001500).UTC()
+	frames := []string{
+		`{"type":"welcome"}`,
+		`{"identifier":"{\"channel\":\"SwapChannel\",\"pool_id\":\"24\"}","message":{"type":"newSwap","data":{"block_timestamp":1700000000000,"tx_hash":"0xabc","ty":"s"}}}`,
+		`not json`,
+	}
+	for _, frame := range frames {
+		captureFrame("geckoterminal", receiveTime, []byte(frame))
+	}
+	closeFrameCapture()
+
+	captured, err := readCapture(filepath.Join(dir, "geckoterminal.jsonl"))
+	if err != nil {
+		t.Fatalf("readCapture: %v", err)
+	}
+	if len(captured) != len(frames) {
+		t.Fatalf("readCapture returned %d frames, want %d", len(captured), len(frames))
+	}
+
+	rec := &fakeRecorder{}
+	for i, frame := range captured {
+		if frame.Provider != "geckoterminal" || frame.Frame != frames[i] || !frame.ReceivedAt.Equal(receiveTime) {
+			t.Errorf("frame %d = %+v, want %q received at %v", i, frame, frames[i], receiveTime)
+		}
+		handleGeckoMessage(&Config{MonitorRegion: "eu"}, rec, nil, []byte(frame.Frame), frame.ReceivedAt)
+	}
+
+	trades := rec.recordedTrades()
+	if len(trades) != 1 || trades[0].LagMs != 1500 || trades[0].TradeType != "sell" {
+		t.Errorf("replayed trades = %+v, want one 1500ms sell", trades)
+	}
+	if got := rec.calls("RecordParseFailure"); len(got) != 1 {
+		t.Errorf("RecordParseFailure calls = %q, want one for the non-JSON frame", got)
+	}
+}
+
+func TestCaptureDisabledWithoutDir(t *testing.T) {
+	if err := initFrameCapture(""); err != nil {
+		t.Fatalf("initFrameCapture: %v", err)
+	}
+	captureFrame("geckoterminal", time.Now(), []byte(`{"type":"ping"}`))
+
+	frameCapture.Lock()
+	defer frameCapture.Unlock()
+	if len(frameCapture.files) != 0 {
+		t.Errorf("capture opened %d files with CAPTURE_DIR unset", len(frameCapture.files))
+	}
+}
+
+func TestReadCaptureReportsBadLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "codex.jsonl")
+	content := `{"provider":"codex","received_at":"2023-11-14T22:13:20Z","frame":"{}"}` + "\n\n" + `{"provider":` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := readCapture(path)
+	if err == nil || !strings.Contains(err.Error(), path+":3:") {
+		t.Errorf("readCapture error = %v, want one naming line 3", err)
+	}
+}
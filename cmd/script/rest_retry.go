@@ -0,0 +1,28 @@
+package main
+
+import "time"
+
+// restRetryBackoff is the wait before the first REST retry, doubled for each further retry
+const restRetryBackoff = 250 * time.Millisecond
+
+// withRESTRetry makes up to retries extra attempts of a REST call while it fails transiently
+// (transport error or 5xx); 4xx responses are returned at once. It returns the outcome of
+// the last attempt with the latency of the whole call, retries and backoff included, and
+// stops retrying as soon as stopChan closes.
+func withRESTRetry(stopChan <-chan struct{}, retries int, call func() (float64, int, error)) (float64, int, error) {
+	start := time.Now()
+	backoff := restRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		latencyMs, statusCode, err := call()
+
+		transient := (err != nil && statusCode == 0) || statusCode >= 500
+		if !transient || attempt >= retries || !sleepOrStop(stopChan, backoff) {
+			if attempt > 0 {
+				latencyMs = float64(time.Since(start).Milliseconds())
+			}
+			return latencyMs, statusCode, err
+		}
+		backoff *= 2
+	}
+}
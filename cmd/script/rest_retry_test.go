@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithRESTRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		err          error
+		retries      int
+		wantAttempts int
+	}{
+		{"success", http.StatusOK, nil, 2, 1},
+		{"client error", http.StatusUnauthorized, nil, 2, 1},
+		{"server error", http.StatusBadGateway, nil, 2, 3},
+		{"transport error", 0, errors.New("connection reset"), 1, 2},
+		{"no retries", http.StatusBadGateway, nil, 0, 1},
+	}
+	for _, tt := range tests {
+		attempts := 0
+		_, status, _ := withRESTRetry(nil, tt.retries, func() (float64, int, error) {
+			attempts++
+			return 1, tt.status, tt.err
+		})
+		if attempts != tt.wantAttempts || status != tt.status {
+			t.Errorf("%s: %d attempts with status %d, want %d with %d", tt.name, attempts, status, tt.wantAttempts, tt.status)
+		}
+	}
+}
+
+// A shutdown during the retry backoff returns the failed attempt at once instead of sleeping on
+func TestWithRESTRetryStopsOnShutdown(t *testing.T) {
+	stop := make(chan struct{})
+	time.AfterFunc(50*time.Millisecond, func() { close(stop) })
+
+	attempts := 0
+	start := time.Now()
+	_, status, _ := withRESTRetry(stop, 20, func() (float64, int, error) {
+		attempts++
+		return 1, http.StatusServiceUnavailable, nil
+	})
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Errorf("withRESTRetry returned %v after starting, want under %v once stopped", elapsed, promptly)
+	}
+	if status != http.StatusServiceUnavailable || attempts > 2 {
+		t.Errorf("withRESTRetry = status %d after %d attempts, want the 503 within 2 attempts", status, attempts)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// REST Warm-Up
+// REST_WARMUP: one discarded request per endpoint before the measured checks, so
+// cold DNS, connect and TLS costs stay out of the latency distribution
+// ============================================================================
+
+// restWarmup decides when a REST monitor sends its warm-up requests: before the first
+// check cycle, then again once REST_WARMUP_INTERVAL has passed if it is set
+type restWarmup struct {
+	enabled  bool
+	interval time.Duration // 0: at startup only
+	last     time.Time
+}
+
+func newRESTWarmup(config *Config) *restWarmup {
+	return &restWarmup{enabled: config.RESTWarmup, interval: config.RESTWarmupInterval}
+}
+
+// due reports whether the next check cycle should be preceded by a warm-up, and if so
+// counts it as done at now
+func (w *restWarmup) due(now time.Time) bool {
+	if !w.enabled {
+		return false
+	}
+	if !w.last.IsZero() && (w.interval == 0 || now.Sub(w.last) < w.interval) {
+		return false
+	}
+	w.last = now
+	return true
+}
+
+// warmUpEndpoints calls each endpoint once and discards the outcome; nothing is recorded.
+// call returns false when the provider has no pool to send the endpoint's request for.
+func warmUpEndpoints(prefix string, endpoints []string, call func(endpoint string) bool) {
+	start := time.Now()
+	warmed := 0
+	for _, endpoint := range endpoints {
+		if call(endpoint) {
+			warmed++
+		}
+	}
+	fmt.Printf("%s Warm-up: %d endpoint(s) in %v, not recorded\n", prefix, warmed, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ============================================================================
+// selftest
+// Connects to every enabled provider once, the way the monitors do, and reports
+// whether it answered, how fast, and any auth error
+// ============================================================================
+
+const selfTestTimeout = 20 * time.Second
+
+// runSelfTest exercises each configured provider end-to-end and returns the process exit code:
+// non-zero if any enabled provider failed.
+func runSelfTest(envFile string) int {
+	fmt.Println("=== Provider Self-Test ===")
+	fmt.Println()
+
+	config, err := loadEnv(envFile)
+	if err != nil {
+		fmt.Printf("  ✗ %v\n", err)
+		return 1
+	}
+	initProviderURLs(config.ProviderURLs)
+
+	check := &configCheck{}
+
+	fmt.Println("Head lag feeds:")
+	if config.MobulaAPIKey == "" {
+		check.warn("Mobula WebSocket: skipped (MOBULA_API_KEY not set)")
+	} else {
+		selfTestMobulaWS(check, config)
+	}
+	if config.DefinedSessionCookie == "" {
+		check.warn("Codex WebSocket: skipped (DEFINED_SESSION_COOKIE not set)")
+	} else {
+		selfTestCodexWS(check, config)
+	}
+	selfTestGeckoWS(check)
+	fmt.Println()
+
+	fmt.Println("REST:")
+	if config.MobulaAPIKey == "" {
+		check.warn("Mobula REST: skipped (MOBULA_API_KEY not set)")
+	} else {
+		checkMobula(check, config)
+	}
+	fmt.Println()
+
+	fmt.Println("Quote APIs:")
+	selfTestQuotes(check, config)
+	fmt.Println()
+
+	if check.critical > 0 {
+		fmt.Printf("Self-test failed: %d provider check(s) failed, %d skipped\n", check.critical, check.warnings)
+		return 1
+	}
+	fmt.Printf("Self-test passed (%d skipped)\n", check.warnings)
+	return 0
+}
+
+// selfTestMobulaWS subscribes to fast-trade and waits for the first message, which is either a trade or an error
+func selfTestMobulaWS(check *configCheck, config *Config) {
+	start := time.Now()
+	conn, subscribed, err := dialMobulaFastTrade(context.Background(), config.MobulaAPIKey, monitoredPools())
+	if err != nil {
+		check.fail("Mobula WebSocket: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(selfTestTimeout))
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		check.fail("Mobula WebSocket: no message within %v after subscribing to %d pools (%s): %v", selfTestTimeout, len(subscribed), classifyWebSocketError(err), err)
+		return
+	}
+
+	var reply struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(message, &reply) == nil && reply.Error != "" {
+		check.fail("Mobula WebSocket: subscribe rejected: %s %s", reply.Error, reply.Message)
+		return
+	}
+	check.ok("Mobula WebSocket: first message in %dms | %d pools subscribed", time.Since(start).Milliseconds(), len(subscribed))
+}
+
+// selfTestCodexWS runs the Defined.fi JWT flow and the Codex connection_init handshake
+func selfTestCodexWS(check *configCheck, config *Config) {
+	start := time.Now()
+	conn, err := dialCodexWS(context.Background(), config.DefinedSessionCookie)
+	if err != nil {
+		check.fail("Codex WebSocket: %v", err)
+		return
+	}
+	conn.Close()
+
+	check.ok("Codex WebSocket: JWT issued and connection acknowledged in %dms", time.Since(start).Milliseconds())
+}
+
+// selfTestGeckoWS subscribes to the SwapChannel of the first GeckoTerminal pool and waits for the confirmation
+func selfTestGeckoWS(check *configCheck) {
+	pool := geckoTerminalPools[0]
+	headers := map[string][]string{
+		"Origin":     {geckoOrigin},
+		"User-Agent": {geckoUserAgent},
+	}
+
+	start := time.Now()
+	conn, _, err := websocket.DefaultDialer.Dial(providerURLs.GeckoTerminalWS, headers)
+	if err != nil {
+		check.fail("GeckoTerminal WebSocket: dial failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	subscribeToGeckoSwapChannel(newWSConn(conn), pool.PoolID, pool.Name)
+
+	conn.SetReadDeadline(time.Now().Add(selfTestTimeout))
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			check.fail("GeckoTerminal WebSocket: no subscription confirmation for %s (%s): %v", pool.Name, classifyWebSocketError(err), err)
+			return
+		}
+
+		var msg GeckoActionCableMessage
+		if err := json.Unmarshal(message, &msg); err != nil {
+			continue
+		}
+
+		switch msg.Type {
+		case "confirm_subscription":
+			check.ok("GeckoTerminal WebSocket: SwapChannel confirmed in %dms | %s", time.Since(start).Milliseconds(), pool.Name)
+			return
+		case "reject_subscription":
+			check.fail("GeckoTerminal WebSocket: SwapChannel subscription rejected for %s", pool.Name)
+			return
+		}
+	}
+}
+
+// selfTestQuotes requests one quote from each provider
+func selfTestQuotes(check *configCheck, config *Config) {
+	report := func(name string, latencyMs float64, statusCode int, err error) {
+		if err != nil || statusCode >= 400 {
+			check.fail("%s: quote failed (status %d, err: %v)", name, statusCode, err)
+			return
+		}
+		check.ok("%s: quote in %.0fms", name, latencyMs)
+	}
+
+	if config.MobulaAPIKey == "" {
+		check.warn("mobula: skipped (MOBULA_API_KEY not set)")
+	} else {
+		latencyMs, statusCode, err := callMobulaSwapQuoteAPI("solana", "solana", solanaConfig.TokenIn, solanaConfig.TokenOut, "100", config.MobulaAPIKey, config.QuoteSlippageBps)
+		report("mobula", latencyMs, statusCode, err)
+	}
+
+	latencyMs, statusCode, err := callJupiterPublicQuoteAPI(config.QuoteSlippageBps)
+	report("jupiter", latencyMs, statusCode, err)
+
+	chain := evmQuoteChains[0]
+	for _, provider := range []struct {
+		name string
+		call func(QuoteChainConfig) (float64, int, error)
+	}{
+		{"openocean", func(chain QuoteChainConfig) (float64, int, error) {
+			return callOpenOceanQuoteAPI(chain, config.QuoteSlippageBps)
+		}},
+		{"paraswap", callParaSwapQuoteAPI},
+		{"lifi", func(chain QuoteChainConfig) (float64, int, error) {
+			return callLifiQuoteAPI(chain, config.QuoteSlippageBps)
+		}},
+		{"kyberswap", callKyberSwapQuoteAPI},
+	} {
+		latencyMs, statusCode, err := provider.call(chain)
+		report(provider.name+" ("+chain.Name+")", latencyMs, statusCode, err)
+	}
+}
@@ -75,6 +75,7 @@ func RefreshSessionCookie() (string, error) {
 	fmt.Println("[SESSION-SCRAPER] Attempting to refresh Defined.fi session cookie...")
 
 	sessionCookie, err := ScrapeDefinedSessionCookie()
+	RecordSessionRefreshAttempt(err == nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to refresh session cookie: %w", err)
 	}
@@ -82,17 +83,23 @@ func RefreshSessionCookie() (string, error) {
 	// Update environment variable
 	os.Setenv("DEFINED_SESSION_COOKIE", sessionCookie)
 
+	scrapedCookieMu.Lock()
+	scrapedCookie = sessionCookie
+	scrapedCookieMu.Unlock()
+
 	fmt.Printf("[SESSION-SCRAPER] ✓ Session cookie refreshed successfully (length: %d)\n", len(sessionCookie))
 
 	return sessionCookie, nil
 }
 
-// InvalidateTokenCache forces a token refresh on next request
+// InvalidateTokenCache forces a token refresh on next request. That refresh, and every retry
+// until one succeeds, is counted in session_refresh_attempts_total.
 func InvalidateTokenCache() {
 	globalTokenCache.mu.Lock()
 	defer globalTokenCache.mu.Unlock()
 
 	globalTokenCache.token = ""
 	globalTokenCache.expiresAt = time.Time{}
+	globalTokenCache.invalidated = true
 	fmt.Println("[DEFINED-AUTH] Token cache invalidated")
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// ============================================================================
+// Shutdown helpers
+// Let reconnect backoffs, dials and token requests return as soon as stopChan closes
+// ============================================================================
+
+// stopContext returns a context that is cancelled when stopChan closes.
+// Calling cancel releases it early once the caller is done.
+func stopContext(stopChan <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// backoffOrStop is sleepOrStop for a feed's reconnect backoff: the time actually slept, up to
+// the stop on shutdown, is added to backoff_seconds_total since the feed records nothing meanwhile
+func backoffOrStop(rec Recorder, provider string, stopChan <-chan struct{}, d time.Duration, region string) bool {
+	start := time.Now()
+	completed := sleepOrStop(stopChan, d)
+	rec.RecordBackoff(provider, time.Since(start).Seconds(), region)
+	return completed
+}
+
+// sleepOrStop waits for d and returns false if stopChan closed first
+func sleepOrStop(stopChan <-chan struct{}, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-stopChan:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
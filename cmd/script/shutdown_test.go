@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// promptly is how soon a wait must end once stopChan closes
+const promptly = time.Second
+
+func TestSleepOrStop(t *testing.T) {
+	if !sleepOrStop(make(chan struct{}), 10*time.Millisecond) {
+		t.Error("sleepOrStop with an open stopChan = false, want true")
+	}
+
+	stop := make(chan struct{})
+	time.AfterFunc(50*time.Millisecond, func() { close(stop) })
+	start := time.Now()
+	if sleepOrStop(stop, 5*time.Minute) {
+		t.Error("sleepOrStop stopped mid-sleep = true, want false")
+	}
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Errorf("sleepOrStop returned %v after the stop, want under %v", elapsed, promptly)
+	}
+}
+
+func TestBackoffOrStopRecordsTimeSlept(t *testing.T) {
+	stop := make(chan struct{})
+	time.AfterFunc(50*time.Millisecond, func() { close(stop) })
+
+	rec := &fakeRecorder{}
+	start := time.Now()
+	if backoffOrStop(rec, "codex", stop, 5*time.Minute, "eu") {
+		t.Error("backoffOrStop stopped mid-backoff = true, want false")
+	}
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Errorf("backoffOrStop returned %v after the stop, want under %v", elapsed, promptly)
+	}
+	if calls := rec.calls("RecordBackoff"); len(calls) != 1 {
+		t.Errorf("RecordBackoff calls = %v, want one for the time actually slept", calls)
+	}
+}
+
+func TestStopContext(t *testing.T) {
+	stop := make(chan struct{})
+	ctx, cancel := stopContext(stop)
+	defer cancel()
+
+	close(stop)
+	select {
+	case <-ctx.Done():
+	case <-time.After(promptly):
+		t.Fatal("stopContext not cancelled after stopChan closed")
+	}
+}
+
+// The Codex monitor must exit promptly whether the stop lands during its JWT request or its
+// reconnect backoff, which starts at 30s
+func TestCodexMonitorStopsPromptly(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}{
+		{
+			name: "during token generation",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				hangUntilCancelled(r)
+			},
+		},
+		{
+			name: "during backoff",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested := make(chan struct{}, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case requested <- struct{}{}:
+				default:
+				}
+				tt.handler(w, r)
+			}))
+			defer server.Close()
+			withFreshTokenCache(t)
+			withProviderURL(t, &providerURLs.DefinedAPI, server.URL)
+
+			stop := make(chan struct{})
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go runCodexHeadLagMonitor(&Config{DefinedSessionCookie: "cookie", MonitorRegion: "eu"}, &fakeRecorder{}, stop, &wg)
+
+			select {
+			case <-requested:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the monitor never requested a JWT")
+			}
+			time.Sleep(100 * time.Millisecond) // Into the hung request or the backoff
+			close(stop)
+
+			if !waitOrTimeout(&wg, promptly) {
+				t.Fatalf("monitor still running %v after stopChan closed", promptly)
+			}
+		})
+	}
+}
+
+func TestGetDefinedJWTTokenHonorsContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hangUntilCancelled(r)
+	}))
+	defer server.Close()
+	withFreshTokenCache(t)
+	withProviderURL(t, &providerURLs.DefinedAPI, server.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := GetDefinedJWTToken(ctx, "cookie"); err == nil {
+		t.Error("GetDefinedJWTToken with a cancelled context: no error")
+	}
+	if elapsed := time.Since(start); elapsed > promptly {
+		t.Errorf("GetDefinedJWTToken returned after %v, want under %v", elapsed, promptly)
+	}
+}
+
+// hangUntilCancelled never answers r. Its body is drained first: the server only notices the
+// client giving up on a POST once it has read the body.
+func hangUntilCancelled(r *http.Request) {
+	io.Copy(io.Discard, r.Body)
+	<-r.Context().Done()
+}
+
+// waitOrTimeout reports whether wg finished within d
+func waitOrTimeout(wg *sync.WaitGroup, d time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(d):
+		return false
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NormalizedTrade is a single head-lag measurement in a provider-agnostic shape.
+// Every WebSocket monitor produces one per trade and hands it to the Recorder,
+// which forwards it to all configured sinks.
+type NormalizedTrade struct {
+	Provider    string    `json:"provider"`
+	Chain       string    `json:"chain"`
+	Pool        string    `json:"pool,omitempty"`
+	TxHash      string    `json:"tx_hash"`
+	BlockNumber int64     `json:"block_number,omitempty"`
+	OnChainTime time.Time `json:"on_chain_time"`
+	ReceivedAt  time.Time `json:"received_at"`
+	LagMs       int64     `json:"lag_ms"`
+	Region      string    `json:"region"`
+
+	// Full-precision lag, ReceivedAt minus OnChainTime; LagMs is this truncated to milliseconds
+	Lag time.Duration `json:"-"`
+
+	// confirmed or unconfirmed for providers that stream both (Codex), empty otherwise
+	Confirmation string `json:"confirmation,omitempty"`
+
+	// buy or sell when the provider reports the trade's direction, empty otherwise
+	TradeType string `json:"trade_type,omitempty"`
+}
+
+// normalizeTradeType maps a provider's trade direction to buy or sell, empty for anything else.
+// Every provider goes through it so the trade_type label means the same everywhere: Mobula sends
+// buy/sell, Codex Buy/Sell (eventDisplayType) and GeckoTerminal b/s (ty).
+func normalizeTradeType(direction string) string {
+	switch strings.ToLower(strings.TrimSpace(direction)) {
+	case "buy", "b":
+		return "buy"
+	case "sell", "s":
+		return "sell"
+	default:
+		return ""
+	}
+}
+
+// LagSeconds returns the measured lag in seconds, at full precision when Lag is set
+func (t NormalizedTrade) LagSeconds() float64 {
+	if t.Lag != 0 {
+		return t.Lag.Seconds()
+	}
+	return float64(t.LagMs) / 1000.0
+}
+
+// Sink receives every measured trade. Publish is called from the WebSocket
+// read loops, so implementations must never block on I/O.
+type Sink interface {
+	Publish(trade NormalizedTrade)
+	Close() error
+}
+
+// Prober is implemented by sinks whose backend can be checked without publishing
+type Prober interface {
+	Probe() error
+}
+
+// newSinks builds every sink enabled in config. On error, sinks built so far are closed.
+func newSinks(config *Config) ([]Sink, error) {
+	var sinks []Sink
+	fail := func(err error) ([]Sink, error) {
+		for _, sink := range sinks {
+			sink.Close()
+		}
+		return nil, err
+	}
+
+	if config.RedisURL != "" {
+		redisSink, err := NewRedisSink(config.RedisURL, config.RedisStreamKey, config.RedisStreamMaxLen)
+		if err != nil {
+			return fail(err)
+		}
+		sinks = append(sinks, redisSink)
+		fmt.Printf("Publishing measurements to Redis stream %q (MAXLEN ~%d)\n", config.RedisStreamKey, config.RedisStreamMaxLen)
+	}
+
+	if config.KafkaBrokers != "" {
+		kafkaSink, err := NewKafkaSink(config.KafkaBrokers, config.KafkaTopic)
+		if err != nil {
+			return fail(err)
+		}
+		sinks = append(sinks, kafkaSink)
+		fmt.Printf("Publishing measurements to Kafka topic %q (brokers: %s)\n", config.KafkaTopic, config.KafkaBrokers)
+	}
+
+	if config.CSVPath != "" {
+		csvSink, err := NewCSVSink(config.CSVPath, config.CSVMaxSizeMB, config.CSVRotation)
+		if err != nil {
+			return fail(err)
+		}
+		sinks = append(sinks, csvSink)
+		if config.CSVMaxSizeMB > 0 {
+			fmt.Printf("Appending measurements to %s (%s at %d MB)\n", config.CSVPath, config.CSVRotation, config.CSVMaxSizeMB)
+		} else {
+			fmt.Printf("Appending measurements to %s (no size limit)\n", config.CSVPath)
+		}
+	}
+
+	if config.GRPCPort != "" {
+		grpcServer, err := NewGRPCServer(":" + config.GRPCPort)
+		if err != nil {
+			return fail(err)
+		}
+		sinks = append(sinks, grpcServer)
+		fmt.Printf("Streaming measurements over gRPC on :%s\n", config.GRPCPort)
+	}
+
+	return sinks, nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestNormalizeTradeType(t *testing.T) {
+	tests := []struct {
+		direction string
+		want      string
+	}{
+		{"buy", "buy"},   // Mobula
+		{"Sell", "sell"}, // Codex eventDisplayType
+		{"b", "buy"},     // GeckoTerminal ty
+		{"s", "sell"},
+		{" BUY ", "buy"},
+		{"", ""},
+		{"swap", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeTradeType(tt.direction); got != tt.want {
+			t.Errorf("normalizeTradeType(%q) = %q, want %q", tt.direction, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"sort"
+)
+
+// ============================================================================
+// Comparison Matrix
+// Providers x {p50, p95, availability, last message age} per chain, served on
+// /stats for dashboards that render their own table
+// ============================================================================
+
+// MatrixCell is one provider on one chain. Latency fields are nil with fewer than STATS_MIN_SAMPLES
+// samples in the window; connection fields are nil when the provider has no subscribed pool on the chain.
+type MatrixCell struct {
+	Provider        string   `json:"provider"`
+	Samples         int      `json:"samples"` // Samples behind p50 and p95, outliers excluded
+	P50             *float64 `json:"p50_ms"`
+	P95             *float64 `json:"p95_ms"`
+	Availability    *float64 `json:"availability"`             // Share of subscribed pools that streamed since the feed connected
+	LastMessageAge  *float64 `json:"last_message_age_seconds"` // Since the provider's latest trade on the chain, or its subscription if none
+	SubscribedPools int      `json:"subscribed_pools"`
+}
+
+// ChainMatrix holds every provider seen on a chain from one region, sorted by name
+type ChainMatrix struct {
+	Chain     string       `json:"chain"`
+	Region    string       `json:"region"`
+	Providers []MatrixCell `json:"providers"`
+}
+
+// matrixRow identifies a row: providers are only compared from the same vantage point
+type matrixRow struct {
+	chain  string
+	region string
+}
+
+// comparisonMatrix joins the window stats with pool liveness into one cell per provider, chain and region
+func comparisonMatrix(stats []WindowStats, liveness []PoolLiveness) []ChainMatrix {
+	cells := make(map[matrixRow]map[string]*MatrixCell)
+	cell := func(row matrixRow, provider string) *MatrixCell {
+		if cells[row] == nil {
+			cells[row] = make(map[string]*MatrixCell)
+		}
+		c, ok := cells[row][provider]
+		if !ok {
+			c = &MatrixCell{Provider: provider}
+			cells[row][provider] = c
+		}
+		return c
+	}
+
+	for _, s := range stats {
+		c := cell(matrixRow{s.Chain, s.Region}, s.Provider)
+		c.Samples = s.Samples - s.Outliers
+		if !s.Insufficient {
+			c.P50, c.P95 = &s.P50, &s.P95
+		}
+	}
+
+	active := make(map[*MatrixCell]int)
+	for _, pool := range liveness {
+		c := cell(matrixRow{pool.Chain, pool.Region}, pool.Provider)
+		c.SubscribedPools++
+		if pool.Active {
+			active[c]++
+		}
+		age := pool.Silence.Seconds()
+		if c.LastMessageAge == nil || age < *c.LastMessageAge {
+			c.LastMessageAge = &age
+		}
+	}
+	for row := range cells {
+		for _, c := range cells[row] {
+			if c.SubscribedPools > 0 {
+				availability := float64(active[c]) / float64(c.SubscribedPools)
+				c.Availability = &availability
+			}
+		}
+	}
+
+	matrix := make([]ChainMatrix, 0, len(cells))
+	for key, providers := range cells {
+		row := ChainMatrix{Chain: key.chain, Region: key.region}
+		for _, c := range providers {
+			row.Providers = append(row.Providers, *c)
+		}
+		sort.Slice(row.Providers, func(i, j int) bool { return row.Providers[i].Provider < row.Providers[j].Provider })
+		matrix = append(matrix, row)
+	}
+	sort.Slice(matrix, func(i, j int) bool {
+		if matrix[i].Chain != matrix[j].Chain {
+			return matrix[i].Chain < matrix[j].Chain
+		}
+		return matrix[i].Region < matrix[j].Region
+	})
+	return matrix
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ============================================================================
+// Surface Attribution
+// Pairs each provider's streaming head lag (WebSocket) with its REST data
+// freshness (polling) per chain, since a provider can be fast on one surface
+// and slow on the other
+// ============================================================================
+
+// Surfaces a lag was measured on, the surface label of surface_lag_seconds
+const (
+	surfaceWebSocket = "websocket"
+	surfaceREST      = "rest"
+)
+
+// restFreshnessSample is the latest REST data freshness of one provider on one chain
+type restFreshnessSample struct {
+	endpoint  string
+	freshness float64 // Seconds
+	at        time.Time
+}
+
+var restFreshness = struct {
+	sync.Mutex
+	latest map[string]map[string]restFreshnessSample // provider -> chain -> latest sample
+}{
+	latest: make(map[string]map[string]restFreshnessSample),
+}
+
+// markRESTFreshness keeps the latest REST data freshness of a provider on a chain
+func markRESTFreshness(provider string, endpoint string, chain string, freshnessSeconds float64, at time.Time) {
+	restFreshness.Lock()
+	defer restFreshness.Unlock()
+	if restFreshness.latest[provider] == nil {
+		restFreshness.latest[provider] = make(map[string]restFreshnessSample)
+	}
+	restFreshness.latest[provider][chain] = restFreshnessSample{endpoint: endpoint, freshness: freshnessSeconds, at: at}
+}
+
+// SurfaceAttribution shows one provider's lag on a chain per access surface, null where
+// the surface was not measured within the window
+type SurfaceAttribution struct {
+	Provider string `json:"provider"`
+	Chain    string `json:"chain"`
+
+	// WebSocket: p50 head lag over the window, outliers left out as in the summary
+	WebSocketHeadLagP50 *float64 `json:"websocket_head_lag_p50_seconds"`
+	WebSocketSamples    int      `json:"websocket_samples"`
+
+	// REST: data freshness of the latest poll within the window, and the endpoint that measured it
+	RESTFreshness *float64 `json:"rest_freshness_seconds"`
+	RESTEndpoint  string   `json:"rest_endpoint,omitempty"`
+	RESTAge       *float64 `json:"rest_measured_seconds_ago"`
+}
+
+// surfaceAttribution joins the window stats with the REST freshness measured since now-window,
+// sorted by provider then chain
+func surfaceAttribution(stats []WindowStats, window time.Duration, now time.Time) []SurfaceAttribution {
+	byKey := make(map[[2]string]*SurfaceAttribution)
+	entry := func(provider string, chain string) *SurfaceAttribution {
+		key := [2]string{provider, chain}
+		a, ok := byKey[key]
+		if !ok {
+			a = &SurfaceAttribution{Provider: provider, Chain: chain}
+			byKey[key] = a
+		}
+		return a
+	}
+
+	for _, s := range stats {
+		a := entry(s.Provider, s.Chain)
+		a.WebSocketSamples = s.Samples - s.Outliers
+		if !s.Insufficient {
+			p50 := s.P50 / 1000
+			a.WebSocketHeadLagP50 = &p50
+		}
+	}
+
+	restFreshness.Lock()
+	for provider, chains := range restFreshness.latest {
+		for chain, sample := range chains {
+			age := now.Sub(sample.at)
+			if age > window {
+				continue
+			}
+			a := entry(provider, chain)
+			freshness, ageSeconds := sample.freshness, age.Seconds()
+			a.RESTFreshness, a.RESTEndpoint, a.RESTAge = &freshness, sample.endpoint, &ageSeconds
+		}
+	}
+	restFreshness.Unlock()
+
+	attribution := make([]SurfaceAttribution, 0, len(byKey))
+	for _, a := range byKey {
+		attribution = append(attribution, *a)
+	}
+	sort.Slice(attribution, func(i, j int) bool {
+		if attribution[i].Provider != attribution[j].Provider {
+			return attribution[i].Provider < attribution[j].Provider
+		}
+		return attribution[i].Chain < attribution[j].Chain
+	})
+	return attribution
+}
+
+// registerSurfaceAttribution exports surface_lag_seconds from the /stats window
+func registerSurfaceAttribution(window *LatencyWindow, region string) {
+	prometheus.MustRegister(surfaceAttributionCollector{window: window, region: region})
+}
+
+// surfaceAttributionCollector exports surface_lag_seconds from the window, evaluated on scrape
+type surfaceAttributionCollector struct {
+	window *LatencyWindow
+	region string
+}
+
+func (surfaceAttributionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- surfaceLag
+}
+
+func (c surfaceAttributionCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	for _, a := range surfaceAttribution(c.window.SnapshotAt(now), c.window.window, now) {
+		if a.WebSocketHeadLagP50 != nil {
+			ch <- prometheus.MustNewConstMetric(surfaceLag, prometheus.GaugeValue, *a.WebSocketHeadLagP50, a.Provider, a.Chain, surfaceWebSocket, c.region)
+		}
+		if a.RESTFreshness != nil {
+			ch <- prometheus.MustNewConstMetric(surfaceLag, prometheus.GaugeValue, *a.RESTFreshness, a.Provider, a.Chain, surfaceREST, c.region)
+		}
+	}
+}
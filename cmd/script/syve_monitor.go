@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// Syve Monitor
+// Polls Syve's DEX trade index for each pool and measures how long a swap takes
+// to show up, from its on-chain timestamp to the poll that first returns it
+// ============================================================================
+
+const (
+	// Newest trades asked for per poll; more new swaps than this between two polls are missed
+	syvePageSize = 20
+
+	// Transaction hashes remembered per pool to tell new swaps from ones already recorded
+	syveSeenLimit = 1000
+)
+
+// SyveDexTrade is one record of Syve's dex-trades index
+type SyveDexTrade struct {
+	Timestamp       int64  `json:"timestamp"` // Block time, unix seconds
+	TransactionHash string `json:"transaction_hash"`
+	BlockNumber     int64  `json:"block_number"`
+}
+
+// syveSupports reports whether Syve indexes the chain's DEX trades (Ethereum mainnet only)
+func syveSupports(chain ChainInfo) bool {
+	return chain.ChainName == ethereumChain.ChainName
+}
+
+// syvePoolState tracks the swaps already recorded for one pool
+type syvePoolState struct {
+	primed bool // The first poll only fills seen, its trades predate the monitor
+	seen   map[string]bool
+	order  []string
+}
+
+// markSeen remembers a transaction hash, forgetting the oldest past syveSeenLimit, and
+// reports whether it is new
+func (s *syvePoolState) markSeen(txHash string) bool {
+	if s.seen[txHash] {
+		return false
+	}
+	s.seen[txHash] = true
+	s.order = append(s.order, txHash)
+	if len(s.order) > syveSeenLimit {
+		delete(s.seen, s.order[0])
+		s.order = s.order[1:]
+	}
+	return true
+}
+
+// fetchSyveTrades returns the newest trades Syve has indexed for a pool
+func fetchSyveTrades(client *http.Client, apiKey string, poolAddress string) ([]SyveDexTrade, int, error) {
+	q := url.Values{}
+	q.Add("eq:pool_address", poolAddress)
+	q.Add("sort:timestamp", "desc")
+	q.Add("size", strconv.Itoa(syvePageSize))
+	q.Add("key", apiKey)
+
+	req, err := http.NewRequest("GET", providerURLs.SyveDexTrades+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var trades []SyveDexTrade
+	if err := json.Unmarshal(body, &trades); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return trades, resp.StatusCode, nil
+}
+
+// runSyveMonitor polls Syve every SYVE_POLL_INTERVAL until stopChan closes. A new swap's lag is
+// the time of the poll that first returns it minus its block time, so it carries up to one
+// poll interval of detection delay on top of Syve's own indexing lag.
+func runSyveMonitor(config *Config, rec Recorder, stopChan <-chan struct{}) {
+	fmt.Println("Starting Syve indexer monitor...")
+	fmt.Printf("   Polling %d pools every %v\n", len(monitoredPools()), config.SyvePollInterval)
+	logSkippedChains("[SYVE]", "syve", syveSupports)
+	fmt.Println()
+
+	if config.SyveAPIKey == "" {
+		rec.RecordProviderStatus("syve", "rest", skipReasonMissingSyveKey, config.MonitorRegion)
+		fmt.Println("SYVE_API_KEY not set in .env file. Skipping Syve monitor.")
+		return
+	}
+
+	var pools []MonitoredPool
+	for _, pool := range monitoredPools() {
+		if syveSupports(pool.ChainInfo) && chainEnabled("syve", pool.ChainName) {
+			pools = append(pools, pool)
+		}
+	}
+	if len(pools) == 0 {
+		rec.RecordProviderStatus("syve", "rest", skipReasonNoSupportedChain, config.MonitorRegion)
+		fmt.Println("No monitored pool on a chain Syve supports. Skipping Syve monitor.")
+		return
+	}
+	rec.RecordProviderStatus("syve", "rest", "", config.MonitorRegion)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	states := make(map[string]*syvePoolState, len(pools))
+	for _, pool := range pools {
+		states[pool.Address] = &syvePoolState{seen: make(map[string]bool)}
+	}
+
+	ticker := time.NewTicker(config.SyvePollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, pool := range pools {
+			pollSyvePool(config, rec, client, pool, states[pool.Address])
+		}
+
+		select {
+		case <-stopChan:
+			fmt.Println("Syve monitor stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollSyvePool records the lag of every swap the pool's newest page holds for the first time
+func pollSyvePool(config *Config, rec Recorder, client *http.Client, pool MonitoredPool, state *syvePoolState) {
+	trades, statusCode, err := fetchSyveTrades(client, config.SyveAPIKey, pool.Address)
+	pollTime := time.Now()
+	if err != nil {
+		errorType := "request_failed"
+		if statusCode != 0 {
+			errorType = fmt.Sprintf("http_%d", statusCode)
+		}
+		rec.RecordHeadLagError("syve", pool.ChainName, errorType, config.MonitorRegion)
+		return
+	}
+	rec.RecordMonitorSuccess("syve", "rest", config.MonitorRegion)
+
+	for _, trade := range trades {
+		if trade.TransactionHash == "" || !state.markSeen(trade.TransactionHash) || !state.primed {
+			continue
+		}
+
+		onChainTime := time.Unix(trade.Timestamp, 0)
+		lagMs := pollTime.Sub(onChainTime).Milliseconds()
+		lagSeconds := float64(lagMs) / 1000.0
+		rec.RecordHeadLag("syve", pool.ChainName, lagMs, lagSeconds, config.MonitorRegion)
+
+		if lagMs > 30000 || sampleTradeLog("syve") {
+			fmt.Printf("[HEAD-LAG][SYVE][%s][%s] Lag: %.2fs | Block: %d | Tx: %s\n",
+				pollTime.Format("15:04:05"), pool.ChainName, lagSeconds, trade.BlockNumber, trade.TransactionHash)
+		}
+	}
+	state.primed = true
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Token Resolver
+// Turns TOKENS entries into their highest-liquidity pool via Mobula
+// ============================================================================
+
+// TokenTarget is a token the user wants benchmarked, parsed from TOKENS
+type TokenTarget struct {
+	Chain   ChainInfo
+	Address string
+}
+
+type MobulaMarketPairsResponse struct {
+	Data struct {
+		Pairs []MobulaMarketPair `json:"pairs"`
+	} `json:"data"`
+}
+
+type MobulaMarketPair struct {
+	Address   string  `json:"address"`
+	Liquidity float64 `json:"liquidity"`
+	Token0    struct {
+		Symbol string `json:"symbol"`
+	} `json:"token0"`
+	Token1 struct {
+		Symbol string `json:"symbol"`
+	} `json:"token1"`
+}
+
+// parseTokenTargets parses TOKENS ("solana:<mint>,ethereum:<addr>") against the chain registry
+func parseTokenTargets(tokens string) ([]TokenTarget, error) {
+	var targets []TokenTarget
+	for _, entry := range strings.Split(tokens, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		chainName, address, ok := strings.Cut(entry, ":")
+		chainName, address = strings.TrimSpace(chainName), strings.TrimSpace(address)
+		if !ok || address == "" {
+			return nil, fmt.Errorf("invalid TOKENS entry %q: expected <chain>:<address>", entry)
+		}
+
+		chain, ok := lookupChain(strings.ToLower(chainName))
+		if !ok {
+			return nil, fmt.Errorf("invalid TOKENS entry %q: unsupported chain %q", entry, chainName)
+		}
+		if !chain.SupportsMobula() {
+			return nil, fmt.Errorf("invalid TOKENS entry %q: pools are resolved via Mobula, which does not cover %s", entry, chain.ChainName)
+		}
+
+		targets = append(targets, TokenTarget{Chain: chain, Address: address})
+	}
+	return targets, nil
+}
+
+// resolveTokenPools looks up the top pool of every token and adds it to the pool registry.
+// It must run before the monitors start. Tokens that cannot be resolved are skipped.
+func resolveTokenPools(config *Config) {
+	if len(config.Tokens) == 0 {
+		return
+	}
+
+	if config.MobulaAPIKey == "" {
+		fmt.Println("Warning: TOKENS set but MOBULA_API_KEY is not, skipping token pool resolution")
+		return
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	for _, token := range config.Tokens {
+		pool, err := resolveTopPool(client, config.MobulaAPIKey, token)
+		if err != nil {
+			fmt.Printf("[TOKENS][%s] Could not resolve %s: %v\n", token.Chain.ChainName, token.Address, err)
+			continue
+		}
+
+		if !registerPool(pool) {
+			fmt.Printf("[TOKENS][%s] %s resolves to %s, already monitored\n", token.Chain.ChainName, token.Address, pool.Address)
+			continue
+		}
+		fmt.Printf("[TOKENS][%s] %s -> %s (%s)\n", token.Chain.ChainName, token.Address, pool.Address, pool.Name)
+	}
+}
+
+// resolveTopPool returns the highest-liquidity pool Mobula knows for a token
+func resolveTopPool(client *http.Client, apiKey string, token TokenTarget) (MonitoredPool, error) {
+	req, err := http.NewRequest("GET", providerURLs.MobulaREST+"/api/1/market/pairs", nil)
+	if err != nil {
+		return MonitoredPool{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	q := req.URL.Query()
+	q.Add("asset", token.Address)
+	q.Add("blockchain", token.Chain.BlockchainID)
+	q.Add("sortBy", "liquidity")
+	req.URL.RawQuery = q.Encode()
+
+	req.Header.Set("Authorization", apiKey)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return MonitoredPool{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return MonitoredPool{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return MonitoredPool{}, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	var pairsResp MobulaMarketPairsResponse
+	if err := json.Unmarshal(body, &pairsResp); err != nil {
+		return MonitoredPool{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// Pick the deepest pool ourselves rather than trusting the sort order
+	var top *MobulaMarketPair
+	for i, pair := range pairsResp.Data.Pairs {
+		if pair.Address == "" {
+			continue
+		}
+		if top == nil || pair.Liquidity > top.Liquidity {
+			top = &pairsResp.Data.Pairs[i]
+		}
+	}
+	if top == nil {
+		return MonitoredPool{}, fmt.Errorf("no pools found")
+	}
+
+	name := fmt.Sprintf("%s/%s", top.Token0.Symbol, top.Token1.Symbol)
+	if top.Token0.Symbol == "" || top.Token1.Symbol == "" {
+		name = token.Address
+	}
+
+	return MonitoredPool{
+		Name:      name,
+		Address:   normalizePoolAddress(top.Address),
+		ChainInfo: token.Chain,
+	}, nil
+}
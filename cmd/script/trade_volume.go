@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Trade Volume Filter
+// MIN_TRADE_VOLUME_USD: dust trades are left out of the head lag metrics, as
+// providers may index them differently from economically significant swaps
+// ============================================================================
+
+// usdAmount is a trade's USD value as a provider sends it: a JSON number, a numeric string
+// (Codex) or nothing. A missing or malformed value leaves the volume unknown instead of
+// failing the whole frame.
+type usdAmount struct {
+	value float64
+	known bool
+}
+
+func (a *usdAmount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		return nil
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		*a = usdAmount{value: v, known: true}
+	}
+	return nil
+}
+
+// belowMinTradeVolume reports whether a trade is too small to record under MIN_TRADE_VOLUME_USD,
+// counting it in trades_below_min_volume_total if so. Trades of unknown volume are kept.
+func belowMinTradeVolume(config *Config, rec Recorder, provider string, chain string, volume usdAmount) bool {
+	if config.MinTradeVolumeUSD <= 0 || !volume.known || volume.value >= config.MinTradeVolumeUSD {
+		return false
+	}
+	rec.RecordTradeBelowMinVolume(provider, chain, config.MonitorRegion)
+	return true
+}
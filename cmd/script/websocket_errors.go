@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// websocketFeeds are the WebSocket monitors READ_DEADLINES can tune
+var websocketFeeds = []string{"mobula", "codex", "geckoterminal", "mobula-pulse", "codex-launchpad", "binance"}
+
+// maxKeepaliveInterval is how often a feed is pinged when its read deadline allows
+const maxKeepaliveInterval = 25 * time.Second
+
+// setReadDeadline arms the feed's read deadline before a read, or clears it for a feed without one
+func setReadDeadline(conn *websocket.Conn, deadline time.Duration) {
+	if deadline <= 0 {
+		conn.SetReadDeadline(time.Time{})
+		return
+	}
+	conn.SetReadDeadline(time.Now().Add(deadline))
+}
+
+// keepaliveInterval pings at least twice per read deadline, so the server's answer
+// keeps a quiet but alive connection from timing out
+func keepaliveInterval(deadline time.Duration) time.Duration {
+	if deadline <= 0 {
+		return maxKeepaliveInterval
+	}
+	return min(deadline/2, maxKeepaliveInterval)
+}
+
+// parseFailureSampleBytes is how much of an unparseable frame is logged under DEBUG
+const parseFailureSampleBytes = 200
+
+// recordParseFailure counts a frame a provider sent that we could not parse, so a change in
+// the provider's message shape shows up as errors rather than as a quiet feed. The frame is
+// only logged under DEBUG, truncated.
+func recordParseFailure(rec Recorder, provider string, frame []byte, err error, region string) {
+	rec.RecordParseFailure(provider, region)
+	if currentDebug() {
+		fmt.Printf("[DEBUG][%s] Unparseable frame (%v): %s\n", provider, err, safeTruncate(string(frame), parseFailureSampleBytes))
+	}
+}
+
+// WebSocket read error types for websocket_errors_total
+const (
+	wsErrorTimeout  = "timeout"  // Read deadline fired: the feed went quiet
+	wsErrorClosed   = "closed"   // Server closed the connection or it was reset
+	wsErrorProtocol = "protocol" // Bad frame or a close for a protocol violation
+	wsErrorOther    = "other"
+)
+
+// WebSocket write operations for websocket_write_errors_total
+const (
+	wsWriteInit      = "init"      // Connection handshake
+	wsWriteSubscribe = "subscribe" // Subscription request
+	wsWritePing      = "ping"      // Keepalive sent by us
+	wsWritePong      = "pong"      // Answer to a server ping
+)
+
+// webSocketWriteError is a failed write on a feed's connection, naming the operation
+type webSocketWriteError struct {
+	op  string
+	err error
+}
+
+func (e *webSocketWriteError) Error() string {
+	return fmt.Sprintf("%s write failed: %v", e.op, e.err)
+}
+
+func (e *webSocketWriteError) Unwrap() error {
+	return e.err
+}
+
+// wsConn is a feed connection whose writes go through writeWebSocket. gorilla/websocket allows one
+// writer at a time, and a feed can answer a server ping or send a keepalive while it is still subscribing.
+type wsConn struct {
+	*websocket.Conn
+	writeMu sync.Mutex
+}
+
+func newWSConn(conn *websocket.Conn) *wsConn {
+	return &wsConn{Conn: conn}
+}
+
+// writeWebSocket sends v as JSON. A failed write closes the connection, so the feed's read loop
+// fails and reconnects instead of keeping a connection that still reads but can no longer write.
+func writeWebSocket(conn *wsConn, op string, v interface{}) error {
+	conn.writeMu.Lock()
+	defer conn.writeMu.Unlock()
+
+	if err := conn.WriteJSON(v); err != nil {
+		conn.Close()
+		return &webSocketWriteError{op: op, err: err}
+	}
+	return nil
+}
+
+// recordWebSocketWriteError counts err in websocket_write_errors_total if a write caused it
+func recordWebSocketWriteError(rec Recorder, aggregator string, err error, region string) {
+	var writeErr *webSocketWriteError
+	if errors.As(err, &writeErr) {
+		rec.RecordWebSocketWriteError(aggregator, writeErr.op, region)
+	}
+}
+
+// classifyWebSocketError tells a quiet feed from a dropped connection from a bad frame
+func classifyWebSocketError(err error) string {
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		switch closeErr.Code {
+		case websocket.CloseProtocolError, websocket.CloseUnsupportedData,
+			websocket.CloseInvalidFramePayloadData, websocket.CloseMessageTooBig:
+			return wsErrorProtocol
+		default:
+			return wsErrorClosed
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return wsErrorTimeout
+	}
+
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return wsErrorClosed
+	}
+
+	// gorilla/websocket reports malformed frames as plain "websocket: ..." errors
+	if errors.Is(err, websocket.ErrReadLimit) || strings.HasPrefix(err.Error(), "websocket: ") {
+		return wsErrorProtocol
+	}
+
+	return wsErrorOther
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// A reader answering pings while another goroutine subscribes must not trip gorilla's
+// "concurrent write to websocket connection" panic
+func TestWriteWebSocketSerializesWriters(t *testing.T) {
+	const writers, writesEach = 8, 50
+	payload := map[string]string{"type": "pong", "padding": strings.Repeat("x", 64<<10)} // Large enough for writes to overlap
+
+	received := make(chan int, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		count := 0
+		for count < writers*writesEach {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				break
+			}
+			count++
+		}
+		received <- count
+	}))
+	defer server.Close()
+
+	raw, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	conn := newWSConn(raw)
+	defer conn.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < writesEach; j++ {
+				if err := writeWebSocket(conn, wsWritePong, payload); err != nil {
+					t.Errorf("writeWebSocket: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := <-received; got != writers*writesEach {
+		t.Errorf("server received %d frames, want %d", got, writers*writesEach)
+	}
+}
+
+func TestWriteWebSocketClosesOnFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer server.Close()
+
+	raw, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	conn := newWSConn(raw)
+	raw.Close()
+
+	err = writeWebSocket(conn, wsWriteSubscribe, map[string]string{"type": "subscribe"})
+	if err == nil {
+		t.Fatal("writeWebSocket on a closed connection: no error")
+	}
+
+	rec := &fakeRecorder{}
+	recordWebSocketWriteError(rec, "codex", err, "eu")
+	if got := rec.calls("RecordWebSocketWriteError"); len(got) != 1 || got[0] != "codex subscribe eu" {
+		t.Errorf("RecordWebSocketWriteError calls = %v, want [codex subscribe eu]", got)
+	}
+}
@@ -0,0 +1,467 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: proto/latency/v1/latency.proto
+
+package latencyv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StreamMeasurementsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only stream measurements from this provider (all providers when empty).
+	Provider string `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	// Only stream measurements on this chain (all chains when empty).
+	Chain         string `protobuf:"bytes,2,opt,name=chain,proto3" json:"chain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMeasurementsRequest) Reset() {
+	*x = StreamMeasurementsRequest{}
+	mi := &file_proto_latency_v1_latency_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMeasurementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMeasurementsRequest) ProtoMessage() {}
+
+func (x *StreamMeasurementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_latency_v1_latency_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMeasurementsRequest.ProtoReflect.Descriptor instead.
+func (*StreamMeasurementsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_latency_v1_latency_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamMeasurementsRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *StreamMeasurementsRequest) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+// Measurement is a single head-lag measurement for one trade.
+type Measurement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Chain         string                 `protobuf:"bytes,2,opt,name=chain,proto3" json:"chain,omitempty"`
+	Pool          string                 `protobuf:"bytes,3,opt,name=pool,proto3" json:"pool,omitempty"`
+	TxHash        string                 `protobuf:"bytes,4,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	BlockNumber   int64                  `protobuf:"varint,5,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	OnChainTime   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=on_chain_time,json=onChainTime,proto3" json:"on_chain_time,omitempty"`
+	ReceivedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+	LagMs         int64                  `protobuf:"varint,8,opt,name=lag_ms,json=lagMs,proto3" json:"lag_ms,omitempty"`
+	Region        string                 `protobuf:"bytes,9,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Measurement) Reset() {
+	*x = Measurement{}
+	mi := &file_proto_latency_v1_latency_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Measurement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Measurement) ProtoMessage() {}
+
+func (x *Measurement) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_latency_v1_latency_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Measurement.ProtoReflect.Descriptor instead.
+func (*Measurement) Descriptor() ([]byte, []int) {
+	return file_proto_latency_v1_latency_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Measurement) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Measurement) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+func (x *Measurement) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *Measurement) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *Measurement) GetBlockNumber() int64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *Measurement) GetOnChainTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OnChainTime
+	}
+	return nil
+}
+
+func (x *Measurement) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *Measurement) GetLagMs() int64 {
+	if x != nil {
+		return x.LagMs
+	}
+	return 0
+}
+
+func (x *Measurement) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_proto_latency_v1_latency_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_latency_v1_latency_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_latency_v1_latency_proto_rawDescGZIP(), []int{2}
+}
+
+type GetStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stats         []*ProviderChainStats  `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_proto_latency_v1_latency_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_latency_v1_latency_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_latency_v1_latency_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetStatsResponse) GetStats() []*ProviderChainStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+// ProviderChainStats aggregates the measurements of one provider on one chain.
+type ProviderChainStats struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Provider       string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Chain          string                 `protobuf:"bytes,2,opt,name=chain,proto3" json:"chain,omitempty"`
+	Count          int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	LastLagMs      int64                  `protobuf:"varint,4,opt,name=last_lag_ms,json=lastLagMs,proto3" json:"last_lag_ms,omitempty"`
+	AvgLagMs       float64                `protobuf:"fixed64,5,opt,name=avg_lag_ms,json=avgLagMs,proto3" json:"avg_lag_ms,omitempty"`
+	MinLagMs       int64                  `protobuf:"varint,6,opt,name=min_lag_ms,json=minLagMs,proto3" json:"min_lag_ms,omitempty"`
+	MaxLagMs       int64                  `protobuf:"varint,7,opt,name=max_lag_ms,json=maxLagMs,proto3" json:"max_lag_ms,omitempty"`
+	LastReceivedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_received_at,json=lastReceivedAt,proto3" json:"last_received_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ProviderChainStats) Reset() {
+	*x = ProviderChainStats{}
+	mi := &file_proto_latency_v1_latency_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderChainStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderChainStats) ProtoMessage() {}
+
+func (x *ProviderChainStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_latency_v1_latency_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderChainStats.ProtoReflect.Descriptor instead.
+func (*ProviderChainStats) Descriptor() ([]byte, []int) {
+	return file_proto_latency_v1_latency_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ProviderChainStats) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ProviderChainStats) GetChain() string {
+	if x != nil {
+		return x.Chain
+	}
+	return ""
+}
+
+func (x *ProviderChainStats) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ProviderChainStats) GetLastLagMs() int64 {
+	if x != nil {
+		return x.LastLagMs
+	}
+	return 0
+}
+
+func (x *ProviderChainStats) GetAvgLagMs() float64 {
+	if x != nil {
+		return x.AvgLagMs
+	}
+	return 0
+}
+
+func (x *ProviderChainStats) GetMinLagMs() int64 {
+	if x != nil {
+		return x.MinLagMs
+	}
+	return 0
+}
+
+func (x *ProviderChainStats) GetMaxLagMs() int64 {
+	if x != nil {
+		return x.MaxLagMs
+	}
+	return 0
+}
+
+func (x *ProviderChainStats) GetLastReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastReceivedAt
+	}
+	return nil
+}
+
+var File_proto_latency_v1_latency_proto protoreflect.FileDescriptor
+
+const file_proto_latency_v1_latency_proto_rawDesc = "" +
+	"\n" +
+	"\x1eproto/latency/v1/latency.proto\x12\n" +
+	"latency.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"M\n" +
+	"\x19StreamMeasurementsRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05chain\x18\x02 \x01(\tR\x05chain\"\xbb\x02\n" +
+	"\vMeasurement\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05chain\x18\x02 \x01(\tR\x05chain\x12\x12\n" +
+	"\x04pool\x18\x03 \x01(\tR\x04pool\x12\x17\n" +
+	"\atx_hash\x18\x04 \x01(\tR\x06txHash\x12!\n" +
+	"\fblock_number\x18\x05 \x01(\x03R\vblockNumber\x12>\n" +
+	"\ron_chain_time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vonChainTime\x12;\n" +
+	"\vreceived_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"receivedAt\x12\x15\n" +
+	"\x06lag_ms\x18\b \x01(\x03R\x05lagMs\x12\x16\n" +
+	"\x06region\x18\t \x01(\tR\x06region\"\x11\n" +
+	"\x0fGetStatsRequest\"H\n" +
+	"\x10GetStatsResponse\x124\n" +
+	"\x05stats\x18\x01 \x03(\v2\x1e.latency.v1.ProviderChainStatsR\x05stats\"\x9c\x02\n" +
+	"\x12ProviderChainStats\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05chain\x18\x02 \x01(\tR\x05chain\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\x12\x1e\n" +
+	"\vlast_lag_ms\x18\x04 \x01(\x03R\tlastLagMs\x12\x1c\n" +
+	"\n" +
+	"avg_lag_ms\x18\x05 \x01(\x01R\bavgLagMs\x12\x1c\n" +
+	"\n" +
+	"min_lag_ms\x18\x06 \x01(\x03R\bminLagMs\x12\x1c\n" +
+	"\n" +
+	"max_lag_ms\x18\a \x01(\x03R\bmaxLagMs\x12D\n" +
+	"\x10last_received_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x0elastReceivedAt2\xaf\x01\n" +
+	"\x0eLatencyService\x12V\n" +
+	"\x12StreamMeasurements\x12%.latency.v1.StreamMeasurementsRequest\x1a\x17.latency.v1.Measurement0\x01\x12E\n" +
+	"\bGetStats\x12\x1b.latency.v1.GetStatsRequest\x1a\x1c.latency.v1.GetStatsResponseB6Z4mobula_latency_competitor/proto/latency/v1;latencyv1b\x06proto3"
+
+var (
+	file_proto_latency_v1_latency_proto_rawDescOnce sync.Once
+	file_proto_latency_v1_latency_proto_rawDescData []byte
+)
+
+func file_proto_latency_v1_latency_proto_rawDescGZIP() []byte {
+	file_proto_latency_v1_latency_proto_rawDescOnce.Do(func() {
+		file_proto_latency_v1_latency_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_latency_v1_latency_proto_rawDesc), len(file_proto_latency_v1_latency_proto_rawDesc)))
+	})
+	return file_proto_latency_v1_latency_proto_rawDescData
+}
+
+var file_proto_latency_v1_latency_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_proto_latency_v1_latency_proto_goTypes = []any{
+	(*StreamMeasurementsRequest)(nil), // 0: latency.v1.StreamMeasurementsRequest
+	(*Measurement)(nil),               // 1: latency.v1.Measurement
+	(*GetStatsRequest)(nil),           // 2: latency.v1.GetStatsRequest
+	(*GetStatsResponse)(nil),          // 3: latency.v1.GetStatsResponse
+	(*ProviderChainStats)(nil),        // 4: latency.v1.ProviderChainStats
+	(*timestamppb.Timestamp)(nil),     // 5: google.protobuf.Timestamp
+}
+var file_proto_latency_v1_latency_proto_depIdxs = []int32{
+	5, // 0: latency.v1.Measurement.on_chain_time:type_name -> google.protobuf.Timestamp
+	5, // 1: latency.v1.Measurement.received_at:type_name -> google.protobuf.Timestamp
+	4, // 2: latency.v1.GetStatsResponse.stats:type_name -> latency.v1.ProviderChainStats
+	5, // 3: latency.v1.ProviderChainStats.last_received_at:type_name -> google.protobuf.Timestamp
+	0, // 4: latency.v1.LatencyService.StreamMeasurements:input_type -> latency.v1.StreamMeasurementsRequest
+	2, // 5: latency.v1.LatencyService.GetStats:input_type -> latency.v1.GetStatsRequest
+	1, // 6: latency.v1.LatencyService.StreamMeasurements:output_type -> latency.v1.Measurement
+	3, // 7: latency.v1.LatencyService.GetStats:output_type -> latency.v1.GetStatsResponse
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_proto_latency_v1_latency_proto_init() }
+func file_proto_latency_v1_latency_proto_init() {
+	if File_proto_latency_v1_latency_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_latency_v1_latency_proto_rawDesc), len(file_proto_latency_v1_latency_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_latency_v1_latency_proto_goTypes,
+		DependencyIndexes: file_proto_latency_v1_latency_proto_depIdxs,
+		MessageInfos:      file_proto_latency_v1_latency_proto_msgTypes,
+	}.Build()
+	File_proto_latency_v1_latency_proto = out.File
+	file_proto_latency_v1_latency_proto_goTypes = nil
+	file_proto_latency_v1_latency_proto_depIdxs = nil
+}
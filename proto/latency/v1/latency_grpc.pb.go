@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proto/latency/v1/latency.proto
+
+package latencyv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LatencyService_StreamMeasurements_FullMethodName = "/latency.v1.LatencyService/StreamMeasurements"
+	LatencyService_GetStats_FullMethodName           = "/latency.v1.LatencyService/GetStats"
+)
+
+// LatencyServiceClient is the client API for LatencyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LatencyService streams live head-lag measurements to internal consumers.
+type LatencyServiceClient interface {
+	// StreamMeasurements emits every measurement as it is recorded, until the client disconnects.
+	StreamMeasurements(ctx context.Context, in *StreamMeasurementsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Measurement], error)
+	// GetStats returns aggregates over every measurement recorded since startup.
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+}
+
+type latencyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLatencyServiceClient(cc grpc.ClientConnInterface) LatencyServiceClient {
+	return &latencyServiceClient{cc}
+}
+
+func (c *latencyServiceClient) StreamMeasurements(ctx context.Context, in *StreamMeasurementsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Measurement], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LatencyService_ServiceDesc.Streams[0], LatencyService_StreamMeasurements_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamMeasurementsRequest, Measurement]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LatencyService_StreamMeasurementsClient = grpc.ServerStreamingClient[Measurement]
+
+func (c *latencyServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatsResponse)
+	err := c.cc.Invoke(ctx, LatencyService_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LatencyServiceServer is the server API for LatencyService service.
+// All implementations must embed UnimplementedLatencyServiceServer
+// for forward compatibility.
+//
+// LatencyService streams live head-lag measurements to internal consumers.
+type LatencyServiceServer interface {
+	// StreamMeasurements emits every measurement as it is recorded, until the client disconnects.
+	StreamMeasurements(*StreamMeasurementsRequest, grpc.ServerStreamingServer[Measurement]) error
+	// GetStats returns aggregates over every measurement recorded since startup.
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	mustEmbedUnimplementedLatencyServiceServer()
+}
+
+// UnimplementedLatencyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLatencyServiceServer struct{}
+
+func (UnimplementedLatencyServiceServer) StreamMeasurements(*StreamMeasurementsRequest, grpc.ServerStreamingServer[Measurement]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamMeasurements not implemented")
+}
+func (UnimplementedLatencyServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedLatencyServiceServer) mustEmbedUnimplementedLatencyServiceServer() {}
+func (UnimplementedLatencyServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeLatencyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LatencyServiceServer will
+// result in compilation errors.
+type UnsafeLatencyServiceServer interface {
+	mustEmbedUnimplementedLatencyServiceServer()
+}
+
+func RegisterLatencyServiceServer(s grpc.ServiceRegistrar, srv LatencyServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLatencyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LatencyService_ServiceDesc, srv)
+}
+
+func _LatencyService_StreamMeasurements_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamMeasurementsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LatencyServiceServer).StreamMeasurements(m, &grpc.GenericServerStream[StreamMeasurementsRequest, Measurement]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LatencyService_StreamMeasurementsServer = grpc.ServerStreamingServer[Measurement]
+
+func _LatencyService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LatencyServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LatencyService_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LatencyServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LatencyService_ServiceDesc is the grpc.ServiceDesc for LatencyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LatencyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "latency.v1.LatencyService",
+	HandlerType: (*LatencyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStats",
+			Handler:    _LatencyService_GetStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamMeasurements",
+			Handler:       _LatencyService_StreamMeasurements_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/latency/v1/latency.proto",
+}